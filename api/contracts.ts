@@ -45,26 +45,271 @@ export const CardResponseSchema = z.object({
 });
 export type CardResponse = z.infer<typeof CardResponseSchema>;
 
-// GET /api/v1/cards/:id/render - Stream card image (PNG/SVG)
+// GET /api/v1/cards/:id/render - Stream card image (PNG, JPEG or lossless WebP; SVG is not implemented yet).
+// Query values override the configured output; a single dimension keeps the card's proportions. Served from the render cache when unchanged
 export const RenderCardRequestSchema = z.object({
   params: z.object({ id: z.string().uuid() }),
   query: z.object({
-    format: z.enum(["png", "svg"]).default("png"),
+    format: z.enum(["png", "jpeg", "jpg", "webp", "svg"]).optional(),
+    width: z.coerce.number().int().positive().optional(),
+    height: z.coerce.number().int().positive().optional(),
+    quality: z.coerce.number().int().min(1).max(100).optional(),
+    dpi: z.coerce.number().int().positive().optional(),
   }),
 });
 
+// POST /api/v1/cards/render/batch - Render up to 50 cards concurrently; responds with application/zip
+// holding one image per rendered card in the configured format and a manifest.json matching BatchRenderManifestSchema
+export const BatchRenderRequestSchema = z.object({
+  cards: z.array(CardDataSchema).min(1).max(50),
+});
+export type BatchRenderRequest = z.infer<typeof BatchRenderRequestSchema>;
+
+// In request order; a card has a file when it rendered and an error when it failed
+export const BatchRenderManifestSchema = z.array(
+  z.object({
+    name: z.string(),
+    file: z.string().optional(),
+    error: z.string().optional(),
+  }),
+);
+export type BatchRenderManifest = z.infer<typeof BatchRenderManifestSchema>;
+
 // POST /api/v1/cards/analyze - Analyze card for tags and metadata
 export const AnalyzeCardRequestSchema = CardDataSchema;
 export type AnalyzeCardRequest = z.infer<typeof AnalyzeCardRequestSchema>;
 
+export const LintIssueSchema = z.object({
+  rule: z.string(),
+  message: z.string(),
+  text: z.string(),
+  suggestion: z.string().optional(),
+  fix: z
+    .object({
+      start: z.number().int(),
+      end: z.number().int(),
+      replacement: z.string(),
+    })
+    .optional(),
+});
+export type LintIssue = z.infer<typeof LintIssueSchema>;
+
+export const LintResultSchema = z.object({
+  card_name: z.string(),
+  effect: z.string(),
+  issues: z.array(LintIssueSchema),
+  fixed_effect: z.string(),
+});
+export type LintResult = z.infer<typeof LintResultSchema>;
+
+// Returned per tag when the request sets ?explain=true
+export const TagExplanationSchema = z.object({
+  tag: z.object({
+    name: z.string(),
+    category: z.string(),
+    weight: z.number().int().optional(),
+  }),
+  source: z.enum(["basic", "tribal", "combo", "rule", "model", "manual"]),
+  rule: z.string().optional(),
+  description: z.string().optional(),
+  matches: z
+    .array(
+      z.object({
+        pattern: z.string(),
+        pattern_type: z.string(),
+        start: z.number().int(),
+        end: z.number().int(),
+        text: z.string(),
+      }),
+    )
+    .optional(),
+  conditions: z
+    .array(z.object({ type: z.string(), value: z.unknown() }))
+    .optional(),
+});
+export type TagExplanation = z.infer<typeof TagExplanationSchema>;
+
 export const AnalyzeCardResponseSchema = z.object({
+  lint: LintResultSchema.optional(),
+  explanations: z.array(TagExplanationSchema).optional(),
   tags: z.array(z.string()),
+  // One point per stored card the card interacts with, capped at 10
   synergyScore: z.number().min(0).max(10),
   tribalTags: z.array(z.string()),
+  // Present when model-assisted effect analysis (LLM_BASE_URL) is configured
+  summary: z.string().optional(),
+  // Effect complexity; commons over the style guide's budget get a "complexity" lint issue
+  complexity: z.object({
+    clauses: z.number().int(),
+    conditionals: z.number().int(),
+    targets: z.number().int(),
+    choices: z.number().int(),
+    keywords: z.number().int(),
+    numbers: z.number().int(),
+    words: z.number().int(),
+    score: z.number().int(),
+  }),
   metadata: z.record(z.string()),
 });
 export type AnalyzeCardResponse = z.infer<typeof AnalyzeCardResponseSchema>;
 
+// A tag stored for a card, either generated by the tag rules or added manually
+export const CardTagSchema = z.object({
+  card_id: z.string(),
+  name: z.string(),
+  category: z.string(),
+  weight: z.number().int().optional(),
+  source: z.enum(["auto", "manual"]),
+  rule: z.string().optional(),
+  rule_version: z.string().optional(),
+  created_at: z.string().datetime(),
+});
+export type CardTag = z.infer<typeof CardTagSchema>;
+
+// GET /api/v1/cards/:id/tags - List stored auto and manual tags of a card
+export const CardTagsResponseSchema = z.object({
+  tags: z.array(CardTagSchema),
+});
+export type CardTagsResponse = z.infer<typeof CardTagsResponseSchema>;
+
+// POST /api/v1/cards/:id/tags - Add a manual tag to a card
+export const AddCardTagRequestSchema = z.object({
+  name: z.string().min(1).max(100),
+  category: z.enum([
+    "TRIBAL",
+    "MECHANIC",
+    "STRATEGY",
+    "COST",
+    "SYNERGY",
+    "COMBO",
+    "ARCHETYPE",
+    "TIMING",
+  ]),
+  weight: z.number().int().min(0).optional(),
+});
+export type AddCardTagRequest = z.infer<typeof AddCardTagRequestSchema>;
+
+// GET /api/v1/tags/cards?all=&any=&none=&source= - Find cards by tag; parent tags match their descendants
+export const FindCardsByTagsRequestSchema = z.object({
+  query: z.object({
+    all: z.string().optional(),
+    any: z.string().optional(),
+    none: z.string().optional(),
+    source: z.enum(["auto", "manual"]).optional(),
+  }),
+});
+
+export const FindCardsByTagsResponseSchema = z.object({
+  cards: z.array(z.string()),
+});
+export type FindCardsByTagsResponse = z.infer<typeof FindCardsByTagsResponseSchema>;
+
+// GET /api/v1/tags/taxonomy - Tag hierarchy with synonyms, grouped under root tags
+export type TaxonomyNode = {
+  name: string;
+  category: AddCardTagRequest["category"];
+  parent?: string;
+  synonyms?: string[];
+  description?: string;
+  children?: TaxonomyNode[];
+};
+
+export const TaxonomyNodeSchema: z.ZodType<TaxonomyNode> = z.lazy(() =>
+  z.object({
+    name: z.string(),
+    category: AddCardTagRequestSchema.shape.category,
+    parent: z.string().optional(),
+    synonyms: z.array(z.string()).optional(),
+    description: z.string().optional(),
+    children: z.array(TaxonomyNodeSchema).optional(),
+  }),
+);
+
+export const TaxonomyResponseSchema = z.object({
+  tags: z.array(TaxonomyNodeSchema),
+});
+export type TaxonomyResponse = z.infer<typeof TaxonomyResponseSchema>;
+
+// POST /api/v1/admin/retag?force= - Regenerate auto tags created by older rule versions
+export const RetagResponseSchema = z.object({
+  rules_version: z.string(),
+  checked: z.number().int(),
+  retagged: z.number().int(),
+  unchanged: z.number().int(),
+});
+export type RetagResponse = z.infer<typeof RetagResponseSchema>;
+
+// POST /api/v1/decks/archetype - Classify a deck or card pool; repeat a card once per copy
+export const ClassifyArchetypeRequestSchema = z.object({
+  cards: z.array(CardDataSchema).min(1),
+});
+export type ClassifyArchetypeRequest = z.infer<typeof ClassifyArchetypeRequestSchema>;
+
+export const ArchetypeSchema = z.enum(["aggro", "control", "midrange", "combo"]);
+
+export const ArchetypeResultSchema = z.object({
+  archetype: ArchetypeSchema,
+  // Sorted by probability, highest first
+  probabilities: z.array(
+    z.object({
+      archetype: ArchetypeSchema,
+      probability: z.number().min(0).max(1),
+      score: z.number(),
+      explanations: z.array(
+        z.object({
+          feature: z.string(),
+          share: z.number().min(0).max(1),
+          contribution: z.number(),
+          reason: z.string(),
+        }),
+      ),
+    }),
+  ),
+  features: z.object({
+    cards: z.number().int(),
+    average_cost: z.number(),
+    shares: z.record(z.number()),
+  }),
+});
+export type ArchetypeResult = z.infer<typeof ArchetypeResultSchema>;
+
+// GET /api/v1/cards/duplicates - Near-duplicate clusters across the card pool
+export const GetDuplicatesRequestSchema = z.object({
+  query: z.object({
+    threshold: z.coerce.number().min(0.5).max(1).optional(),
+    // Maximum pairs returned, highest scores first (default 100)
+    limit: z.coerce.number().int().min(1).max(1000).optional(),
+  }),
+});
+
+export const SimilarCardSchema = z.object({
+  id: z.string().optional(),
+  name: z.string(),
+  type: z.string(),
+});
+
+export const DuplicatesResponseSchema = z.object({
+  threshold: z.number(),
+  // True when pairs beyond the limit were left out; clusters always cover every pair
+  truncated: z.boolean(),
+  pairs: z.array(
+    z.object({
+      a: SimilarCardSchema,
+      b: SimilarCardSchema,
+      text_score: z.number(),
+      stat_score: z.number(),
+      score: z.number(),
+    }),
+  ),
+  clusters: z.array(
+    z.object({
+      members: z.array(SimilarCardSchema),
+      max_score: z.number(),
+    }),
+  ),
+});
+export type DuplicatesResponse = z.infer<typeof DuplicatesResponseSchema>;
+
 // POST /api/v1/import/csv - Import cards from CSV (async job)
 export const ImportCsvRequestSchema = z.object({
   file: z.instanceof(File), // Handled as multipart/form-data
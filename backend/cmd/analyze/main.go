@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/batch"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+	"github.com/ControlYourPotatoes/card-generator/backend/pkg/bootstrap"
+)
+
+func main() {
+	// Command line flags
+	inputFile := flag.String("input", "", "Optional CSV file to load into the store before analyzing")
+	cardType := flag.String("type", "creature", "Type of cards in the input file (creature, spell, artifact, incantation, anthem)")
+	format := flag.String("format", "json", "Per-card results format (json, csv)")
+	outputFile := flag.String("output", "", "Output file for per-card results (defaults to stdout)")
+	statsJSON := flag.Bool("stats-json", false, "Print the tag statistics as JSON")
+	workers := flag.Int("workers", 0, "Number of concurrent workers (defaults to one per CPU)")
+	rulesPath := flag.String("rules", "", "Optional tag rule file or directory merged with the built-in rules")
+	env := flag.String("env", "development", "Environment (development, production, test)")
+	flag.Parse()
+
+	resultsFormat, err := batch.ParseFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	// Initialize application with DI
+	app, err := bootstrap.NewApplication(*env)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+	defer func() {
+		if err := app.Shutdown(); err != nil {
+			log.Printf("Error during shutdown: %v", err)
+		}
+	}()
+
+	cardStore, err := app.GetCardStore()
+	if err != nil {
+		log.Fatalf("Failed to get card store: %v", err)
+	}
+
+	if *inputFile != "" {
+		if err := loadCards(app, cardStore, *inputFile, *cardType); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	cardTagger, err := app.NewCardTagger()
+	if err != nil {
+		log.Fatalf("Failed to create card tagger: %v", err)
+	}
+	if *rulesPath != "" {
+		if err := cardTagger.LoadRules(*rulesPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	results, err := batch.NewAnalyzer(cardTagger, *workers).AnalyzeStore(ctx, cardStore)
+	if err != nil {
+		if results == nil {
+			log.Fatalf("Failed to analyze cards: %v", err)
+		}
+		log.Printf("Analysis interrupted, writing partial results: %v", err)
+	}
+
+	// Statistics go to stdout unless the per-card results do
+	var out io.Writer = os.Stdout
+	var statsOut io.Writer = os.Stderr
+	if *outputFile != "" {
+		if err := os.MkdirAll(filepath.Dir(*outputFile), 0755); err != nil {
+			log.Fatalf("Failed to create output directory: %v", err)
+		}
+		file, err := os.Create(*outputFile)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+		statsOut = os.Stdout
+	}
+
+	if err := batch.Write(out, results, resultsFormat); err != nil {
+		log.Fatal(err)
+	}
+
+	stats := batch.Summarize(results)
+	if *statsJSON {
+		encoder := json.NewEncoder(statsOut)
+		encoder.SetIndent("", "    ")
+		if err := encoder.Encode(stats); err != nil {
+			log.Fatalf("Failed to encode statistics: %v", err)
+		}
+	} else {
+		batch.WriteStats(statsOut, stats)
+	}
+
+	if *outputFile != "" {
+		fmt.Printf("\nResults for %d cards written to: %s\n", len(results), *outputFile)
+	}
+}
+
+// loadCards parses a CSV file and saves its cards into the store
+func loadCards(app *bootstrap.Application, cardStore store.Store, filename, cardType string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer file.Close()
+
+	csvParser, err := app.GetCSVParser(file)
+	if err != nil {
+		return fmt.Errorf("failed to get CSV parser: %w", err)
+	}
+
+	cards, err := csvParser.ParseCSV(cardType)
+	if err != nil {
+		return fmt.Errorf("failed to parse cards: %w", err)
+	}
+
+	for _, c := range cards {
+		if _, err := cardStore.Save(c); err != nil {
+			return fmt.Errorf("failed to save card %s: %w", c.GetName(), err)
+		}
+	}
+
+	log.Printf("Loaded %d %s cards from %s", len(cards), cardType, filename)
+	return nil
+}
@@ -1,14 +1,18 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/pdf"
 	"github.com/ControlYourPotatoes/card-generator/backend/pkg/bootstrap"
 )
 
@@ -25,8 +29,25 @@ func main() {
 	clean := flag.Bool("clean", false, "Clean output directories before generation")
 	cardType := flag.String("type", "creature", "Type of cards to parse (creature, spell, artifact, incantation, anthem)")
 	env := flag.String("env", "development", "Environment (development, production, test)")
+	workers := flag.Int("workers", 0, "Concurrent renders (default: generator.parallel_jobs from config)")
+	mode := flag.String("mode", "png", "Output mode: png writes card images; pdf also lays them out on print sheets")
+	pdfFile := flag.String("pdf", "output/cards.pdf", "Output PDF file in pdf mode")
+	format := flag.String("format", "", "Image format: png, jpeg or webp (default: generator.format from config)")
+	sidesFlag := flag.String("sides", "front", "Card sides to output: front, both (front and back pairs) or back (backs only)")
 	flag.Parse()
 
+	if *mode != "png" && *mode != "pdf" {
+		log.Fatalf("Unsupported output mode: %s", *mode)
+	}
+	sides, err := pdf.ParseSides(*sidesFlag)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Stop rendering on interrupt; cards already rendered are kept
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	// Initialize application with DI
 	app, err := bootstrap.NewApplication(*env)
 	if err != nil {
@@ -38,6 +59,24 @@ func main() {
 		}
 	}()
 
+	// The format flag overrides the configuration the generator is built from
+	if *format != "" {
+		app.Config.Generator.Format = *format
+	}
+	outputOpts, err := app.OutputOptions()
+	if err != nil {
+		log.Fatalf("Invalid output configuration: %v", err)
+	}
+	ext := outputOpts.Format.Extension()
+
+	// Check the sheet layout before rendering anything
+	var layout pdf.Layout
+	if *mode == "pdf" {
+		if layout, err = printLayout(app.Config.Generator, sides); err != nil {
+			log.Fatalf("Invalid print configuration: %v", err)
+		}
+	}
+
 	// Clean output directories if requested
 	if *clean {
 		if err := cleanOutputDirectories(*outputFile, *outputImageDir); err != nil {
@@ -56,7 +95,10 @@ func main() {
 	}
 
 	// Process cards and collect results
-	results, err := processCards(*inputFile, *cardType, *outputImageDir, app)
+	if *workers <= 0 {
+		*workers = app.Config.Generator.ParallelJobs
+	}
+	results, err := processCards(ctx, *inputFile, *cardType, *outputImageDir, ext, sides, *workers, app)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -72,9 +114,18 @@ func main() {
 		log.Fatal(err)
 	}
 
+	if *mode == "pdf" {
+		if err := writePDF(*pdfFile, layout, sides, *outputImageDir, ext, results); err != nil {
+			log.Fatal(err)
+		}
+	}
+
 	fmt.Printf("Successfully processed %d cards\n", len(results))
 	fmt.Printf("Output written to: %s\n", *outputFile)
 	fmt.Printf("Card images written to: %s\n", *outputImageDir)
+	if *mode == "pdf" {
+		fmt.Printf("Print sheets written to: %s\n", *pdfFile)
+	}
 }
 
 func cleanOutputDirectories(jsonPath string, imagePath string) error {
@@ -120,7 +171,9 @@ func cleanOutputDirectories(jsonPath string, imagePath string) error {
 	return nil
 }
 
-func processCards(filename string, cardType string, outputImageDir string, app *bootstrap.Application) ([]CardOutput, error) {
+// processCards saves every parsed card to the store, then renders the requested sides of each card concurrently.
+// Cards that fail to render are reported and left out of the results.
+func processCards(ctx context.Context, filename string, cardType string, outputImageDir string, ext string, sides pdf.Sides, workers int, app *bootstrap.Application) ([]CardOutput, error) {
 	// Open input file
 	file, err := os.Open(filename)
 	if err != nil {
@@ -134,7 +187,7 @@ func processCards(filename string, cardType string, outputImageDir string, app *
 		return nil, fmt.Errorf("failed to get card store: %w", err)
 	}
 
-	generator, err := app.GetCardGenerator()
+	cardGenerator, err := app.GetCardGenerator()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get card generator: %w", err)
 	}
@@ -150,37 +203,63 @@ func processCards(filename string, cardType string, outputImageDir string, app *
 		return nil, fmt.Errorf("failed to parse cards: %w", err)
 	}
 
-	var results []CardOutput
-
-	// Process each card
+	// Save to store, collecting a render job per card
+	outputs := make([]CardOutput, 0, len(cards))
+	jobs := make([]generator.BatchJob, 0, len(cards))
 	for _, c := range cards {
-		// Save to store
 		id, err := store.Save(c)
 		if err != nil {
 			return nil, fmt.Errorf("failed to save card %s: %w", c.GetName(), err)
 		}
 
-		// Convert to DTO for JSON output and image generation
 		cardDTO := c.ToDTO()
-
-		// Generate image for the card
-		imagePath := filepath.Join(outputImageDir, id+".png")
-		if err := generator.GenerateCard(cardDTO, imagePath); err != nil {
-			return nil, fmt.Errorf("failed to generate image for card %s: %w", c.GetName(), err)
+		outputs = append(outputs, CardOutput{ID: id, Card: cardDTO})
+		job := generator.BatchJob{Card: cardDTO}
+		if sides != pdf.SidesBack {
+			job.OutputPath = frontPath(outputImageDir, id, ext)
 		}
+		if sides != pdf.SidesFront {
+			job.BackPath = backPath(outputImageDir, id, ext)
+		}
+		jobs = append(jobs, job)
+	}
 
-		// Add to results
-		results = append(results, CardOutput{
-			ID:   id,
-			Card: cardDTO,
-		})
+	renderer := generator.NewBatchRenderer(cardGenerator, workers).OnProgress(printProgress)
+	rendered, err := renderer.Render(ctx, jobs)
+	if err != nil {
+		return nil, fmt.Errorf("rendering stopped: %w", err)
+	}
 
-		fmt.Printf("Processed card: %s (ID: %s)\n", c.GetName(), id)
+	var results []CardOutput
+	for i, result := range rendered {
+		if result.Err == nil {
+			results = append(results, outputs[i])
+		}
+	}
+	if failed := generator.Failed(rendered); len(failed) > 0 {
+		log.Printf("%d of %d cards failed to render", len(failed), len(rendered))
 	}
 
 	return results, nil
 }
 
+// printProgress reports each rendered card with the batch's progress
+func printProgress(p generator.Progress) {
+	if p.Last.Err != nil {
+		fmt.Printf("[%d/%d] Failed card: %s: %v\n", p.Done, p.Total, p.Last.Name, p.Last.Err)
+		return
+	}
+	output := p.Last.OutputPath
+	if output == "" {
+		output = p.Last.BackPath
+	}
+	fmt.Printf("[%d/%d] Processed card: %s (%s)\n", p.Done, p.Total, p.Last.Name, output)
+}
+
+// frontPath and backPath name the image files of a card's two sides
+func frontPath(dir, id, ext string) string { return filepath.Join(dir, id+ext) }
+func backPath(dir, id, ext string) string  { return filepath.Join(dir, id+"-back"+ext) }
+
 func writeJSON(filename string, cards []CardOutput) error {
 	// Create output file
 	file, err := os.Create(filename)
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"image"
+	_ "image/jpeg" // register decoding of every output format
+	_ "image/png"
+	"os"
+	"path/filepath"
+
+	_ "golang.org/x/image/webp"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/pdf"
+	"github.com/ControlYourPotatoes/card-generator/backend/pkg/config"
+)
+
+// printLayout builds the PDF sheet layout from the generator configuration and checks it can print the sides
+func printLayout(cfg config.GeneratorConfig, sides pdf.Sides) (pdf.Layout, error) {
+	page, err := pdf.ParsePageSize(cfg.Print.PageSize)
+	if err != nil {
+		return pdf.Layout{}, err
+	}
+	duplex, err := pdf.ParseDuplex(cfg.Print.Duplex)
+	if err != nil {
+		return pdf.Layout{}, err
+	}
+
+	layout := pdf.Layout{
+		Page:        page,
+		CardWidth:   cfg.Print.CardWidth * pdf.PointsPerInch,
+		CardHeight:  cfg.Print.CardHeight * pdf.PointsPerInch,
+		Columns:     cfg.Print.Columns,
+		Rows:        cfg.Print.Rows,
+		Bleed:       cfg.Print.Bleed * pdf.PointsPerInch,
+		Gutter:      cfg.Print.Gutter * pdf.PointsPerInch,
+		CropMarks:   cfg.Print.CropMarks,
+		DPI:         cfg.DPI,
+		Duplex:      duplex,
+		BackOffsetX: cfg.Print.BackOffsetX * pdf.PointsPerInch,
+		BackOffsetY: cfg.Print.BackOffsetY * pdf.PointsPerInch,
+	}
+	if err := layout.Validate(); err != nil {
+		return pdf.Layout{}, err
+	}
+	return layout, layout.CanPrint(sides)
+}
+
+// writePDF lays out the rendered card images on print sheets, in the order of the results
+func writePDF(path string, layout pdf.Layout, sides pdf.Sides, imageDir, ext string, results []CardOutput) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create PDF directory: %w", err)
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create PDF: %w", err)
+	}
+	defer f.Close()
+
+	sheets, err := pdf.NewSheetWriter(f, layout, sides)
+	if err != nil {
+		return err
+	}
+	for _, result := range results {
+		var front, back image.Image
+		if sides != pdf.SidesBack {
+			if front, err = loadImage(frontPath(imageDir, result.ID, ext)); err != nil {
+				return fmt.Errorf("failed to load image of card %s: %w", result.Card.Name, err)
+			}
+		}
+		if sides != pdf.SidesFront {
+			if back, err = loadImage(backPath(imageDir, result.ID, ext)); err != nil {
+				return fmt.Errorf("failed to load back of card %s: %w", result.Card.Name, err)
+			}
+		}
+		if err := sheets.AddCard(front, back); err != nil {
+			return fmt.Errorf("failed to add card %s: %w", result.Card.Name, err)
+		}
+	}
+	if err := sheets.Close(); err != nil {
+		return fmt.Errorf("failed to write PDF: %w", err)
+	}
+	return f.Close()
+}
+
+// loadImage decodes a PNG, JPEG or WebP file
+func loadImage(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	return img, err
+}
@@ -1,15 +1,21 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/cache"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/parser"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/memory"
 )
 
@@ -24,8 +30,27 @@ func main() {
 	outputFile := flag.String("output", "output/cards.json", "Output JSON file for processed cards")
 	outputImageDir := flag.String("images", "output/cards", "Output directory for card images")
 	clean := flag.Bool("clean", false, "Clean output directories before generation")
+	cardType := flag.String("type", "creature", "Type of cards to parse (creature, spell, artifact, incantation, anthem)")
+	artDir := flag.String("art", "art", "Directory searched for card art")
+	workers := flag.Int("workers", 0, "Concurrent renders (default: one per CPU)")
+	cacheDir := flag.String("cache", "cache", "Directory caching renders of unchanged cards (empty disables)")
+	cacheSize := flag.Int64("cache-size", 512<<20, "Cache size limit in bytes")
+	formatName := flag.String("format", "png", "Image format: png, jpeg or webp")
+	width := flag.Int("width", 0, "Image width in pixels (default: native, or scaled with -height)")
+	height := flag.Int("height", 0, "Image height in pixels (default: native, or scaled with -width)")
+	quality := flag.Int("quality", 90, "JPEG quality, 1-100")
+	dpi := flag.Int("dpi", 300, "Resolution recorded in the images")
 	flag.Parse()
 
+	format, err := output.ParseFormat(*formatName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Stop rendering on interrupt; cards already rendered are kept
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
 	if *clean {
 		if err := cleanOutputDirectories(*outputFile, *outputImageDir); err != nil {
 			log.Printf("Warning: cleanup failed: %v", err)
@@ -38,17 +63,28 @@ func main() {
 	}
 
 	// Initialize components
-	store := memory.New()
-	defer store.Close()
+	cardStore := memory.New()
+	defer cardStore.Close()
 
-	// Create card factory
-	factory := card.NewCardFactory(store)
+	var renderCache *cache.Cache
+	if *cacheDir != "" {
+		var err error
+		renderCache, err = cache.New(*cacheDir, *cacheSize)
+		if err != nil {
+			log.Fatalf("Failed to open render cache: %v", err)
+		}
+	}
 
 	// Create image generator
-	generator, err := image.NewGenerator()
+	cardGenerator, err := generator.NewCardGeneratorWithConfig(&generator.Config{
+		ArtDir: *artDir,
+		Cache:  renderCache,
+		Output: output.Options{Format: format, Width: *width, Height: *height, Quality: *quality, DPI: *dpi},
+	})
 	if err != nil {
 		log.Fatalf("Failed to create image generator: %v", err)
 	}
+	defer cardGenerator.Close()
 
 	// Create output directories
 	if err := os.MkdirAll(*outputImageDir, 0755); err != nil {
@@ -56,7 +92,8 @@ func main() {
 	}
 
 	// Process cards and collect results
-	results, err := processCards(*inputFile, factory, store, generator, *outputImageDir)
+	renderer := generator.NewBatchRenderer(cardGenerator, *workers).OnProgress(printProgress)
+	results, err := processCards(ctx, *inputFile, *cardType, cardStore, renderer, *outputImageDir, format.Extension())
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -119,7 +156,10 @@ func cleanOutputDirectories(jsonPath string, imagePath string) error {
 	fmt.Println("Cleaned output directories")
 	return nil
 }
-func processCards(filename string, factory *card.CardFactory, store card.CardStore, generator *image.Generator, outputImageDir string) ([]CardOutput, error) {
+
+// processCards saves every parsed card to the store, then renders their images with the batch renderer.
+// Cards that fail to render are reported and left out of the results.
+func processCards(ctx context.Context, filename string, cardType string, cardStore store.Store, renderer *generator.BatchRenderer, outputImageDir, ext string) ([]CardOutput, error) {
 	// Open input file
 	file, err := os.Open(filename)
 	if err != nil {
@@ -127,52 +167,56 @@ func processCards(filename string, factory *card.CardFactory, store card.CardSto
 	}
 	defer file.Close()
 
-	// Create parser
-	p := parser.NewParser(file)
-
 	// Parse cards
-	cards, err := p.Parse()
+	cards, err := parser.NewCSVParser(file).ParseCSV(cardType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse cards: %w", err)
 	}
 
-	var results []CardOutput
-
-	// Process each card
+	// Save to store, collecting a render job per card
+	outputs := make([]CardOutput, 0, len(cards))
+	jobs := make([]generator.BatchJob, 0, len(cards))
 	for _, c := range cards {
-		// Convert to CardData
-		data := card.ToData(c)
-
-		// Create card using factory
-		newCard, err := factory.CreateFromData(data)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create card %s: %w", c.GetName(), err)
-		}
-
-		// Save to store
-		id, err := store.Save(newCard)
+		id, err := cardStore.Save(c)
 		if err != nil {
-			return nil, fmt.Errorf("failed to save card %s: %w", newCard.GetName(), err)
-		}
-
-		// Generate image for the card
-		imagePath := filepath.Join(outputImageDir, id+".png")
-		if err := generator.GenerateImage(data, imagePath); err != nil {
-			return nil, fmt.Errorf("failed to generate image for card %s: %w", newCard.GetName(), err)
+			return nil, fmt.Errorf("failed to save card %s: %w", c.GetName(), err)
 		}
 
-		// Add to results
-		results = append(results, CardOutput{
-			ID:   id,
-			Card: data,
+		data := c.ToDTO()
+		outputs = append(outputs, CardOutput{ID: id, Card: data})
+		jobs = append(jobs, generator.BatchJob{
+			Card:       data,
+			OutputPath: filepath.Join(outputImageDir, id+ext),
 		})
+	}
+
+	rendered, err := renderer.Render(ctx, jobs)
+	if err != nil {
+		return nil, fmt.Errorf("rendering stopped: %w", err)
+	}
 
-		fmt.Printf("Processed card: %s (ID: %s)\n", newCard.GetName(), id)
+	var results []CardOutput
+	for i, result := range rendered {
+		if result.Err == nil {
+			results = append(results, outputs[i])
+		}
+	}
+	if failed := generator.Failed(rendered); len(failed) > 0 {
+		log.Printf("%d of %d cards failed to render", len(failed), len(rendered))
 	}
 
 	return results, nil
 }
 
+// printProgress reports each rendered card with the batch's progress
+func printProgress(p generator.Progress) {
+	if p.Last.Err != nil {
+		fmt.Printf("[%d/%d] Failed card: %s: %v\n", p.Done, p.Total, p.Last.Name, p.Last.Err)
+		return
+	}
+	fmt.Printf("[%d/%d] Processed card: %s (%s)\n", p.Done, p.Total, p.Last.Name, p.Last.OutputPath)
+}
+
 func writeJSON(filename string, cards []CardOutput) error {
 	// Create output file
 	file, err := os.Create(filename)
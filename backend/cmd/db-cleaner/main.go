@@ -74,6 +74,7 @@ func cleanDatabase(store *database.PostgresStore) (int, error) {
 		"anthem_cards",
 		"card_set_cards",
 		"card_images",
+		"card_tags",
 		// Then delete from the main cards table
 		"cards",
 	}
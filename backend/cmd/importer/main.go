@@ -7,6 +7,8 @@ import (
 	"os"
 	"strings"
 
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/lint"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/parser"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/database"
 )
@@ -17,6 +19,8 @@ func main() {
 	inputFile := flag.String("file", "", "Path to the CSV file containing card data")
 	dbEnvFile := flag.String("env", ".env", "Path to the environment file for database configuration")
 	dryRun := flag.Bool("dry-run", false, "Parse the CSV and create cards but don't save to database")
+	lintEffects := flag.Bool("lint", true, "Check effect text against the style guide during a dry run")
+	styleGuide := flag.String("style", "", "Path to a YAML or JSON style guide for effect text linting")
 	flag.Parse()
 
 	// Validate input
@@ -68,6 +72,12 @@ func main() {
 		for i, c := range cards {
 			fmt.Printf("Card %d: %s (Cost: %d)\n", i+1, c.GetName(), c.GetCost())
 		}
+
+		if *lintEffects {
+			if err := lintCards(cards, *styleGuide); err != nil {
+				log.Fatalf("Failed to lint cards: %v", err)
+			}
+		}
 		return
 	}
 
@@ -114,3 +124,39 @@ func main() {
 
 	log.Printf("Successfully imported %d of %d cards", successCount, len(cards))
 }
+
+// lintCards checks each card's effect text against the style guide and prints suggestions
+func lintCards(cards []card.Card, styleGuidePath string) error {
+	guide := lint.DefaultStyleGuide()
+	if styleGuidePath != "" {
+		var err error
+		guide, err = lint.LoadStyleGuide(styleGuidePath)
+		if err != nil {
+			return err
+		}
+	}
+
+	linter, err := lint.NewLinter(guide)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println("\nEffect text lint:")
+	issueCount := 0
+	for _, c := range cards {
+		result := linter.Check(c.ToDTO())
+		if len(result.Issues) == 0 {
+			continue
+		}
+
+		fmt.Printf("%s:\n", result.CardName)
+		for _, issue := range result.Issues {
+			fmt.Printf("  [%s] %s\n", issue.Rule, issue.Message)
+		}
+		fmt.Printf("  suggested: %s\n", result.Fixed)
+		issueCount += len(result.Issues)
+	}
+
+	fmt.Printf("Found %d style issues in %d cards\n", issueCount, len(cards))
+	return nil
+}
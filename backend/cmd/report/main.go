@@ -0,0 +1,118 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/report"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+	"github.com/ControlYourPotatoes/card-generator/backend/pkg/bootstrap"
+)
+
+func main() {
+	// Command line flags
+	inputFile := flag.String("input", "", "Optional CSV file to load into the store before reporting")
+	cardType := flag.String("type", "creature", "Type of cards in the input file (creature, spell, artifact, incantation, anthem)")
+	format := flag.String("format", "markdown", "Report format (json, markdown, html)")
+	outputFile := flag.String("output", "", "Output file for the report (defaults to stdout)")
+	title := flag.String("title", "Set Report", "Report title")
+	rulesPath := flag.String("rules", "", "Optional tag rule file or directory merged with the built-in rules")
+	env := flag.String("env", "development", "Environment (development, production, test)")
+	flag.Parse()
+
+	reportFormat, err := report.ParseFormat(*format)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Initialize application with DI
+	app, err := bootstrap.NewApplication(*env)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+	defer func() {
+		if err := app.Shutdown(); err != nil {
+			log.Printf("Error during shutdown: %v", err)
+		}
+	}()
+
+	cardStore, err := app.GetCardStore()
+	if err != nil {
+		log.Fatalf("Failed to get card store: %v", err)
+	}
+
+	if *inputFile != "" {
+		if err := loadCards(app, cardStore, *inputFile, *cardType); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	cardTagger, err := app.NewCardTagger()
+	if err != nil {
+		log.Fatalf("Failed to create card tagger: %v", err)
+	}
+	if *rulesPath != "" {
+		if err := cardTagger.LoadRules(*rulesPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	builder := report.NewBuilder(cardTagger, report.DefaultOptions())
+	r, err := builder.FromStore(cardStore, *title)
+	if err != nil {
+		log.Fatalf("Failed to build report: %v", err)
+	}
+
+	var out io.Writer = os.Stdout
+	if *outputFile != "" {
+		if err := os.MkdirAll(filepath.Dir(*outputFile), 0755); err != nil {
+			log.Fatalf("Failed to create output directory: %v", err)
+		}
+		file, err := os.Create(*outputFile)
+		if err != nil {
+			log.Fatalf("Failed to create output file: %v", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	if err := report.Write(out, r, reportFormat); err != nil {
+		log.Fatal(err)
+	}
+
+	if *outputFile != "" {
+		fmt.Printf("Report for %d cards written to: %s\n", r.TotalCards, *outputFile)
+	}
+}
+
+// loadCards parses a CSV file and saves its cards into the store
+func loadCards(app *bootstrap.Application, cardStore store.Store, filename, cardType string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer file.Close()
+
+	csvParser, err := app.GetCSVParser(file)
+	if err != nil {
+		return fmt.Errorf("failed to get CSV parser: %w", err)
+	}
+
+	cards, err := csvParser.ParseCSV(cardType)
+	if err != nil {
+		return fmt.Errorf("failed to parse cards: %w", err)
+	}
+
+	for _, c := range cards {
+		if _, err := cardStore.Save(c); err != nil {
+			return fmt.Errorf("failed to save card %s: %w", c.GetName(), err)
+		}
+	}
+
+	log.Printf("Loaded %d %s cards from %s", len(cards), cardType, filename)
+	return nil
+}
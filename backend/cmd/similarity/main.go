@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/similarity"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+	"github.com/ControlYourPotatoes/card-generator/backend/pkg/bootstrap"
+)
+
+func main() {
+	// Command line flags
+	inputFile := flag.String("input", "", "Optional CSV file to load into the store before comparing")
+	cardType := flag.String("type", "creature", "Type of cards in the input file (creature, spell, artifact, incantation, anthem)")
+	threshold := flag.Float64("threshold", similarity.DefaultOptions().Threshold, "Combined similarity score at which cards are reported (0-1)")
+	asJSON := flag.Bool("json", false, "Print the result as JSON")
+	env := flag.String("env", "development", "Environment (development, production, test)")
+	flag.Parse()
+
+	if *threshold < 0 || *threshold > 1 {
+		log.Fatalf("Threshold must be between 0 and 1, got %v", *threshold)
+	}
+
+	// Initialize application with DI
+	app, err := bootstrap.NewApplication(*env)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+	defer func() {
+		if err := app.Shutdown(); err != nil {
+			log.Printf("Error during shutdown: %v", err)
+		}
+	}()
+
+	cardStore, err := app.GetCardStore()
+	if err != nil {
+		log.Fatalf("Failed to get card store: %v", err)
+	}
+
+	if *inputFile != "" {
+		if err := loadCards(app, cardStore, *inputFile, *cardType); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	options := similarity.DefaultOptions()
+	options.Threshold = *threshold
+
+	result, err := similarity.NewEngine(nil, options).FindInStore(cardStore)
+	if err != nil {
+		log.Fatalf("Failed to compare cards: %v", err)
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "    ")
+		if err := encoder.Encode(result); err != nil {
+			log.Fatalf("Failed to encode result: %v", err)
+		}
+		return
+	}
+
+	if len(result.Clusters) == 0 {
+		fmt.Printf("No near-duplicates found at threshold %.2f\n", result.Threshold)
+		return
+	}
+
+	fmt.Printf("Found %d near-duplicate clusters at threshold %.2f\n", len(result.Clusters), result.Threshold)
+	for i, cluster := range result.Clusters {
+		fmt.Printf("\nCluster %d (max score %.2f):\n", i+1, cluster.MaxScore)
+		for _, m := range cluster.Members {
+			fmt.Printf("  - %s (%s)\n", m.Name, m.Type)
+		}
+	}
+
+	fmt.Println("\nPairs:")
+	for _, pair := range result.Pairs {
+		fmt.Printf("  %.2f  %s <-> %s (text %.2f, stats %.2f)\n", pair.Score, pair.A.Name, pair.B.Name, pair.TextScore, pair.StatScore)
+	}
+}
+
+// loadCards parses a CSV file and saves its cards into the store
+func loadCards(app *bootstrap.Application, cardStore store.Store, filename, cardType string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer file.Close()
+
+	csvParser, err := app.GetCSVParser(file)
+	if err != nil {
+		return fmt.Errorf("failed to get CSV parser: %w", err)
+	}
+
+	cards, err := csvParser.ParseCSV(cardType)
+	if err != nil {
+		return fmt.Errorf("failed to parse cards: %w", err)
+	}
+
+	for _, c := range cards {
+		if _, err := cardStore.Save(c); err != nil {
+			return fmt.Errorf("failed to save card %s: %w", c.GetName(), err)
+		}
+	}
+
+	log.Printf("Loaded %d %s cards from %s", len(cards), cardType, filename)
+	return nil
+}
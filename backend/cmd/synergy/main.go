@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/synergy"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/deck"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+	"github.com/ControlYourPotatoes/card-generator/backend/pkg/bootstrap"
+)
+
+func main() {
+	// Command line flags
+	deckFile := flag.String("deck", "", "Deck file (YAML or JSON) to analyze; defaults to every card in the store")
+	inputFile := flag.String("input", "", "Optional CSV file to load into the store before analyzing")
+	cardType := flag.String("type", "creature", "Type of cards in the input file (creature, spell, artifact, incantation, anthem)")
+	rulesPath := flag.String("rules", "", "Optional tag rule file or directory merged with the built-in rules")
+	asJSON := flag.Bool("json", false, "Print the result as JSON")
+	env := flag.String("env", "development", "Environment (development, production, test)")
+	flag.Parse()
+
+	// Initialize application with DI
+	app, err := bootstrap.NewApplication(*env)
+	if err != nil {
+		log.Fatalf("Failed to initialize application: %v", err)
+	}
+	defer func() {
+		if err := app.Shutdown(); err != nil {
+			log.Printf("Error during shutdown: %v", err)
+		}
+	}()
+
+	cardStore, err := app.GetCardStore()
+	if err != nil {
+		log.Fatalf("Failed to get card store: %v", err)
+	}
+
+	if *inputFile != "" {
+		if err := loadCards(app, cardStore, *inputFile, *cardType); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	cardTagger, err := app.NewCardTagger()
+	if err != nil {
+		log.Fatalf("Failed to create card tagger: %v", err)
+	}
+	if *rulesPath != "" {
+		if err := cardTagger.LoadRules(*rulesPath); err != nil {
+			log.Fatal(err)
+		}
+	}
+	analyzer := synergy.NewAnalyzer(cardTagger)
+
+	var report *synergy.Report
+	if *deckFile != "" {
+		d, err := deck.Load(*deckFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		report, err = analyzer.AnalyzeDeck(d, cardStore)
+		if err != nil {
+			log.Fatalf("Failed to analyze deck: %v", err)
+		}
+	} else {
+		report, err = analyzer.AnalyzeStore(cardStore)
+		if err != nil {
+			log.Fatalf("Failed to analyze cards: %v", err)
+		}
+	}
+
+	if *asJSON {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "    ")
+		if err := encoder.Encode(report); err != nil {
+			log.Fatalf("Failed to encode result: %v", err)
+		}
+		return
+	}
+
+	name := report.Deck
+	if name == "" {
+		name = "Card pool"
+	}
+	fmt.Printf("%s: %d cards, coherence %.0f/100 (participation %.0f%%, connectivity %.0f%%)\n",
+		name, report.Cards, report.Coherence, report.Participation*100, report.Connectivity*100)
+
+	if len(report.Packages) == 0 {
+		fmt.Println("No synergies found")
+		return
+	}
+
+	fmt.Println("\nPackages:")
+	for _, p := range report.Packages {
+		label := string(p.Kind)
+		if p.Tribe != "" {
+			label += " (" + p.Tribe + ")"
+		}
+		fmt.Printf("  %s, %d copies\n    enablers: %v\n    payoffs:  %v\n", label, p.Copies, p.Enablers, p.Payoffs)
+	}
+
+	fmt.Println("\nInteractions:")
+	for _, i := range report.Interactions {
+		fmt.Printf("  [%s] %s\n", i.Kind, i.Reason)
+	}
+}
+
+// loadCards parses a CSV file and saves its cards into the store
+func loadCards(app *bootstrap.Application, cardStore store.Store, filename, cardType string) error {
+	file, err := os.Open(filename)
+	if err != nil {
+		return fmt.Errorf("failed to open input file: %w", err)
+	}
+	defer file.Close()
+
+	csvParser, err := app.GetCSVParser(file)
+	if err != nil {
+		return fmt.Errorf("failed to get CSV parser: %w", err)
+	}
+
+	cards, err := csvParser.ParseCSV(cardType)
+	if err != nil {
+		return fmt.Errorf("failed to parse cards: %w", err)
+	}
+
+	for _, c := range cards {
+		if _, err := cardStore.Save(c); err != nil {
+			return fmt.Errorf("failed to save card %s: %w", c.GetName(), err)
+		}
+	}
+
+	log.Printf("Loaded %d %s cards from %s", len(cards), cardType, filename)
+	return nil
+}
@@ -0,0 +1,305 @@
+// Package archetype classifies decks and card pools as aggro, control, midrange or combo
+package archetype
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/deck"
+)
+
+// Archetype is a broad deck strategy
+type Archetype string
+
+const (
+	Aggro    Archetype = "aggro"
+	Control  Archetype = "control"
+	Midrange Archetype = "midrange"
+	Combo    Archetype = "combo"
+)
+
+// All lists every archetype in display order
+var All = []Archetype{Aggro, Control, Midrange, Combo}
+
+// temperature softens the softmax over archetype scores; lower is more decisive
+const temperature = 0.5
+
+// Tagger generates tags for a card; satisfied by tagger.CardTagger
+type Tagger interface {
+	GenerateTags(card interface{}) ([]types.Tag, error)
+}
+
+// Feature names a deck-level measurement, each a share of the cards between 0 and 1
+type Feature string
+
+const (
+	FeatureLowCost   Feature = "low_cost"  // cost 2 or less
+	FeatureMidCost   Feature = "mid_cost"  // cost 3 or 4
+	FeatureHighCost  Feature = "high_cost" // cost 5 or more
+	FeatureCreatures Feature = "creatures"
+	FeatureAggro     Feature = "aggro_tags"   // tagged AGGRO
+	FeatureControl   Feature = "control_tags" // tagged CONTROL or REMOVAL
+	FeatureAdvantage Feature = "advantage"    // tagged CARD_ADVANTAGE
+	FeatureCombo     Feature = "combo_tags"   // any COMBO category tag
+	FeatureTokens    Feature = "token_makers" // tagged TOKEN_GENERATOR
+)
+
+// featureLabels describe each feature in explanations
+var featureLabels = map[Feature]string{
+	FeatureLowCost:   "cards cost 2 or less",
+	FeatureMidCost:   "cards cost 3 or 4",
+	FeatureHighCost:  "cards cost 5 or more",
+	FeatureCreatures: "cards are creatures",
+	FeatureAggro:     "cards are tagged AGGRO",
+	FeatureControl:   "cards are removal or control",
+	FeatureAdvantage: "cards provide card advantage",
+	FeatureCombo:     "cards have combo potential",
+	FeatureTokens:    "cards create tokens",
+}
+
+// Signal weighs a feature's contribution to an archetype's score
+type Signal struct {
+	Archetype Archetype
+	Feature   Feature
+	Weight    float64
+}
+
+// DefaultSignals are the weights used by NewClassifier
+var DefaultSignals = []Signal{
+	{Aggro, FeatureLowCost, 3},
+	{Aggro, FeatureCreatures, 1.5},
+	{Aggro, FeatureAggro, 3},
+	{Aggro, FeatureHighCost, -3},
+	{Aggro, FeatureControl, -1},
+
+	{Control, FeatureControl, 3},
+	{Control, FeatureAdvantage, 2},
+	{Control, FeatureHighCost, 2},
+	{Control, FeatureCreatures, -2},
+
+	{Midrange, FeatureMidCost, 3},
+	{Midrange, FeatureCreatures, 1},
+	{Midrange, FeatureControl, 1},
+	{Midrange, FeatureLowCost, -0.5},
+
+	{Combo, FeatureCombo, 4},
+	{Combo, FeatureAdvantage, 1},
+	{Combo, FeatureTokens, 1},
+	{Combo, FeatureCreatures, -0.5},
+}
+
+// Features summarizes the classified cards
+type Features struct {
+	Cards       int                 `json:"cards"`
+	AverageCost float64             `json:"average_cost"`
+	Shares      map[Feature]float64 `json:"shares"`
+}
+
+// Explanation is one feature's contribution to an archetype's score
+type Explanation struct {
+	Feature      Feature `json:"feature"`
+	Share        float64 `json:"share"`
+	Contribution float64 `json:"contribution"`
+	Reason       string  `json:"reason"`
+}
+
+// Probability is how likely the cards are to form an archetype, and why
+type Probability struct {
+	Archetype    Archetype     `json:"archetype"`
+	Probability  float64       `json:"probability"`
+	Score        float64       `json:"score"`
+	Explanations []Explanation `json:"explanations"`
+}
+
+// Result is the classification of a deck or card pool
+type Result struct {
+	Archetype     Archetype     `json:"archetype"`
+	Probabilities []Probability `json:"probabilities"` // sorted by probability, highest first
+	Features      Features      `json:"features"`
+}
+
+// Classifier aggregates card-level tags and curve statistics into archetype probabilities
+type Classifier struct {
+	tagger  Tagger
+	signals []Signal
+}
+
+// NewClassifier creates a classifier using the tagger's tags and DefaultSignals
+func NewClassifier(tagger Tagger) *Classifier {
+	return &Classifier{
+		tagger:  tagger,
+		signals: DefaultSignals,
+	}
+}
+
+// ClassifyDeck classifies a deck's main cards, counting every copy
+func (c *Classifier) ClassifyDeck(d *deck.Deck, cards deck.CardSource) (*Result, error) {
+	dtos := make([]*card.CardDTO, 0, d.Size())
+	for _, e := range d.Cards {
+		loaded, err := cards.Load(e.CardID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load card %s: %w", e.CardID, err)
+		}
+		dto := loaded.ToDTO()
+		for i := 0; i < e.Count; i++ {
+			dtos = append(dtos, dto)
+		}
+	}
+	return c.Classify(dtos)
+}
+
+// Classify returns archetype probabilities for the cards; repeated cards count once per copy
+func (c *Classifier) Classify(cards []*card.CardDTO) (*Result, error) {
+	if len(cards) == 0 {
+		return nil, fmt.Errorf("no cards to classify")
+	}
+
+	features, err := c.features(cards)
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make(map[Archetype]float64, len(All))
+	explanations := make(map[Archetype][]Explanation, len(All))
+	for _, signal := range c.signals {
+		share := features.Shares[signal.Feature]
+		contribution := signal.Weight * share
+		scores[signal.Archetype] += contribution
+		if contribution == 0 {
+			continue
+		}
+		explanations[signal.Archetype] = append(explanations[signal.Archetype], Explanation{
+			Feature:      signal.Feature,
+			Share:        share,
+			Contribution: round(contribution),
+			Reason:       reason(signal, share),
+		})
+	}
+
+	probabilities := softmax(scores)
+	result := &Result{Features: features, Probabilities: make([]Probability, 0, len(All))}
+	for _, a := range All {
+		reasons := explanations[a]
+		sort.SliceStable(reasons, func(i, j int) bool {
+			return math.Abs(reasons[i].Contribution) > math.Abs(reasons[j].Contribution)
+		})
+		if reasons == nil {
+			reasons = make([]Explanation, 0)
+		}
+		result.Probabilities = append(result.Probabilities, Probability{
+			Archetype:    a,
+			Probability:  round(probabilities[a]),
+			Score:        round(scores[a]),
+			Explanations: reasons,
+		})
+	}
+
+	sort.SliceStable(result.Probabilities, func(i, j int) bool {
+		return result.Probabilities[i].Probability > result.Probabilities[j].Probability
+	})
+	result.Archetype = result.Probabilities[0].Archetype
+	return result, nil
+}
+
+// features measures the curve, creature ratio and tag shares of the cards
+func (c *Classifier) features(cards []*card.CardDTO) (Features, error) {
+	counts := make(map[Feature]int)
+	tagged := make(map[*card.CardDTO]map[Feature]bool) // copies share a DTO, so tag each once
+	totalCost := 0
+	for _, dto := range cards {
+		totalCost += dto.Cost
+		switch {
+		case dto.Cost <= 2:
+			counts[FeatureLowCost]++
+		case dto.Cost <= 4:
+			counts[FeatureMidCost]++
+		default:
+			counts[FeatureHighCost]++
+		}
+		if dto.Type == card.TypeCreature {
+			counts[FeatureCreatures]++
+		}
+
+		if c.tagger == nil {
+			continue
+		}
+		cardFeatures, ok := tagged[dto]
+		if !ok {
+			tags, err := c.tagger.GenerateTags(dto)
+			if err != nil {
+				return Features{}, fmt.Errorf("failed to tag %s: %w", dto.Name, err)
+			}
+			cardFeatures = tagFeatures(tags)
+			tagged[dto] = cardFeatures
+		}
+		for feature := range cardFeatures {
+			counts[feature]++
+		}
+	}
+
+	features := Features{
+		Cards:       len(cards),
+		AverageCost: round(float64(totalCost) / float64(len(cards))),
+		Shares:      make(map[Feature]float64, len(featureLabels)),
+	}
+	for feature := range featureLabels {
+		features.Shares[feature] = round(float64(counts[feature]) / float64(len(cards)))
+	}
+	return features, nil
+}
+
+// tagFeatures returns the tag-based features a single card counts towards
+func tagFeatures(tags []types.Tag) map[Feature]bool {
+	features := make(map[Feature]bool)
+	for _, tag := range tags {
+		switch {
+		case tag.Name == "AGGRO":
+			features[FeatureAggro] = true
+		case tag.Name == "CONTROL" || tag.Name == "REMOVAL":
+			features[FeatureControl] = true
+		case tag.Name == "CARD_ADVANTAGE":
+			features[FeatureAdvantage] = true
+		case tag.Name == "TOKEN_GENERATOR":
+			features[FeatureTokens] = true
+		case tag.Category == types.TagCombo:
+			features[FeatureCombo] = true
+		}
+	}
+	return features
+}
+
+// reason phrases a signal's contribution, e.g. "62% of cards cost 2 or less"
+func reason(signal Signal, share float64) string {
+	text := fmt.Sprintf("%.0f%% of %s", share*100, featureLabels[signal.Feature])
+	if signal.Weight < 0 {
+		return text + " (counts against)"
+	}
+	return text
+}
+
+// softmax turns archetype scores into probabilities that sum to 1
+func softmax(scores map[Archetype]float64) map[Archetype]float64 {
+	max := math.Inf(-1)
+	for _, a := range All {
+		max = math.Max(max, scores[a])
+	}
+
+	total := 0.0
+	result := make(map[Archetype]float64, len(All))
+	for _, a := range All {
+		result[a] = math.Exp((scores[a] - max) / temperature)
+		total += result[a]
+	}
+	for a := range result {
+		result[a] /= total
+	}
+	return result
+}
+
+// round keeps three decimal places
+func round(value float64) float64 {
+	return math.Round(value*1000) / 1000
+}
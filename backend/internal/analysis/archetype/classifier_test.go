@@ -0,0 +1,125 @@
+package archetype
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/deck"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/memory"
+)
+
+var (
+	raider   = &card.CardDTO{Type: card.TypeCreature, Name: "Raider", Cost: 1, Effect: "HASTE. Can attack the turn it is played.", Attack: 2, Defense: 1}
+	striker  = &card.CardDTO{Type: card.TypeCreature, Name: "Striker", Cost: 2, Effect: "Whenever this creature attacks, it gains +1/0.", Attack: 2, Defense: 2}
+	squire   = &card.CardDTO{Type: card.TypeCreature, Name: "Squire", Cost: 1, Effect: "GUARD", Attack: 1, Defense: 2}
+	golem    = &card.CardDTO{Type: card.TypeCreature, Name: "War Golem", Cost: 4, Effect: "INDESTRUCTIBLE", Attack: 4, Defense: 4}
+	knight   = &card.CardDTO{Type: card.TypeCreature, Name: "Knight", Cost: 3, Effect: "GUARD", Attack: 3, Defense: 3}
+	smite    = &card.CardDTO{Type: card.TypeSpell, Name: "Smite", Cost: 3, Effect: "Destroy target creature."}
+	denial   = &card.CardDTO{Type: card.TypeIncantation, Name: "No!", Cost: 2, Effect: "Negate the play of an Incantation or Spell."}
+	insight  = &card.CardDTO{Type: card.TypeSpell, Name: "Insight", Cost: 5, Effect: "Draw 3 cards."}
+	judgment = &card.CardDTO{Type: card.TypeSpell, Name: "Judgment", Cost: 6, Effect: "Exile target creature. Draw 2 cards."}
+	rebirth  = &card.CardDTO{Type: card.TypeSpell, Name: "Rebirth", Cost: 3, Effect: "When a creature dies, return it from your graveyard to the battlefield."}
+	doubler  = &card.CardDTO{Type: card.TypeAnthem, Name: "Doubling Season", Cost: 4, Effect: "Whenever you create tokens, double the tokens created."}
+)
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name  string
+		cards []*card.CardDTO
+		want  Archetype
+	}{
+		{"Aggro", []*card.CardDTO{raider, raider, striker, striker, squire, squire, knight}, Aggro},
+		{"Control", []*card.CardDTO{smite, denial, insight, judgment, judgment, golem}, Control},
+		{"Midrange", []*card.CardDTO{golem, golem, knight, knight, smite, striker}, Midrange},
+		{"Combo", []*card.CardDTO{rebirth, rebirth, doubler, doubler, insight}, Combo},
+	}
+
+	classifier := NewClassifier(tagger.NewCardTagger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := classifier.Classify(tt.cards)
+			if err != nil {
+				t.Fatalf("Classify() error = %v", err)
+			}
+			if result.Archetype != tt.want {
+				t.Errorf("Archetype = %s, want %s; probabilities %+v", result.Archetype, tt.want, result.Probabilities)
+			}
+
+			total := 0.0
+			for i, p := range result.Probabilities {
+				total += p.Probability
+				if i > 0 && p.Probability > result.Probabilities[i-1].Probability {
+					t.Errorf("probabilities not sorted: %+v", result.Probabilities)
+				}
+			}
+			if math.Abs(total-1) > 0.01 {
+				t.Errorf("probabilities sum to %f", total)
+			}
+			if len(result.Probabilities[0].Explanations) == 0 {
+				t.Error("top archetype has no explanations")
+			}
+		})
+	}
+}
+
+func TestClassifyFeatures(t *testing.T) {
+	result, err := NewClassifier(nil).Classify([]*card.CardDTO{raider, knight, golem, insight})
+	if err != nil {
+		t.Fatalf("Classify() error = %v", err)
+	}
+
+	want := map[Feature]float64{
+		FeatureLowCost:   0.25,
+		FeatureMidCost:   0.5,
+		FeatureHighCost:  0.25,
+		FeatureCreatures: 0.75,
+		FeatureControl:   0,
+	}
+	for feature, share := range want {
+		if got := result.Features.Shares[feature]; got != share {
+			t.Errorf("share of %s = %v, want %v", feature, got, share)
+		}
+	}
+	if result.Features.AverageCost != 3.25 {
+		t.Errorf("AverageCost = %v, want 3.25", result.Features.AverageCost)
+	}
+
+	if _, err := NewClassifier(nil).Classify(nil); err == nil {
+		t.Error("Classify() of no cards should fail")
+	}
+}
+
+func TestClassifyDeck(t *testing.T) {
+	cards := memory.New()
+	ids := make(map[string]string)
+	for _, dto := range []*card.CardDTO{raider, striker, squire} {
+		id, err := cards.Save(card.NewCreatureFromDTO(dto))
+		if err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		ids[dto.Name] = id
+	}
+
+	d := deck.New("Sligh")
+	d.AddCard(ids["Raider"], 3)
+	d.AddCard(ids["Striker"], 3)
+	d.AddCard(ids["Squire"], 2)
+
+	result, err := NewClassifier(tagger.NewCardTagger()).ClassifyDeck(d, cards)
+	if err != nil {
+		t.Fatalf("ClassifyDeck() error = %v", err)
+	}
+	if result.Features.Cards != 8 {
+		t.Errorf("Cards = %d, want 8", result.Features.Cards)
+	}
+	if result.Archetype != Aggro {
+		t.Errorf("Archetype = %s, want aggro", result.Archetype)
+	}
+
+	d.AddCard("missing", 1)
+	if _, err := NewClassifier(nil).ClassifyDeck(d, cards); err == nil {
+		t.Error("ClassifyDeck() with an unknown card should fail")
+	}
+}
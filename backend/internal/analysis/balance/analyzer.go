@@ -0,0 +1,160 @@
+package balance
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// Verdict classifies a card relative to the cost curve
+type Verdict string
+
+const (
+	VerdictBalanced     Verdict = "balanced"
+	VerdictUndercosted  Verdict = "undercosted" // stronger than its cost suggests
+	VerdictOvercosted   Verdict = "overcosted"  // weaker than its cost suggests
+	VerdictUnassessable Verdict = "unassessable"
+)
+
+// Options controls curve fitting and outlier detection
+type Options struct {
+	// Threshold is the absolute z-score at which a card is flagged as an outlier
+	Threshold float64
+
+	// MinTypeSamples is the number of cards a type needs before it gets its own curve
+	MinTypeSamples int
+}
+
+// DefaultOptions returns the options used when none are configured
+func DefaultOptions() Options {
+	return Options{
+		Threshold:      1.5,
+		MinTypeSamples: 5,
+	}
+}
+
+// Assessment compares a card's power with the curve for its type
+type Assessment struct {
+	Score
+	Expected float64 `json:"expected"`
+	Delta    float64 `json:"delta"`
+	ZScore   float64 `json:"z_score"`
+	Verdict  Verdict `json:"verdict"`
+}
+
+// Report is the result of analyzing a card pool
+type Report struct {
+	Curve       Curve                   `json:"curve"`
+	TypeCurves  map[card.CardType]Curve `json:"type_curves"`
+	Assessments []Assessment            `json:"assessments"`
+	Outliers    []Assessment            `json:"outliers"`
+}
+
+// Analyzer scores a card pool and flags cards that sit far off the cost curve
+type Analyzer struct {
+	scorer  *Scorer
+	options Options
+}
+
+// NewAnalyzer creates an analyzer using the given scorer
+func NewAnalyzer(scorer *Scorer, options Options) *Analyzer {
+	return &Analyzer{
+		scorer:  scorer,
+		options: options,
+	}
+}
+
+// AnalyzeStore analyzes every card in the store
+func (a *Analyzer) AnalyzeStore(s store.Store) (*Report, error) {
+	cards, err := s.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %w", err)
+	}
+
+	dtos := make([]*card.CardDTO, 0, len(cards))
+	for _, c := range cards {
+		dtos = append(dtos, c.ToDTO())
+	}
+	return a.Analyze(dtos)
+}
+
+// Analyze scores the cards, fits cost curves over them and flags outliers
+func (a *Analyzer) Analyze(cards []*card.CardDTO) (*Report, error) {
+	scores := make([]Score, 0, len(cards))
+	byType := make(map[card.CardType][]Score)
+	for _, c := range cards {
+		score := a.scorer.Score(c)
+		scores = append(scores, score)
+		byType[c.Type] = append(byType[c.Type], score)
+	}
+
+	curve, err := FitCurve(scores)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fit cost curve: %w", err)
+	}
+
+	report := &Report{
+		Curve:      curve,
+		TypeCurves: make(map[card.CardType]Curve),
+	}
+
+	// Types with enough cards get their own curve, the rest use the pool curve
+	for cardType, typeScores := range byType {
+		if len(typeScores) < a.options.MinTypeSamples {
+			continue
+		}
+		if typeCurve, err := FitCurve(typeScores); err == nil {
+			report.TypeCurves[cardType] = typeCurve
+		}
+	}
+
+	for _, score := range scores {
+		assessment := a.assess(score, report.curveFor(score.Type))
+		report.Assessments = append(report.Assessments, assessment)
+		if assessment.Verdict == VerdictUndercosted || assessment.Verdict == VerdictOvercosted {
+			report.Outliers = append(report.Outliers, assessment)
+		}
+	}
+
+	// Most extreme outliers first
+	sort.SliceStable(report.Outliers, func(i, j int) bool {
+		return math.Abs(report.Outliers[i].ZScore) > math.Abs(report.Outliers[j].ZScore)
+	})
+
+	return report, nil
+}
+
+// curveFor returns the curve for a card type, falling back to the pool curve
+func (r *Report) curveFor(cardType card.CardType) Curve {
+	if curve, ok := r.TypeCurves[cardType]; ok {
+		return curve
+	}
+	return r.Curve
+}
+
+// assess compares a score with a curve
+func (a *Analyzer) assess(score Score, curve Curve) Assessment {
+	assessment := Assessment{Score: score, Verdict: VerdictUnassessable}
+	if score.Cost < 0 {
+		return assessment
+	}
+
+	assessment.Expected = curve.Expected(score.Cost)
+	assessment.Delta = score.Power - assessment.Expected
+	if curve.StdDev > 0 {
+		assessment.ZScore = assessment.Delta / curve.StdDev
+	}
+
+	switch {
+	case assessment.ZScore >= a.options.Threshold:
+		assessment.Verdict = VerdictUndercosted
+	case assessment.ZScore <= -a.options.Threshold:
+		assessment.Verdict = VerdictOvercosted
+	default:
+		assessment.Verdict = VerdictBalanced
+	}
+	return assessment
+}
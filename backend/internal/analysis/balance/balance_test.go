@@ -0,0 +1,223 @@
+package balance
+
+import (
+	"math"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/memory"
+)
+
+func TestScore(t *testing.T) {
+	scorer := NewScorer(nil)
+
+	tests := []struct {
+		name      string
+		card      *card.CardDTO
+		wantPower float64
+		wantKeys  []string
+	}{
+		{
+			name: "Vanilla creature",
+			card: &card.CardDTO{
+				Type: card.TypeCreature, Name: "Goblin Scout", Cost: 2,
+				Effect: "A small scout.", Attack: 2, Defense: 2,
+			},
+			wantPower: 2,
+			wantKeys:  []string{"stats"},
+		},
+		{
+			name: "Creature with keyword in effect text",
+			card: &card.CardDTO{
+				Type: card.TypeCreature, Name: "Blade Dancer", Cost: 3,
+				Effect: "CRITICAL", Attack: 3, Defense: 1,
+			},
+			wantPower: 3,
+			wantKeys:  []string{"stats", "keyword:CRITICAL"},
+		},
+		{
+			name: "Removal spell",
+			card: &card.CardDTO{
+				Type: card.TypeSpell, Name: "Smite", Cost: 2,
+				Effect: "Destroy target creature.",
+			},
+			wantPower: 2,
+			wantKeys:  []string{"effect:REMOVAL"},
+		},
+		{
+			name: "Card advantage with declared keyword",
+			card: &card.CardDTO{
+				Type: card.TypeSpell, Name: "Research", Cost: 1,
+				Effect: "Draw 2 cards.", Keywords: []string{"guide"},
+			},
+			wantPower: 2,
+			wantKeys:  []string{"effect:CARD_ADVANTAGE", "keyword:GUIDE"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := scorer.Score(tt.card)
+
+			if math.Abs(score.Power-tt.wantPower) > 1e-9 {
+				t.Errorf("Power = %v, want %v (breakdown %v)", score.Power, tt.wantPower, score.Breakdown)
+			}
+			if len(score.Breakdown) != len(tt.wantKeys) {
+				t.Errorf("Breakdown = %v, want keys %v", score.Breakdown, tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := score.Breakdown[key]; !ok {
+					t.Errorf("Breakdown missing %s: %v", key, score.Breakdown)
+				}
+			}
+		})
+	}
+}
+
+func TestScoreRuleSemantics(t *testing.T) {
+	// Effects use the tagger's rule semantics: groups, negation and conditions all apply
+	scorer := NewScorer(&Weights{
+		Effects: map[string]float64{"BURN": 1, "SELF_SACRIFICE": 1},
+		Rules: []types.TagRule{
+			{
+				Name: "BURN",
+				Patterns: []types.Pattern{
+					{Value: `deal (\d+) damage`, Type: types.RegexMatch, Group: 1},
+					{Value: "any target", Type: types.ExactMatch, Group: 2},
+					{Value: `to you\b`, Type: types.NegationMatch},
+				},
+				Conditions: []types.Condition{{Type: types.IsType, Value: card.TypeSpell}},
+			},
+			{
+				Name: "SELF_SACRIFICE",
+				Patterns: []types.Pattern{
+					{Value: "sacrifice creature", Type: types.ProximityMatch, Proximity: 1},
+				},
+			},
+		},
+	})
+
+	tests := []struct {
+		name     string
+		card     *card.CardDTO
+		wantKeys []string
+	}{
+		{"All groups match", &card.CardDTO{Type: card.TypeSpell, Effect: "Deal 3 damage to any target."}, []string{"effect:BURN"}},
+		{"One group missing", &card.CardDTO{Type: card.TypeSpell, Effect: "Deal 3 damage to target creature."}, nil},
+		{"Negated", &card.CardDTO{Type: card.TypeSpell, Effect: "Deal 3 damage to any target and 1 damage to you."}, nil},
+		{"Condition fails", &card.CardDTO{Type: card.TypeArtifact, Effect: "Deal 3 damage to any target."}, nil},
+		{"Proximity", &card.CardDTO{Type: card.TypeSpell, Effect: "Sacrifice a creature: draw a card."}, []string{"effect:SELF_SACRIFICE"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := scorer.Score(tt.card)
+			if len(score.Breakdown) != len(tt.wantKeys) {
+				t.Errorf("Breakdown = %v, want keys %v", score.Breakdown, tt.wantKeys)
+			}
+			for _, key := range tt.wantKeys {
+				if _, ok := score.Breakdown[key]; !ok {
+					t.Errorf("Breakdown missing %s: %v", key, score.Breakdown)
+				}
+			}
+		})
+	}
+}
+
+func TestFitCurve(t *testing.T) {
+	tests := []struct {
+		name          string
+		scores        []Score
+		wantErr       bool
+		wantSlope     float64
+		wantIntercept float64
+	}{
+		{
+			name:          "Perfect line",
+			scores:        []Score{{Cost: 1, Power: 3}, {Cost: 2, Power: 5}, {Cost: 3, Power: 7}},
+			wantSlope:     2,
+			wantIntercept: 1,
+		},
+		{
+			name:          "X costs are ignored",
+			scores:        []Score{{Cost: 0, Power: 0}, {Cost: 4, Power: 4}, {Cost: -1, Power: 100}},
+			wantSlope:     1,
+			wantIntercept: 0,
+		},
+		{
+			name:    "Single cost",
+			scores:  []Score{{Cost: 2, Power: 1}, {Cost: 2, Power: 3}},
+			wantErr: true,
+		},
+		{
+			name:    "Too few cards",
+			scores:  []Score{{Cost: 2, Power: 1}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			curve, err := FitCurve(tt.scores)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FitCurve() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if math.Abs(curve.Slope-tt.wantSlope) > 1e-9 || math.Abs(curve.Intercept-tt.wantIntercept) > 1e-9 {
+				t.Errorf("FitCurve() = %+v, want slope %v intercept %v", curve, tt.wantSlope, tt.wantIntercept)
+			}
+		})
+	}
+}
+
+func TestAnalyzeStore(t *testing.T) {
+	s := memory.New()
+	defer s.Close()
+
+	pool := []struct {
+		name    string
+		cost    int
+		attack  int
+		defense int
+	}{
+		{"Squire", 1, 1, 1},
+		{"Footman", 2, 2, 2},
+		{"Knight", 3, 3, 3},
+		{"Captain", 4, 4, 4},
+		{"Champion", 5, 5, 5},
+		{"Warlord", 6, 6, 6},
+		{"Pikeman", 2, 2, 2},
+		{"Sentry", 3, 3, 2},
+		{"Giant Rat", 1, 7, 7},
+	}
+	for _, p := range pool {
+		creature := card.NewCreatureFromDTO(&card.CardDTO{
+			Type: card.TypeCreature, Name: p.name, Cost: p.cost,
+			Effect: "A soldier.", Attack: p.attack, Defense: p.defense,
+		})
+		if _, err := s.Save(creature); err != nil {
+			t.Fatalf("Save(%s) error = %v", p.name, err)
+		}
+	}
+
+	report, err := NewAnalyzer(NewScorer(nil), DefaultOptions()).AnalyzeStore(s)
+	if err != nil {
+		t.Fatalf("AnalyzeStore() error = %v", err)
+	}
+
+	if len(report.Assessments) != len(pool) {
+		t.Errorf("got %d assessments, want %d", len(report.Assessments), len(pool))
+	}
+	if _, ok := report.TypeCurves[card.TypeCreature]; !ok {
+		t.Error("expected a creature cost curve")
+	}
+	if len(report.Outliers) != 1 {
+		t.Fatalf("got %d outliers, want 1: %+v", len(report.Outliers), report.Outliers)
+	}
+	if outlier := report.Outliers[0]; outlier.CardName != "Giant Rat" || outlier.Verdict != VerdictUndercosted {
+		t.Errorf("unexpected outlier: %+v", outlier)
+	}
+}
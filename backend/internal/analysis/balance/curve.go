@@ -0,0 +1,67 @@
+package balance
+
+import (
+	"fmt"
+	"math"
+)
+
+// Curve is a linear fit of power against cost
+type Curve struct {
+	Intercept float64 `json:"intercept"`
+	Slope     float64 `json:"slope"`
+
+	// StdDev is the standard deviation of the residuals around the fit
+	StdDev  float64 `json:"std_dev"`
+	Samples int     `json:"samples"`
+}
+
+// Expected returns the power the curve predicts for a cost
+func (c Curve) Expected(cost int) float64 {
+	return c.Intercept + c.Slope*float64(cost)
+}
+
+// FitCurve fits a least-squares line through the scores' cost and power.
+// Cards with X costs (-1) are ignored.
+func FitCurve(scores []Score) (Curve, error) {
+	var n, sumX, sumY float64
+	for _, s := range scores {
+		if s.Cost < 0 {
+			continue
+		}
+		n++
+		sumX += float64(s.Cost)
+		sumY += s.Power
+	}
+	if n < 2 {
+		return Curve{}, fmt.Errorf("need at least 2 cards to fit a cost curve, got %d", int(n))
+	}
+
+	meanX, meanY := sumX/n, sumY/n
+	var sxx, sxy float64
+	for _, s := range scores {
+		if s.Cost < 0 {
+			continue
+		}
+		dx := float64(s.Cost) - meanX
+		sxx += dx * dx
+		sxy += dx * (s.Power - meanY)
+	}
+	if sxx == 0 {
+		return Curve{}, fmt.Errorf("cannot fit a cost curve when every card has the same cost")
+	}
+
+	curve := Curve{Slope: sxy / sxx, Samples: int(n)}
+	curve.Intercept = meanY - curve.Slope*meanX
+
+	var sse float64
+	for _, s := range scores {
+		if s.Cost < 0 {
+			continue
+		}
+		residual := s.Power - curve.Expected(s.Cost)
+		sse += residual * residual
+	}
+	curve.StdDev = math.Sqrt(sse / n)
+
+	return curve, nil
+}
@@ -0,0 +1,155 @@
+// Package balance estimates card power and compares it against the cost curve of a card pool
+package balance
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/rules"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+// Weights controls how much each card feature contributes to the power score
+type Weights struct {
+	// Stat is the value of one point of attack or defense
+	Stat float64
+
+	// Keywords maps a keyword to its value; keywords not listed use DefaultKeyword
+	Keywords       map[string]float64
+	DefaultKeyword float64
+
+	// Effects maps an effect rule name to the value of that effect category
+	Effects map[string]float64
+
+	// Rules are the effect rules Effects refers to; nil selects rules.BaseRules
+	Rules []types.TagRule
+}
+
+// DefaultWeights returns the weights used when none are configured
+func DefaultWeights() *Weights {
+	return &Weights{
+		Stat: 0.5,
+		Keywords: map[string]float64{
+			"CRITICAL":       1.0,
+			"INDESTRUCTIBLE": 1.5,
+			"HASTE":          0.75,
+			"VIGILANCE":      0.5,
+			"BREAKTHROUGH":   0.5,
+			"GUIDE":          0.5,
+			"OFFER":          0.5,
+		},
+		DefaultKeyword: 0.5,
+		Effects: map[string]float64{
+			"REMOVAL":               2.0,
+			"CARD_ADVANTAGE":        1.5,
+			"TOKEN_GENERATOR":       1.0,
+			"GRAVEYARD_INTERACTION": 0.75,
+		},
+	}
+}
+
+// Score is the estimated power of a single card
+type Score struct {
+	CardName  string             `json:"card_name"`
+	Type      card.CardType      `json:"type"`
+	Cost      int                `json:"cost"`
+	Power     float64            `json:"power"`
+	Breakdown map[string]float64 `json:"breakdown"`
+}
+
+// Scorer computes power scores for cards
+type Scorer struct {
+	weights  *Weights
+	keywords map[string]*regexp.Regexp
+	matcher  *rules.Matcher
+	effects  []types.TagRule
+}
+
+// NewScorer creates a scorer; nil weights selects DefaultWeights
+func NewScorer(weights *Weights) *Scorer {
+	if weights == nil {
+		weights = DefaultWeights()
+	}
+
+	s := &Scorer{
+		weights:  weights,
+		keywords: make(map[string]*regexp.Regexp),
+		matcher:  rules.NewMatcher(),
+	}
+
+	for keyword := range weights.Keywords {
+		s.keywords[keyword] = regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(keyword) + `\b`)
+	}
+
+	effectRules := weights.Rules
+	if effectRules == nil {
+		effectRules = rules.BaseRules
+	}
+
+	// Effect rules are evaluated exactly as the tagger evaluates them
+	for _, rule := range effectRules {
+		if _, ok := weights.Effects[rule.Name]; ok {
+			s.effects = append(s.effects, rule)
+		}
+	}
+
+	return s
+}
+
+// Score computes the power score of a card
+func (s *Scorer) Score(c *card.CardDTO) Score {
+	breakdown := make(map[string]float64)
+
+	if c.Type == card.TypeCreature {
+		breakdown["stats"] = float64(c.Attack+c.Defense) * s.weights.Stat
+	}
+
+	for _, keyword := range s.cardKeywords(c) {
+		value, ok := s.weights.Keywords[keyword]
+		if !ok {
+			value = s.weights.DefaultKeyword
+		}
+		breakdown["keyword:"+keyword] = value
+	}
+
+	for _, rule := range s.effects {
+		if _, ok := s.matcher.EvaluateRule(c, rule); ok {
+			breakdown["effect:"+rule.Name] = s.weights.Effects[rule.Name]
+		}
+	}
+
+	var power float64
+	for _, value := range breakdown {
+		power += value
+	}
+
+	return Score{
+		CardName:  c.Name,
+		Type:      c.Type,
+		Cost:      c.Cost,
+		Power:     power,
+		Breakdown: breakdown,
+	}
+}
+
+// cardKeywords returns the card's declared keywords plus known keywords found in its effect text
+func (s *Scorer) cardKeywords(c *card.CardDTO) []string {
+	seen := make(map[string]bool)
+	for _, keyword := range c.Keywords {
+		seen[strings.ToUpper(keyword)] = true
+	}
+	for keyword, re := range s.keywords {
+		if re.MatchString(c.Effect) {
+			seen[keyword] = true
+		}
+	}
+
+	keywords := make([]string, 0, len(seen))
+	for keyword := range seen {
+		keywords = append(keywords, keyword)
+	}
+	sort.Strings(keywords)
+	return keywords
+}
@@ -0,0 +1,230 @@
+// Package batch analyzes many cards concurrently and aggregates tag statistics
+package batch
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/complexity"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// Result is the analysis of a single card
+type Result struct {
+	ID         string                `json:"id"`
+	Name       string                `json:"name"`
+	Type       card.CardType         `json:"type"`
+	Cost       int                   `json:"cost"`
+	Tags       []types.Tag           `json:"tags"`
+	Effects    []types.Tag           `json:"effects"`
+	Synergies  []types.Tag           `json:"synergies"`
+	Keywords   []string              `json:"keywords"`
+	Complexity complexity.Complexity `json:"complexity"`
+	Error      string                `json:"error,omitempty"`
+}
+
+// TagCount is how many cards carry a tag
+type TagCount struct {
+	Name     string            `json:"name"`
+	Category types.TagCategory `json:"category"`
+	Cards    int               `json:"cards"`
+}
+
+// CardError records a card that failed analysis
+type CardError struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Error string `json:"error"`
+}
+
+// Stats aggregates the results of a batch run
+type Stats struct {
+	Cards      int                       `json:"cards"`
+	Analyzed   int                       `json:"analyzed"`
+	Failed     int                       `json:"failed"`
+	Tags       []TagCount                `json:"tags"` // sorted by card count, highest first
+	Categories map[types.TagCategory]int `json:"categories"`
+	Errors     []CardError               `json:"errors"`
+}
+
+// Analyzer runs the card tagger, effect detector and synergy detector over cards with a pool of workers
+type Analyzer struct {
+	tagger    *tagger.CardTagger
+	effects   *tagger.EffectDetector
+	synergies *tagger.SynergyDetector
+	workers   int
+}
+
+// NewAnalyzer creates an analyzer using the given number of workers, or one per CPU when workers is not positive
+func NewAnalyzer(cardTagger *tagger.CardTagger, workers int) *Analyzer {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &Analyzer{
+		tagger:    cardTagger,
+		effects:   tagger.NewEffectDetector(),
+		synergies: tagger.NewSynergyDetector(),
+		workers:   workers,
+	}
+}
+
+// AnalyzeStore analyzes every card in the store
+func (a *Analyzer) AnalyzeStore(ctx context.Context, s store.Store) ([]Result, error) {
+	cards, err := s.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %w", err)
+	}
+
+	dtos := make([]*card.CardDTO, 0, len(cards))
+	for _, c := range cards {
+		dtos = append(dtos, c.ToDTO())
+	}
+	return a.Analyze(ctx, dtos)
+}
+
+// Analyze returns one result per card in input order. A card that fails analysis
+// is reported in its result's Error. When ctx is cancelled the finished results are
+// still returned, cards not yet analyzed carry the context error, and so does the run.
+func (a *Analyzer) Analyze(ctx context.Context, cards []*card.CardDTO) ([]Result, error) {
+	results := make([]Result, len(cards))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < a.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				if err := ctx.Err(); err != nil {
+					results[i] = failedResult(cards[i], err)
+					continue
+				}
+				results[i] = a.analyzeCard(ctx, cards[i])
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(cards); next++ {
+		select {
+		case jobs <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i := next; i < len(cards); i++ {
+			results[i] = failedResult(cards[i], err)
+		}
+		return results, err
+	}
+	return results, nil
+}
+
+// failedResult identifies a card that was not analyzed and records why
+func failedResult(c *card.CardDTO, err error) Result {
+	return Result{
+		ID:        tagger.CardKey(c),
+		Name:      c.Name,
+		Type:      c.Type,
+		Cost:      c.Cost,
+		Tags:      make([]types.Tag, 0),
+		Effects:   make([]types.Tag, 0),
+		Synergies: make([]types.Tag, 0),
+		Keywords:  make([]string, 0),
+		Error:     err.Error(),
+	}
+}
+
+// analyzeCard runs every analysis on one card
+func (a *Analyzer) analyzeCard(ctx context.Context, c *card.CardDTO) Result {
+	result := Result{
+		ID:         tagger.CardKey(c),
+		Name:       c.Name,
+		Type:       c.Type,
+		Cost:       c.Cost,
+		Effects:    nonNil(a.effects.AnalyzeEffect(c.Effect)),
+		Synergies:  nonNil(a.synergies.AnalyzeSynergies(c)),
+		Keywords:   a.effects.DetectKeywords(c.Effect),
+		Complexity: complexity.Analyze(c.Effect),
+	}
+	if result.Keywords == nil {
+		result.Keywords = make([]string, 0)
+	}
+
+	explanations, err := a.tagger.ExplainTagsContext(ctx, c)
+	if err != nil {
+		result.Error = err.Error()
+		result.Tags = make([]types.Tag, 0)
+		return result
+	}
+	result.Tags = make([]types.Tag, 0, len(explanations))
+	for _, explanation := range explanations {
+		result.Tags = append(result.Tags, explanation.Tag)
+	}
+	return result
+}
+
+// Summarize counts the cards carrying each tag and collects failed cards
+func Summarize(results []Result) Stats {
+	stats := Stats{
+		Cards:      len(results),
+		Tags:       make([]TagCount, 0),
+		Categories: make(map[types.TagCategory]int),
+		Errors:     make([]CardError, 0),
+	}
+
+	counts := make(map[string]*TagCount)
+	for _, result := range results {
+		if result.Error != "" {
+			stats.Failed++
+			stats.Errors = append(stats.Errors, CardError{ID: result.ID, Name: result.Name, Error: result.Error})
+			continue
+		}
+		stats.Analyzed++
+
+		seen := make(map[string]bool)
+		for _, tag := range result.Tags {
+			key := string(tag.Category) + ":" + tag.Name
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			stats.Categories[tag.Category]++
+			if count, ok := counts[key]; ok {
+				count.Cards++
+			} else {
+				counts[key] = &TagCount{Name: tag.Name, Category: tag.Category, Cards: 1}
+			}
+		}
+	}
+
+	for _, count := range counts {
+		stats.Tags = append(stats.Tags, *count)
+	}
+	sort.Slice(stats.Tags, func(i, j int) bool {
+		if stats.Tags[i].Cards != stats.Tags[j].Cards {
+			return stats.Tags[i].Cards > stats.Tags[j].Cards
+		}
+		return stats.Tags[i].Name < stats.Tags[j].Name
+	})
+	return stats
+}
+
+// nonNil keeps empty tag lists as [] in JSON output
+func nonNil(tags []types.Tag) []types.Tag {
+	if tags == nil {
+		return make([]types.Tag, 0)
+	}
+	return tags
+}
@@ -0,0 +1,150 @@
+package batch
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+var testCards = []*card.CardDTO{
+	{Type: card.TypeSpell, Name: "Smite", Cost: 3, Effect: "Destroy target creature."},
+	{Type: card.TypeSpell, Name: "Insight", Cost: 5, Effect: "Draw 3 cards."},
+	{Type: card.TypeCreature, Name: "Squire", Cost: 1, Effect: "GUARD", Attack: 1, Defense: 2},
+	{Type: card.TypeSpell, Name: "Shock", Cost: 1, Effect: "Deal 2 damage to target creature."},
+}
+
+func TestAnalyze(t *testing.T) {
+	for _, workers := range []int{1, 3, 0} {
+		results, err := NewAnalyzer(tagger.NewCardTagger(), workers).Analyze(context.Background(), testCards)
+		if err != nil {
+			t.Fatalf("Analyze() with %d workers error = %v", workers, err)
+		}
+		if len(results) != len(testCards) {
+			t.Fatalf("got %d results, want %d", len(results), len(testCards))
+		}
+		for i, result := range results {
+			if result.Name != testCards[i].Name {
+				t.Errorf("result %d is %s, want %s", i, result.Name, testCards[i].Name)
+			}
+			if len(result.Tags) == 0 {
+				t.Errorf("%s has no tags", result.Name)
+			}
+		}
+		if results[0].Complexity.Targets != 1 {
+			t.Errorf("Smite targets = %d, want 1", results[0].Complexity.Targets)
+		}
+		if !strings.Contains(joinTags(results[1].Effects), "CARD_DRAW") {
+			t.Errorf("Insight effects = %s, want CARD_DRAW", joinTags(results[1].Effects))
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	results, err := NewAnalyzer(tagger.NewCardTagger(), 1).Analyze(ctx, testCards)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Analyze() with a cancelled context error = %v, want context.Canceled", err)
+	}
+	if len(results) != len(testCards) {
+		t.Fatalf("got %d results, want %d", len(results), len(testCards))
+	}
+	for i, result := range results {
+		if result.Name != testCards[i].Name || result.Error != context.Canceled.Error() {
+			t.Errorf("result %d = %s with error %q, want %s cancelled", i, result.Name, result.Error, testCards[i].Name)
+		}
+	}
+	if stats := Summarize(results); stats.Failed != len(testCards) {
+		t.Errorf("Summarize() failed = %d, want %d", stats.Failed, len(testCards))
+	}
+}
+
+func TestSummarize(t *testing.T) {
+	results, err := NewAnalyzer(tagger.NewCardTagger(), 2).Analyze(context.Background(), testCards)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+	results = append(results, Result{ID: "broken", Name: "Broken", Error: "boom"})
+
+	stats := Summarize(results)
+	if stats.Cards != 5 || stats.Analyzed != 4 || stats.Failed != 1 {
+		t.Errorf("counts = %d/%d/%d, want 5/4/1", stats.Cards, stats.Analyzed, stats.Failed)
+	}
+	if len(stats.Errors) != 1 || stats.Errors[0].Error != "boom" {
+		t.Errorf("Errors = %+v", stats.Errors)
+	}
+	for i := 1; i < len(stats.Tags); i++ {
+		if stats.Tags[i].Cards > stats.Tags[i-1].Cards {
+			t.Fatalf("tags not sorted by count: %+v", stats.Tags)
+		}
+	}
+
+	counts := make(map[string]int)
+	for _, tag := range stats.Tags {
+		counts[tag.Name] = tag.Cards
+	}
+	if counts["LOW_COST"] != 2 {
+		t.Errorf("LOW_COST on %d cards, want 2", counts["LOW_COST"])
+	}
+
+	var buf bytes.Buffer
+	WriteStats(&buf, stats)
+	if !strings.Contains(buf.String(), "Analyzed 4 of 5 cards (1 failed)") || !strings.Contains(buf.String(), "Broken (broken): boom") {
+		t.Errorf("unexpected statistics output:\n%s", buf.String())
+	}
+}
+
+func TestWrite(t *testing.T) {
+	results, err := NewAnalyzer(tagger.NewCardTagger(), 2).Analyze(context.Background(), testCards)
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		format string
+		check  func(t *testing.T, out []byte)
+	}{
+		{"JSON", "json", func(t *testing.T, out []byte) {
+			var decoded []Result
+			if err := json.Unmarshal(out, &decoded); err != nil {
+				t.Fatalf("invalid JSON: %v", err)
+			}
+			if len(decoded) != len(testCards) || decoded[0].Name != "Smite" {
+				t.Errorf("unexpected results: %+v", decoded)
+			}
+		}},
+		{"CSV", "CSV", func(t *testing.T, out []byte) {
+			rows, err := csv.NewReader(bytes.NewReader(out)).ReadAll()
+			if err != nil {
+				t.Fatalf("invalid CSV: %v", err)
+			}
+			if len(rows) != len(testCards)+1 || rows[0][0] != "id" || rows[1][1] != "Smite" {
+				t.Errorf("unexpected rows: %v", rows)
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, err := ParseFormat(tt.format)
+			if err != nil {
+				t.Fatalf("ParseFormat() error = %v", err)
+			}
+			var buf bytes.Buffer
+			if err := Write(&buf, results, format); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			tt.check(t, buf.Bytes())
+		})
+	}
+
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("ParseFormat(xml) should fail")
+	}
+}
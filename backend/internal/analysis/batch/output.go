@@ -0,0 +1,128 @@
+package batch
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+)
+
+// Format is a per-card results output format
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatCSV  Format = "csv"
+)
+
+// ParseFormat converts a user-supplied format name to a Format
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "json":
+		return FormatJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	default:
+		return "", fmt.Errorf("unsupported results format: %s", value)
+	}
+}
+
+// csvHeader lists the CSV columns; tag lists are joined with semicolons
+var csvHeader = []string{"id", "name", "type", "cost", "tags", "effects", "synergies", "keywords", "complexity", "error"}
+
+// Write writes per-card results in the given format
+func Write(w io.Writer, results []Result, format Format) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "    ")
+		if err := encoder.Encode(results); err != nil {
+			return fmt.Errorf("failed to encode results: %w", err)
+		}
+		return nil
+	case FormatCSV:
+		return writeCSV(w, results)
+	default:
+		return fmt.Errorf("unsupported results format: %s", format)
+	}
+}
+
+// writeCSV writes one row per card
+func writeCSV(w io.Writer, results []Result) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+	for _, result := range results {
+		row := []string{
+			result.ID,
+			result.Name,
+			string(result.Type),
+			strconv.Itoa(result.Cost),
+			joinTags(result.Tags),
+			joinTags(result.Effects),
+			joinTags(result.Synergies),
+			strings.Join(result.Keywords, ";"),
+			strconv.Itoa(result.Complexity.Score),
+			result.Error,
+		}
+		if err := writer.Write(row); err != nil {
+			return fmt.Errorf("failed to write CSV row for %s: %w", result.Name, err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV: %w", err)
+	}
+	return nil
+}
+
+// joinTags joins distinct tag names in sorted order
+func joinTags(tags []types.Tag) string {
+	seen := make(map[string]bool, len(tags))
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		if !seen[tag.Name] {
+			seen[tag.Name] = true
+			names = append(names, tag.Name)
+		}
+	}
+	sort.Strings(names)
+	return strings.Join(names, ";")
+}
+
+// WriteStats prints the tag statistics and failed cards as plain text
+func WriteStats(w io.Writer, stats Stats) {
+	fmt.Fprintf(w, "Analyzed %d of %d cards (%d failed)\n", stats.Analyzed, stats.Cards, stats.Failed)
+
+	if len(stats.Categories) > 0 {
+		categories := make([]string, 0, len(stats.Categories))
+		for category := range stats.Categories {
+			categories = append(categories, string(category))
+		}
+		sort.Strings(categories)
+		fmt.Fprintln(w, "\nCards per tag category:")
+		for _, category := range categories {
+			fmt.Fprintf(w, "  %-10s %d\n", category, stats.Categories[types.TagCategory(category)])
+		}
+	}
+
+	if len(stats.Tags) > 0 {
+		fmt.Fprintln(w, "\nCards per tag:")
+		for _, tag := range stats.Tags {
+			fmt.Fprintf(w, "  %-24s %-10s %d\n", tag.Name, tag.Category, tag.Cards)
+		}
+	}
+
+	if len(stats.Errors) > 0 {
+		fmt.Fprintln(w, "\nErrors:")
+		for _, e := range stats.Errors {
+			fmt.Fprintf(w, "  %s (%s): %s\n", e.Name, e.ID, e.Error)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// Package complexity measures how hard card effect text is to read
+package complexity
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Complexity measures how hard an effect is to read
+type Complexity struct {
+	Clauses      int `json:"clauses"`      // sentences and semicolon-separated clauses
+	Conditionals int `json:"conditionals"` // if, when, whenever, unless, each time
+	Targets      int `json:"targets"`      // target, choose, select
+	Choices      int `json:"choices"`      // optional "may" effects
+	Keywords     int `json:"keywords"`
+	Numbers      int `json:"numbers"`
+	Words        int `json:"words"`
+	Score        int `json:"score"`
+}
+
+var (
+	clausePattern      = regexp.MustCompile(`[^.;]*\w[^.;]*`)
+	conditionalPattern = regexp.MustCompile(`(?i)\b(if|when|whenever|unless|each time)\b`)
+	targetPattern      = regexp.MustCompile(`(?i)\b(target|choose|selected?)\b`)
+	choicePattern      = regexp.MustCompile(`(?i)\bmay\b`)
+	numberPattern      = regexp.MustCompile(`\d+`)
+)
+
+// keywordPatterns match game keywords as whole words
+var keywordPatterns = map[string]*regexp.Regexp{
+	"FLYING":         regexp.MustCompile(`(?i)\bflying\b`),
+	"FIRST_STRIKE":   regexp.MustCompile(`(?i)\bfirst strike\b`),
+	"TRAMPLE":        regexp.MustCompile(`(?i)\btrample\b`),
+	"HASTE":          regexp.MustCompile(`(?i)\bhaste\b`),
+	"VIGILANCE":      regexp.MustCompile(`(?i)\bvigilance\b`),
+	"DEATHTOUCH":     regexp.MustCompile(`(?i)\bdeathtouch\b`),
+	"DOUBLE_STRIKE":  regexp.MustCompile(`(?i)\bdouble strike\b`),
+	"CRITICAL":       regexp.MustCompile(`(?i)\bcritical\b`),
+	"INDESTRUCTIBLE": regexp.MustCompile(`(?i)\bindestructible\b`),
+	"BREAKTHROUGH":   regexp.MustCompile(`(?i)\bbreakthrough\b`),
+	"RELOAD":         regexp.MustCompile(`(?i)\breload\b`),
+	"OFFER":          regexp.MustCompile(`(?i)\boffer\b`),
+	"GUIDE":          regexp.MustCompile(`(?i)\bguide\b`),
+	"COMMAND":        regexp.MustCompile(`(?i)\bcommand\b`),
+	"BOND":           regexp.MustCompile(`(?i)\bbond\b`),
+}
+
+// Keywords identifies game keywords in effect text, sorted by name
+func Keywords(effect string) []string {
+	var keywords []string
+	for keyword, pattern := range keywordPatterns {
+		if pattern.MatchString(effect) {
+			keywords = append(keywords, keyword)
+		}
+	}
+	sort.Strings(keywords)
+	return keywords
+}
+
+// Analyze measures an effect's clauses, conditionals, targets, keywords and length.
+// The score weighs conditionals double and adds one point per ten words.
+func Analyze(effect string) Complexity {
+	c := Complexity{
+		Clauses:      len(clausePattern.FindAllString(effect, -1)),
+		Conditionals: len(conditionalPattern.FindAllString(effect, -1)),
+		Targets:      len(targetPattern.FindAllString(effect, -1)),
+		Choices:      len(choicePattern.FindAllString(effect, -1)),
+		Keywords:     len(Keywords(effect)),
+		Numbers:      len(numberPattern.FindAllString(effect, -1)),
+		Words:        len(strings.Fields(effect)),
+	}
+	c.Score = c.Clauses + 2*c.Conditionals + c.Targets + c.Choices + c.Keywords + c.Words/10
+	return c
+}
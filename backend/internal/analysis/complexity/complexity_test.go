@@ -0,0 +1,40 @@
+package complexity
+
+import "testing"
+
+func TestAnalyze(t *testing.T) {
+	tests := []struct {
+		name   string
+		effect string
+		want   Complexity
+	}{
+		{
+			name:   "Simple removal",
+			effect: "Destroy target creature.",
+			want:   Complexity{Clauses: 1, Targets: 1, Words: 3, Score: 2},
+		},
+		{
+			name:   "Conditional with keyword",
+			effect: "ON PLAY - If you control a Goblin, gain HASTE. You may draw 2 cards; discard 1 card.",
+			want:   Complexity{Clauses: 3, Conditionals: 1, Choices: 1, Keywords: 1, Numbers: 2, Words: 18, Score: 8},
+		},
+		{
+			name:   "Words containing conditionals are not counted",
+			effect: "Modify the wheel.",
+			want:   Complexity{Clauses: 1, Words: 3, Score: 1},
+		},
+		{
+			name:   "Empty",
+			effect: "",
+			want:   Complexity{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analyze(tt.effect); got != tt.want {
+				t.Errorf("Analyze() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
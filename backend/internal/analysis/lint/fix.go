@@ -0,0 +1,36 @@
+package lint
+
+import (
+	"sort"
+	"strings"
+)
+
+// ApplyFixes applies the autofixes of the given issues to text. Issues must have
+// been produced for this exact text; overlapping fixes after the first are skipped.
+func ApplyFixes(text string, issues []Issue) string {
+	fixes := make([]Fix, 0, len(issues))
+	for _, issue := range issues {
+		if issue.Fix != nil {
+			fixes = append(fixes, *issue.Fix)
+		}
+	}
+	if len(fixes) == 0 {
+		return text
+	}
+
+	sort.SliceStable(fixes, func(i, j int) bool { return fixes[i].Start < fixes[j].Start })
+
+	var b strings.Builder
+	pos := 0
+	for _, fix := range fixes {
+		if fix.Start < pos || fix.End > len(text) || fix.Start > fix.End {
+			continue
+		}
+		b.WriteString(text[pos:fix.Start])
+		b.WriteString(fix.Replacement)
+		pos = fix.End
+	}
+	b.WriteString(text[pos:])
+
+	return b.String()
+}
@@ -0,0 +1,521 @@
+// Package lint checks card effect text against a templating style guide
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/complexity"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+// Rule names reported on issues
+const (
+	RuleTiming        = "timing-capitalization"
+	RuleKeyword       = "keyword-capitalization"
+	RuleNumber        = "number-style"
+	RuleSelfReference = "self-reference"
+	RulePunctuation   = "punctuation"
+	RuleBannedPhrase  = "banned-phrase"
+	RuleComplexity    = "complexity"
+)
+
+// Fix is a replacement of the byte range [Start, End) of the effect text
+type Fix struct {
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Replacement string `json:"replacement"`
+}
+
+// Issue is a single style guide violation found in effect text
+type Issue struct {
+	Rule       string `json:"rule"`
+	Message    string `json:"message"`
+	Text       string `json:"text"`
+	Suggestion string `json:"suggestion,omitempty"`
+	Fix        *Fix   `json:"fix,omitempty"`
+}
+
+// Result holds the lint output for one card
+type Result struct {
+	CardName string  `json:"card_name"`
+	Effect   string  `json:"effect"`
+	Issues   []Issue `json:"issues"`
+	Fixed    string  `json:"fixed_effect"`
+}
+
+var numberWords = []string{"zero", "one", "two", "three", "four", "five", "six", "seven", "eight", "nine", "ten"}
+
+// phraseMatcher pairs a compiled pattern with the canonical text it should match
+type phraseMatcher struct {
+	canonical string
+	pattern   *regexp.Regexp
+}
+
+// Linter checks effect text against a style guide
+type Linter struct {
+	guide    *StyleGuide
+	timings  []phraseMatcher
+	keywords []phraseMatcher
+	banned   []phraseMatcher
+	banByKey map[string]BannedPhrase
+}
+
+var (
+	numberWordPattern = regexp.MustCompile(`(?i)\b(zero|one|two|three|four|five|six|seven|eight|nine|ten)\b(\s+)(\w+)`)
+	digitPattern      = regexp.MustCompile(`\b\d+\b`)
+	doubleSpace       = regexp.MustCompile(` {2,}`)
+	spaceBeforePunct  = regexp.MustCompile(`[ \t]+([.,;:!?])`)
+)
+
+// NewLinter creates a linter for the given style guide; a nil guide uses the default
+func NewLinter(guide *StyleGuide) (*Linter, error) {
+	if guide == nil {
+		guide = DefaultStyleGuide()
+	}
+	if err := guide.Validate(); err != nil {
+		return nil, err
+	}
+
+	l := &Linter{
+		guide:    guide,
+		banByKey: make(map[string]BannedPhrase),
+	}
+	l.timings = compilePhrases(guide.Timings)
+	l.keywords = compilePhrases(guide.Keywords)
+
+	phrases := make([]string, 0, len(guide.BannedPhrases))
+	for _, banned := range guide.BannedPhrases {
+		phrases = append(phrases, banned.Phrase)
+		l.banByKey[strings.ToLower(banned.Phrase)] = banned
+	}
+	l.banned = compilePhrases(phrases)
+
+	return l, nil
+}
+
+// compilePhrases builds case-insensitive whole-word matchers, longest phrase first
+// so that "ON UPKEEP" is reported instead of the shorter "UPKEEP"
+func compilePhrases(phrases []string) []phraseMatcher {
+	sorted := append([]string(nil), phrases...)
+	sort.SliceStable(sorted, func(i, j int) bool { return len(sorted[i]) > len(sorted[j]) })
+
+	matchers := make([]phraseMatcher, 0, len(sorted))
+	for _, phrase := range sorted {
+		phrase = strings.TrimSpace(phrase)
+		if phrase == "" {
+			continue
+		}
+		matchers = append(matchers, phraseMatcher{
+			canonical: phrase,
+			pattern:   regexp.MustCompile(`(?i)\b` + regexp.QuoteMeta(phrase) + `\b`),
+		})
+	}
+	return matchers
+}
+
+// Lint checks a card's effect text, and that common cards stay within the complexity budget
+func (l *Linter) Lint(c *card.CardDTO) []Issue {
+	issues := l.LintText(c.Name, c.Effect)
+	if issue := l.checkComplexity(c); issue != nil {
+		issues = append(issues, *issue)
+	}
+	return issues
+}
+
+// LintText checks effect text belonging to the named card
+func (l *Linter) LintText(name, effect string) []Issue {
+	var issues []Issue
+
+	issues = append(issues, l.checkCanonical(effect, l.timings, RuleTiming, "timing")...)
+	issues = append(issues, l.checkCanonical(effect, l.keywords, RuleKeyword, "keyword")...)
+	issues = append(issues, l.checkNumbers(effect)...)
+	issues = append(issues, l.checkSelfReference(name, effect)...)
+	issues = append(issues, l.checkPunctuation(effect)...)
+	issues = append(issues, l.checkBannedPhrases(effect)...)
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issueStart(issues[i]) < issueStart(issues[j])
+	})
+	return issues
+}
+
+// Check lints a card and returns the issues together with the autofixed effect text
+func (l *Linter) Check(c *card.CardDTO) Result {
+	issues := l.Lint(c)
+	if issues == nil {
+		issues = []Issue{}
+	}
+	return Result{
+		CardName: c.Name,
+		Effect:   c.Effect,
+		Issues:   issues,
+		Fixed:    l.Fix(c.Name, c.Effect),
+	}
+}
+
+// Fix applies autofixes repeatedly until the text is stable, since one fix can
+// expose another (e.g. removing a double space next to punctuation)
+func (l *Linter) Fix(name, effect string) string {
+	const maxPasses = 5
+
+	fixed := effect
+	for i := 0; i < maxPasses; i++ {
+		next := ApplyFixes(fixed, l.LintText(name, fixed))
+		if next == fixed {
+			break
+		}
+		fixed = next
+	}
+	return fixed
+}
+
+// checkCanonical reports phrases that match case-insensitively but are not written canonically.
+// Keywords and timings such as "UPKEEP" or "OFFER" are also plain words, so only
+// occurrences in keyword position are reported.
+func (l *Linter) checkCanonical(effect string, matchers []phraseMatcher, rule, label string) []Issue {
+	var issues []Issue
+	covered := make([]bool, len(effect))
+
+	for _, m := range matchers {
+		for _, loc := range m.pattern.FindAllStringIndex(effect, -1) {
+			if isCovered(covered, loc[0], loc[1]) {
+				continue
+			}
+			markCovered(covered, loc[0], loc[1])
+
+			found := effect[loc[0]:loc[1]]
+			if found == m.canonical || !inKeywordPosition(effect, loc[0], loc[1]) {
+				continue
+			}
+			issues = append(issues, Issue{
+				Rule:       rule,
+				Message:    fmt.Sprintf("%s %q should be written as %q", label, found, m.canonical),
+				Text:       found,
+				Suggestion: m.canonical,
+				Fix:        &Fix{Start: loc[0], End: loc[1], Replacement: m.canonical},
+			})
+		}
+	}
+	return issues
+}
+
+// checkNumbers enforces digits or words for quantities from zero to ten
+func (l *Linter) checkNumbers(effect string) []Issue {
+	switch l.guide.NumberStyle {
+	case NumberWords:
+		return l.checkDigits(effect)
+	case NumberDigits, "":
+		return l.checkNumberWords(effect)
+	}
+	return nil
+}
+
+// checkNumberWords flags "draw two cards" when digits are preferred
+func (l *Linter) checkNumberWords(effect string) []Issue {
+	var issues []Issue
+
+	for _, loc := range numberWordPattern.FindAllStringSubmatchIndex(effect, -1) {
+		word := effect[loc[2]:loc[3]]
+		next := strings.ToLower(effect[loc[6]:loc[7]])
+
+		// "one of your creatures", "one more time" are not quantities
+		if next == "of" || next == "more" || next == "another" || next == "or" {
+			continue
+		}
+
+		digit := fmt.Sprintf("%d", indexOf(numberWords, strings.ToLower(word)))
+		issues = append(issues, Issue{
+			Rule:       RuleNumber,
+			Message:    fmt.Sprintf("write quantity %q as %q", word, digit),
+			Text:       word,
+			Suggestion: digit,
+			Fix:        &Fix{Start: loc[2], End: loc[3], Replacement: digit},
+		})
+	}
+	return issues
+}
+
+// checkDigits flags "draw 2 cards" when words are preferred, leaving stat
+// modifiers such as "+1/+1" alone
+func (l *Linter) checkDigits(effect string) []Issue {
+	var issues []Issue
+
+	for _, loc := range digitPattern.FindAllStringIndex(effect, -1) {
+		if loc[0] > 0 && strings.ContainsRune("+-/", rune(effect[loc[0]-1])) {
+			continue
+		}
+		if loc[1] < len(effect) && effect[loc[1]] == '/' {
+			continue
+		}
+
+		digits := effect[loc[0]:loc[1]]
+		var n int
+		if _, err := fmt.Sscanf(digits, "%d", &n); err != nil || n >= len(numberWords) {
+			continue
+		}
+
+		word := numberWords[n]
+		if atSentenceStart(effect, loc[0]) {
+			word = capitalize(word)
+		}
+		issues = append(issues, Issue{
+			Rule:       RuleNumber,
+			Message:    fmt.Sprintf("write quantity %q as %q", digits, word),
+			Text:       digits,
+			Suggestion: word,
+			Fix:        &Fix{Start: loc[0], End: loc[1], Replacement: word},
+		})
+	}
+	return issues
+}
+
+// checkSelfReference flags a card naming itself instead of using the self-reference phrase
+func (l *Linter) checkSelfReference(name, effect string) []Issue {
+	name = strings.TrimSpace(name)
+	if name == "" || l.guide.SelfReference == "" {
+		return nil
+	}
+
+	pattern := regexp.MustCompile(`(?i)` + regexp.QuoteMeta(name))
+
+	var issues []Issue
+	for _, loc := range pattern.FindAllStringIndex(effect, -1) {
+		if !isWholeWord(effect, loc[0], loc[1]) {
+			continue
+		}
+		replacement := l.guide.SelfReference
+		if atSentenceStart(effect, loc[0]) {
+			replacement = capitalize(replacement)
+		}
+		issues = append(issues, Issue{
+			Rule:       RuleSelfReference,
+			Message:    fmt.Sprintf("refer to the card as %q instead of by name", l.guide.SelfReference),
+			Text:       effect[loc[0]:loc[1]],
+			Suggestion: replacement,
+			Fix:        &Fix{Start: loc[0], End: loc[1], Replacement: replacement},
+		})
+	}
+	return issues
+}
+
+// checkPunctuation flags spacing problems and a missing terminal period
+func (l *Linter) checkPunctuation(effect string) []Issue {
+	var issues []Issue
+
+	trimmedStart := len(effect) - len(strings.TrimLeftFunc(effect, unicode.IsSpace))
+	if trimmedStart > 0 && trimmedStart < len(effect) {
+		issues = append(issues, Issue{
+			Rule:    RulePunctuation,
+			Message: "remove leading whitespace",
+			Text:    effect[:trimmedStart],
+			Fix:     &Fix{Start: 0, End: trimmedStart},
+		})
+	}
+
+	trimmedEnd := len(strings.TrimRightFunc(effect, unicode.IsSpace))
+	if trimmedEnd < len(effect) && trimmedEnd > 0 {
+		issues = append(issues, Issue{
+			Rule:    RulePunctuation,
+			Message: "remove trailing whitespace",
+			Text:    effect[trimmedEnd:],
+			Fix:     &Fix{Start: trimmedEnd, End: len(effect)},
+		})
+	}
+
+	for _, loc := range doubleSpace.FindAllStringIndex(effect[:trimmedEnd], -1) {
+		if loc[0] < trimmedStart {
+			continue
+		}
+		issues = append(issues, Issue{
+			Rule:       RulePunctuation,
+			Message:    "use a single space between words",
+			Text:       effect[loc[0]:loc[1]],
+			Suggestion: " ",
+			Fix:        &Fix{Start: loc[0], End: loc[1], Replacement: " "},
+		})
+	}
+
+	for _, loc := range spaceBeforePunct.FindAllStringSubmatchIndex(effect[:trimmedEnd], -1) {
+		if loc[0] < trimmedStart {
+			continue
+		}
+		punct := effect[loc[2]:loc[3]]
+		issues = append(issues, Issue{
+			Rule:       RulePunctuation,
+			Message:    fmt.Sprintf("remove the space before %q", punct),
+			Text:       effect[loc[0]:loc[1]],
+			Suggestion: punct,
+			Fix:        &Fix{Start: loc[0], End: loc[1], Replacement: punct},
+		})
+	}
+
+	if l.guide.RequireTerminalPeriod && trimmedEnd > 0 {
+		last := rune(effect[trimmedEnd-1])
+		if unicode.IsLetter(last) || unicode.IsDigit(last) || last == ')' || last == '"' {
+			issues = append(issues, Issue{
+				Rule:       RulePunctuation,
+				Message:    "end effect text with a period",
+				Suggestion: ".",
+				Fix:        &Fix{Start: trimmedEnd, End: trimmedEnd, Replacement: "."},
+			})
+		}
+	}
+
+	return issues
+}
+
+// checkComplexity flags common cards whose effect complexity exceeds the style guide's budget
+func (l *Linter) checkComplexity(c *card.CardDTO) *Issue {
+	if l.guide.MaxCommonComplexity == 0 || !strings.EqualFold(c.Metadata["rarity"], "common") {
+		return nil
+	}
+
+	measured := complexity.Analyze(c.Effect)
+	if measured.Score <= l.guide.MaxCommonComplexity {
+		return nil
+	}
+	return &Issue{
+		Rule: RuleComplexity,
+		Message: fmt.Sprintf(
+			"effect complexity %d exceeds %d allowed on common cards (%d clauses, %d conditionals, %d targets, %d keywords, %d words)",
+			measured.Score, l.guide.MaxCommonComplexity,
+			measured.Clauses, measured.Conditionals, measured.Targets, measured.Keywords, measured.Words,
+		),
+		Text:       c.Effect,
+		Suggestion: "simplify the effect or raise the card's rarity",
+	}
+}
+
+// checkBannedPhrases flags phrasings the style guide rejects
+func (l *Linter) checkBannedPhrases(effect string) []Issue {
+	var issues []Issue
+	covered := make([]bool, len(effect))
+
+	for _, m := range l.banned {
+		banned := l.banByKey[strings.ToLower(m.canonical)]
+		for _, loc := range m.pattern.FindAllStringIndex(effect, -1) {
+			if isCovered(covered, loc[0], loc[1]) {
+				continue
+			}
+			markCovered(covered, loc[0], loc[1])
+
+			issue := Issue{
+				Rule:    RuleBannedPhrase,
+				Message: fmt.Sprintf("avoid %q", banned.Phrase),
+				Text:    effect[loc[0]:loc[1]],
+			}
+			if banned.Reason != "" {
+				issue.Message += ": " + banned.Reason
+			}
+			if banned.Replacement != "" {
+				replacement := banned.Replacement
+				if atSentenceStart(effect, loc[0]) {
+					replacement = capitalize(replacement)
+				}
+				issue.Suggestion = replacement
+				issue.Fix = &Fix{Start: loc[0], End: loc[1], Replacement: replacement}
+			}
+			issues = append(issues, issue)
+		}
+	}
+	return issues
+}
+
+// Helper functions
+func issueStart(issue Issue) int {
+	if issue.Fix == nil {
+		return -1
+	}
+	return issue.Fix.Start
+}
+
+func isCovered(covered []bool, start, end int) bool {
+	for i := start; i < end; i++ {
+		if covered[i] {
+			return true
+		}
+	}
+	return false
+}
+
+func markCovered(covered []bool, start, end int) {
+	for i := start; i < end; i++ {
+		covered[i] = true
+	}
+}
+
+func indexOf(values []string, value string) int {
+	for i, v := range values {
+		if v == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// atSentenceStart reports whether the byte offset begins a sentence or clause
+func atSentenceStart(text string, offset int) bool {
+	before := strings.TrimRightFunc(text[:offset], unicode.IsSpace)
+	if before == "" {
+		return true
+	}
+	last, _ := utf8.DecodeLastRuneInString(before)
+	return strings.ContainsRune(".!?-–", last)
+}
+
+// grantingWords introduce a keyword a card gains or has, e.g. "gains CRITICAL"
+var grantingWords = map[string]bool{
+	"gain": true, "gains": true, "has": true, "have": true, "with": true, "lose": true, "loses": true,
+}
+
+// inKeywordPosition reports whether the phrase at [start, end) is used as a keyword or timing:
+// a header followed by ":" or "-", a clause of its own ("Haste, vigilance.") or with a value
+// ("guide 2"), the object of a granting word ("gains critical"), or capitalized in the middle
+// of a sentence ("deals Critical damage")
+func inKeywordPosition(text string, start, end int) bool {
+	after := strings.TrimLeftFunc(text[end:], unicode.IsSpace)
+	next, _ := utf8.DecodeRuneInString(after)
+	if after != "" && strings.ContainsRune(":-–", next) {
+		return true
+	}
+
+	before := strings.TrimRightFunc(text[:start], unicode.IsSpace)
+	prev, _ := utf8.DecodeLastRuneInString(before)
+	clauseStart := before == "" || strings.ContainsRune(".!?,;:-–", prev)
+	if clauseStart && (after == "" || strings.ContainsRune(".!?,;", next) || unicode.IsDigit(next)) {
+		return true
+	}
+
+	if fields := strings.Fields(before); len(fields) > 0 && grantingWords[strings.ToLower(fields[len(fields)-1])] {
+		return true
+	}
+
+	found := text[start:end]
+	return strings.ToLower(found) != found && !atSentenceStart(text, start)
+}
+
+// isWholeWord reports whether [start, end) is not part of a longer word. Unlike \b this
+// works for names that start or end with punctuation ("No!") or non-ASCII letters.
+func isWholeWord(text string, start, end int) bool {
+	before, _ := utf8.DecodeLastRuneInString(text[:start])
+	after, _ := utf8.DecodeRuneInString(text[end:])
+	isWordRune := func(r rune) bool { return unicode.IsLetter(r) || unicode.IsDigit(r) }
+	first, _ := utf8.DecodeRuneInString(text[start:end])
+	last, _ := utf8.DecodeLastRuneInString(text[start:end])
+	return (start == 0 || !isWordRune(first) || !isWordRune(before)) &&
+		(end == len(text) || !isWordRune(last) || !isWordRune(after))
+}
+
+// capitalize upper-cases the first letter, which may be more than one byte
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if size == 0 {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,308 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+func TestLintText(t *testing.T) {
+	linter, err := NewLinter(nil)
+	if err != nil {
+		t.Fatalf("NewLinter() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		cardName  string
+		effect    string
+		wantRules []string
+		wantFixed string
+	}{
+		{
+			name:      "Clean effect",
+			cardName:  "Research",
+			effect:    "GUIDE 2, draw 1 card.",
+			wantRules: nil,
+			wantFixed: "GUIDE 2, draw 1 card.",
+		},
+		{
+			name:      "Timing capitalization",
+			cardName:  "Goblin Scout",
+			effect:    "On Play - Draw a card.",
+			wantRules: []string{RuleTiming},
+			wantFixed: "ON PLAY - Draw a card.",
+		},
+		{
+			name:      "Longest timing wins",
+			cardName:  "Path of the Seer",
+			effect:    "on upkeep - GUIDE 2.",
+			wantRules: []string{RuleTiming},
+			wantFixed: "ON UPKEEP - GUIDE 2.",
+		},
+		{
+			name:      "Keyword capitalization",
+			cardName:  "Enchanted Mace",
+			effect:    "Equipped creature gains critical.",
+			wantRules: []string{RuleKeyword},
+			wantFixed: "Equipped creature gains CRITICAL.",
+		},
+		{
+			name:      "Keyword as a clause of its own",
+			cardName:  "Swift Blade",
+			effect:    "Haste, vigilance.",
+			wantRules: []string{RuleKeyword, RuleKeyword},
+			wantFixed: "HASTE, VIGILANCE.",
+		},
+		{
+			name:      "Keywords used as plain words",
+			cardName:  "Quartermaster",
+			effect:    "During your upkeep, guide a creature to the command tent and offer it a bond.",
+			wantRules: nil,
+			wantFixed: "During your upkeep, guide a creature to the command tent and offer it a bond.",
+		},
+		{
+			name:      "Number words",
+			cardName:  "Insight",
+			effect:    "Draw two cards.",
+			wantRules: []string{RuleNumber},
+			wantFixed: "Draw 2 cards.",
+		},
+		{
+			name:      "Number word that is not a quantity",
+			cardName:  "Mana Portal",
+			effect:    "Replace one of your mana cards.",
+			wantRules: nil,
+			wantFixed: "Replace one of your mana cards.",
+		},
+		{
+			name:      "Self reference by name",
+			cardName:  "Corruption Engine",
+			effect:    "Add 1 counter to Corruption Engine.",
+			wantRules: []string{RuleSelfReference},
+			wantFixed: "Add 1 counter to this card.",
+		},
+		{
+			name:      "Self reference at sentence start",
+			cardName:  "War Golem",
+			effect:    "War Golem can't be blocked.",
+			wantRules: []string{RuleSelfReference},
+			wantFixed: "This card can't be blocked.",
+		},
+		{
+			name:      "Self reference ending in punctuation",
+			cardName:  "No!",
+			effect:    "Return No! to your hand.",
+			wantRules: []string{RuleSelfReference},
+			wantFixed: "Return this card to your hand.",
+		},
+		{
+			name:      "Self reference with a non-ASCII name",
+			cardName:  "Éclair",
+			effect:    "Éclair deals 2 damage.",
+			wantRules: []string{RuleSelfReference},
+			wantFixed: "This card deals 2 damage.",
+		},
+		{
+			name:      "Name inside a longer word",
+			cardName:  "Ash",
+			effect:    "Destroy 1 ashen creature.",
+			wantRules: nil,
+			wantFixed: "Destroy 1 ashen creature.",
+		},
+		{
+			name:      "Punctuation spacing and terminal period",
+			cardName:  "Tavern",
+			effect:    "Creatures you cast cost 1 less ;  draw 1 card ",
+			wantRules: []string{RulePunctuation, RulePunctuation, RulePunctuation, RulePunctuation},
+			wantFixed: "Creatures you cast cost 1 less; draw 1 card.",
+		},
+		{
+			name:      "Banned phrase",
+			cardName:  "Shields Up!",
+			effect:    "Target creature gains +0/2 until the end of the turn.",
+			wantRules: []string{RuleBannedPhrase},
+			wantFixed: "Target creature gains +0/2 until end of turn.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := linter.LintText(tt.cardName, tt.effect)
+
+			if len(issues) != len(tt.wantRules) {
+				t.Fatalf("LintText() returned %d issues, want %d: %+v", len(issues), len(tt.wantRules), issues)
+			}
+			for i, issue := range issues {
+				if issue.Rule != tt.wantRules[i] {
+					t.Errorf("issue %d rule = %s, want %s", i, issue.Rule, tt.wantRules[i])
+				}
+			}
+
+			if fixed := linter.Fix(tt.cardName, tt.effect); fixed != tt.wantFixed {
+				t.Errorf("Fix() = %q, want %q", fixed, tt.wantFixed)
+			}
+		})
+	}
+}
+
+func TestNumberWordsStyle(t *testing.T) {
+	guide := DefaultStyleGuide()
+	guide.NumberStyle = NumberWords
+
+	linter, err := NewLinter(guide)
+	if err != nil {
+		t.Fatalf("NewLinter() error = %v", err)
+	}
+
+	got := linter.Fix("War Cry", "2 of your creatures gain +1/+1 and draw 3 cards.")
+	want := "Two of your creatures gain +1/+1 and draw three cards."
+	if got != want {
+		t.Errorf("Fix() = %q, want %q", got, want)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	linter, err := NewLinter(nil)
+	if err != nil {
+		t.Fatalf("NewLinter() error = %v", err)
+	}
+
+	result := linter.Check(&card.CardDTO{
+		Type:   card.TypeSpell,
+		Name:   "Research",
+		Cost:   1,
+		Effect: "guide 2, draw one card",
+	})
+
+	if result.CardName != "Research" {
+		t.Errorf("CardName = %s, want Research", result.CardName)
+	}
+	if len(result.Issues) != 3 {
+		t.Errorf("got %d issues, want 3: %+v", len(result.Issues), result.Issues)
+	}
+	if result.Fixed != "GUIDE 2, draw 1 card." {
+		t.Errorf("Fixed = %q", result.Fixed)
+	}
+}
+
+func TestCheckComplexity(t *testing.T) {
+	linter, err := NewLinter(nil)
+	if err != nil {
+		t.Fatalf("NewLinter() error = %v", err)
+	}
+
+	complex := "ON PLAY - If you control a Goblin, you may choose target creature. When it dies, draw 2 cards; if it was a Demon, deal 2 damage to target player."
+	tests := []struct {
+		name   string
+		effect string
+		rarity string
+		want   bool
+	}{
+		{"Complex common", complex, "Common", true},
+		{"Complex rare", complex, "Rare", false},
+		{"Complex without rarity", complex, "", false},
+		{"Simple common", "Draw 2 cards.", "common", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := linter.Lint(&card.CardDTO{
+				Type:     card.TypeSpell,
+				Name:     "Test",
+				Effect:   tt.effect,
+				Metadata: map[string]string{"rarity": tt.rarity},
+			})
+
+			found := false
+			for _, issue := range issues {
+				found = found || issue.Rule == RuleComplexity
+			}
+			if found != tt.want {
+				t.Errorf("complexity issue = %v, want %v: %+v", found, tt.want, issues)
+			}
+		})
+	}
+
+	guide := DefaultStyleGuide()
+	guide.MaxCommonComplexity = -1
+	if _, err := NewLinter(guide); err == nil {
+		t.Error("NewLinter() should reject a negative complexity budget")
+	}
+}
+
+func TestApplyFixesSkipsOverlaps(t *testing.T) {
+	issues := []Issue{
+		{Fix: &Fix{Start: 0, End: 5, Replacement: "HELLO"}},
+		{Fix: &Fix{Start: 3, End: 8, Replacement: "ignored"}},
+		{Fix: &Fix{Start: 11, End: 11, Replacement: "!"}},
+	}
+
+	if got := ApplyFixes("hello world", issues); got != "HELLO world!" {
+		t.Errorf("ApplyFixes() = %q", got)
+	}
+}
+
+func TestLoadStyleGuide(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("YAML", func(t *testing.T) {
+		path := filepath.Join(dir, "style.yaml")
+		content := "keywords: [CRITICAL]\nnumber_style: words\nbanned_phrases:\n  - phrase: any creature\n    replacement: target creature\n"
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		guide, err := LoadStyleGuide(path)
+		if err != nil {
+			t.Fatalf("LoadStyleGuide() error = %v", err)
+		}
+		if guide.NumberStyle != NumberWords || len(guide.Keywords) != 1 || len(guide.BannedPhrases) != 1 {
+			t.Errorf("unexpected guide: %+v", guide)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		path := filepath.Join(dir, "style.json")
+		if err := os.WriteFile(path, []byte(`{"self_reference": "this creature"}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		guide, err := LoadStyleGuide(path)
+		if err != nil {
+			t.Fatalf("LoadStyleGuide() error = %v", err)
+		}
+		if guide.SelfReference != "this creature" {
+			t.Errorf("SelfReference = %q", guide.SelfReference)
+		}
+	})
+
+	t.Run("Invalid number style", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.yaml")
+		if err := os.WriteFile(path, []byte("number_style: roman\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := LoadStyleGuide(path); err == nil {
+			t.Error("expected error for invalid number style")
+		}
+	})
+}
+
+func TestCapitalize(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"", ""},
+		{"this card", "This card"},
+		{"éclair", "Éclair"},
+		{"ñ", "Ñ"},
+	}
+	for _, tt := range tests {
+		if got := capitalize(tt.in); got != tt.want {
+			t.Errorf("capitalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NumberStyle controls how small quantities are written in effect text
+type NumberStyle string
+
+const (
+	NumberDigits NumberStyle = "digits" // "draw 2 cards"
+	NumberWords  NumberStyle = "words"  // "draw two cards"
+)
+
+// BannedPhrase is a phrasing the style guide rejects, with an optional replacement
+type BannedPhrase struct {
+	Phrase      string `yaml:"phrase" json:"phrase"`
+	Replacement string `yaml:"replacement" json:"replacement"`
+	Reason      string `yaml:"reason" json:"reason"`
+}
+
+// StyleGuide holds the templating conventions effect text is checked against
+type StyleGuide struct {
+	// Timings are trigger words that must be written in their canonical form (e.g. "ON ANY CLASH").
+	// Like keywords, they are only checked where used as one, so "during your upkeep" is plain prose.
+	Timings []string `yaml:"timings" json:"timings"`
+
+	// Keywords are game keywords that must be written in their canonical form (e.g. "CRITICAL")
+	Keywords []string `yaml:"keywords" json:"keywords"`
+
+	// NumberStyle selects digits or words for quantities from zero to ten
+	NumberStyle NumberStyle `yaml:"number_style" json:"number_style"`
+
+	// SelfReference replaces a card's own name in its effect text; empty disables the check
+	SelfReference string `yaml:"self_reference" json:"self_reference"`
+
+	// RequireTerminalPeriod requires effect text to end with punctuation
+	RequireTerminalPeriod bool `yaml:"require_terminal_period" json:"require_terminal_period"`
+
+	// BannedPhrases lists phrasings that should not appear in effect text
+	BannedPhrases []BannedPhrase `yaml:"banned_phrases" json:"banned_phrases"`
+
+	// MaxCommonComplexity is the highest effect complexity score allowed on common cards; 0 disables the check
+	MaxCommonComplexity int `yaml:"max_common_complexity" json:"max_common_complexity"`
+}
+
+// DefaultStyleGuide returns the house style used when no guide file is configured
+func DefaultStyleGuide() *StyleGuide {
+	return &StyleGuide{
+		Timings: []string{
+			"ON ANY CLASH",
+			"ON ATTACK",
+			"ON PLAY",
+			"ON UPKEEP",
+			"ON CHARGE",
+			"END PHASE",
+			"END OF ANY TURN",
+			"UPKEEP",
+			"ETB",
+		},
+		Keywords: []string{
+			"CRITICAL",
+			"INDESTRUCTIBLE",
+			"VIGILANCE",
+			"BREAKTHROUGH",
+			"HASTE",
+			"RELOAD",
+			"OFFER",
+			"GUIDE",
+			"COMMAND",
+			"BOND",
+		},
+		NumberStyle:           NumberDigits,
+		SelfReference:         "this card",
+		RequireTerminalPeriod: true,
+		MaxCommonComplexity:   8,
+		BannedPhrases: []BannedPhrase{
+			{Phrase: "until the end of the turn", Replacement: "until end of turn", Reason: "use the short duration form"},
+			{Phrase: "until the end of turn", Replacement: "until end of turn", Reason: "use the short duration form"},
+			{Phrase: "select a creature", Replacement: "target creature", Reason: "targeting is written as \"target\""},
+			{Phrase: "selected creature", Replacement: "target creature", Reason: "targeting is written as \"target\""},
+			{Phrase: "can not", Replacement: "can't", Reason: "use the contraction"},
+		},
+	}
+}
+
+// LoadStyleGuide reads a style guide from a YAML or JSON file
+func LoadStyleGuide(path string) (*StyleGuide, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read style guide: %w", err)
+	}
+
+	guide := &StyleGuide{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, guide)
+	default:
+		err = yaml.Unmarshal(data, guide)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse style guide %s: %w", path, err)
+	}
+
+	if err := guide.Validate(); err != nil {
+		return nil, err
+	}
+	return guide, nil
+}
+
+// Validate checks that the style guide is usable
+func (g *StyleGuide) Validate() error {
+	switch g.NumberStyle {
+	case "", NumberDigits, NumberWords:
+	default:
+		return fmt.Errorf("invalid number style: %s", g.NumberStyle)
+	}
+
+	for _, phrase := range g.BannedPhrases {
+		if strings.TrimSpace(phrase.Phrase) == "" {
+			return fmt.Errorf("banned phrase cannot be empty")
+		}
+	}
+
+	if g.MaxCommonComplexity < 0 {
+		return fmt.Errorf("max common complexity cannot be negative: %d", g.MaxCommonComplexity)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package llm
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"sync"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+)
+
+// DefaultCacheSize is the number of analyses a client caches when none is configured
+const DefaultCacheSize = 1024
+
+// Cache holds up to a fixed number of effect analyses keyed by the SHA-256 hash of the
+// effect text, evicting the least recently used analysis when full
+type Cache struct {
+	maxEntries int
+	entries    map[string]*list.Element
+	order      *list.List // front is most recently used
+	mutex      sync.Mutex
+}
+
+// cacheEntry is an analysis and its key, kept in the recency list
+type cacheEntry struct {
+	key      string
+	analysis *types.EffectAnalysis
+}
+
+// NewCache creates an empty cache holding at most maxEntries analyses; zero or less means DefaultCacheSize
+func NewCache(maxEntries int) *Cache {
+	if maxEntries <= 0 {
+		maxEntries = DefaultCacheSize
+	}
+	return &Cache{
+		maxEntries: maxEntries,
+		entries:    make(map[string]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// EffectKey returns the cache key of an effect text
+func EffectKey(effect string) string {
+	sum := sha256.Sum256([]byte(effect))
+	return hex.EncodeToString(sum[:])
+}
+
+// Get returns a copy of the cached analysis of the effect text
+func (c *Cache) Get(effect string) (*types.EffectAnalysis, bool) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	element, ok := c.entries[EffectKey(effect)]
+	if !ok {
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return copyAnalysis(element.Value.(*cacheEntry).analysis), true
+}
+
+// Put caches the analysis of the effect text, evicting the least recently used analysis when full
+func (c *Cache) Put(effect string, analysis *types.EffectAnalysis) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	key := EffectKey(effect)
+	if element, ok := c.entries[key]; ok {
+		element.Value.(*cacheEntry).analysis = copyAnalysis(analysis)
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.order.PushFront(&cacheEntry{key: key, analysis: copyAnalysis(analysis)})
+	for c.order.Len() > c.maxEntries {
+		oldest := c.order.Back()
+		c.order.Remove(oldest)
+		delete(c.entries, oldest.Value.(*cacheEntry).key)
+	}
+}
+
+// Len returns the number of cached analyses
+func (c *Cache) Len() int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return len(c.entries)
+}
+
+// copyAnalysis keeps callers from modifying cached tag slices
+func copyAnalysis(analysis *types.EffectAnalysis) *types.EffectAnalysis {
+	tags := make([]types.Tag, len(analysis.Tags))
+	copy(tags, analysis.Tags)
+	return &types.EffectAnalysis{Summary: analysis.Summary, Tags: tags}
+}
@@ -0,0 +1,206 @@
+package llm
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+)
+
+// DefaultModel is used when no model is configured
+const DefaultModel = "anthropic/claude-3.5-sonnet"
+
+// systemPrompt instructs the model to answer with a JSON effect analysis
+const systemPrompt = `You analyze effect text from a trading card game.
+Reply with a single JSON object and nothing else:
+{"summary": "<one sentence plain-language summary>", "tags": [{"name": "<UPPER_SNAKE_CASE>", "category": "<category>", "weight": <1-3>}]}
+Valid categories: TRIBAL, MECHANIC, STRATEGY, COST, SYNERGY, COMBO, ARCHETYPE, TIMING.
+Suggest at most five tags that describe what the effect does.`
+
+// Config holds the settings of an OpenAI-compatible chat completions endpoint
+type Config struct {
+	BaseURL string // e.g. https://openrouter.ai/api/v1
+	APIKey  string
+	Model   string
+	Timeout time.Duration
+
+	// CacheSize caps the number of cached analyses; zero means DefaultCacheSize
+	CacheSize int
+}
+
+// ConfigFromEnv reads LLM_BASE_URL, LLM_API_KEY and LLM_MODEL.
+// It returns nil when LLM_BASE_URL is not set.
+func ConfigFromEnv() *Config {
+	baseURL := os.Getenv("LLM_BASE_URL")
+	if baseURL == "" {
+		return nil
+	}
+
+	model := os.Getenv("LLM_MODEL")
+	if model == "" {
+		model = DefaultModel
+	}
+
+	return &Config{
+		BaseURL: baseURL,
+		APIKey:  os.Getenv("LLM_API_KEY"),
+		Model:   model,
+		Timeout: 30 * time.Second,
+	}
+}
+
+// Client implements types.EffectAnalyzer against an OpenAI-compatible API.
+// Responses are cached by effect text hash.
+type Client struct {
+	config     Config
+	httpClient *http.Client
+	cache      *Cache
+}
+
+// NewClient creates a client for the given endpoint
+func NewClient(cfg Config) *Client {
+	if cfg.Model == "" {
+		cfg.Model = DefaultModel
+	}
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 30 * time.Second
+	}
+	cfg.BaseURL = strings.TrimRight(cfg.BaseURL, "/")
+
+	return &Client{
+		config:     cfg,
+		httpClient: &http.Client{Timeout: cfg.Timeout},
+		cache:      NewCache(cfg.CacheSize),
+	}
+}
+
+// chatMessage is a single message of a chat completion request or response
+type chatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// chatRequest is the body of POST /chat/completions
+type chatRequest struct {
+	Model          string            `json:"model"`
+	Messages       []chatMessage     `json:"messages"`
+	Temperature    float64           `json:"temperature"`
+	ResponseFormat map[string]string `json:"response_format,omitempty"`
+}
+
+// chatResponse is the subset of the chat completion response the client reads
+type chatResponse struct {
+	Choices []struct {
+		Message chatMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error,omitempty"`
+}
+
+// AnalyzeEffect returns the model's summary and tag suggestions for the effect text
+func (c *Client) AnalyzeEffect(ctx context.Context, effect string) (*types.EffectAnalysis, error) {
+	effect = strings.TrimSpace(effect)
+	if effect == "" {
+		return &types.EffectAnalysis{}, nil
+	}
+
+	if analysis, ok := c.cache.Get(effect); ok {
+		return analysis, nil
+	}
+
+	content, err := c.complete(ctx, effect)
+	if err != nil {
+		return nil, err
+	}
+
+	analysis, err := parseAnalysis(content)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.Put(effect, analysis)
+	return analysis, nil
+}
+
+// complete sends the effect text to the chat completions endpoint and returns the reply
+func (c *Client) complete(ctx context.Context, effect string) (string, error) {
+	body, err := json.Marshal(chatRequest{
+		Model: c.config.Model,
+		Messages: []chatMessage{
+			{Role: "system", Content: systemPrompt},
+			{Role: "user", Content: effect},
+		},
+		ResponseFormat: map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.config.BaseURL+"/chat/completions", bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.config.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+c.config.APIKey)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to call model: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var parsed chatResponse
+	if err := json.Unmarshal(data, &parsed); err != nil {
+		return "", fmt.Errorf("failed to decode response (status %d): %w", resp.StatusCode, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if parsed.Error != nil {
+			return "", fmt.Errorf("model request failed with status %d: %s", resp.StatusCode, parsed.Error.Message)
+		}
+		return "", fmt.Errorf("model request failed with status %d", resp.StatusCode)
+	}
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("model returned no choices")
+	}
+	return parsed.Choices[0].Message.Content, nil
+}
+
+// parseAnalysis decodes the model's JSON reply, keeping only valid tag suggestions
+func parseAnalysis(content string) (*types.EffectAnalysis, error) {
+	// Models sometimes wrap JSON in a markdown code fence
+	content = strings.TrimSpace(content)
+	content = strings.TrimPrefix(content, "```json")
+	content = strings.TrimPrefix(content, "```")
+	content = strings.TrimSuffix(content, "```")
+
+	var raw types.EffectAnalysis
+	if err := json.Unmarshal([]byte(content), &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode effect analysis: %w", err)
+	}
+
+	validator := types.NewTagValidator()
+	analysis := &types.EffectAnalysis{Summary: strings.TrimSpace(raw.Summary), Tags: make([]types.Tag, 0, len(raw.Tags))}
+	for _, tag := range raw.Tags {
+		tag.Name = strings.ToUpper(strings.TrimSpace(tag.Name))
+		tag.Category = types.TagCategory(strings.ToUpper(string(tag.Category)))
+		if validator.ValidateTag(tag) == nil {
+			analysis.Tags = append(analysis.Tags, tag)
+		}
+	}
+	return analysis, nil
+}
@@ -0,0 +1,158 @@
+package llm
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/llm/llmtest"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+)
+
+func tagNames(tags []types.Tag) string {
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		names = append(names, tag.Name)
+	}
+	return strings.Join(names, ",")
+}
+
+func TestClientAnalyzeEffect(t *testing.T) {
+	fake := llmtest.NewServer()
+	defer fake.Close()
+	client := NewClient(Config{BaseURL: fake.URL, Model: "fake"})
+
+	tests := []struct {
+		name     string
+		effect   string
+		wantTags string
+		requests int
+	}{
+		{"Draw", "ON PLAY - Draw 2 cards.", "CARD_DRAW", 1},
+		{"Two mechanics", "Deal 3 damage to target creature. Gain 3 life.", "DIRECT_DAMAGE,LIFEGAIN", 2},
+		{"Cached", "ON PLAY - Draw 2 cards.", "CARD_DRAW", 2},
+		{"Cached after trimming", "  ON PLAY - Draw 2 cards.\n", "CARD_DRAW", 2},
+		{"Nothing recognized", "Flying.", "", 3},
+		{"Empty effect", "", "", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, err := client.AnalyzeEffect(context.Background(), tt.effect)
+			if err != nil {
+				t.Fatalf("AnalyzeEffect() error = %v", err)
+			}
+			if got := tagNames(analysis.Tags); got != tt.wantTags {
+				t.Errorf("tags = %q, want %q", got, tt.wantTags)
+			}
+			if tt.effect != "" && analysis.Summary == "" {
+				t.Error("summary is empty")
+			}
+			if got := fake.Requests(); got != tt.requests {
+				t.Errorf("server handled %d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+
+	// Cached analyses are copies
+	first, _ := client.AnalyzeEffect(context.Background(), "ON PLAY - Draw 2 cards.")
+	first.Tags[0].Name = "CHANGED"
+	second, _ := client.AnalyzeEffect(context.Background(), "ON PLAY - Draw 2 cards.")
+	if second.Tags[0].Name != "CARD_DRAW" {
+		t.Errorf("cached analysis was modified: %+v", second.Tags)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		wantErr string
+	}{
+		{"Error status", http.StatusUnauthorized, `{"error": {"message": "invalid api key"}}`, "invalid api key"},
+		{"No choices", http.StatusOK, `{"choices": []}`, "no choices"},
+		{"Non-JSON reply", http.StatusOK, `{"choices": [{"message": {"role": "assistant", "content": "Sure!"}}]}`, "failed to decode effect analysis"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if got := r.Header.Get("Authorization"); got != "Bearer secret" {
+					t.Errorf("Authorization = %q", got)
+				}
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			client := NewClient(Config{BaseURL: server.URL + "/", APIKey: "secret"})
+			_, err := client.AnalyzeEffect(context.Background(), "Draw a card.")
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("AnalyzeEffect() error = %v, want %q", err, tt.wantErr)
+			}
+			if client.cache.Len() != 0 {
+				t.Error("failed analysis was cached")
+			}
+		})
+	}
+}
+
+func TestParseAnalysis(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantTags string
+		wantErr  bool
+	}{
+		{"Plain JSON", `{"summary": "Draws.", "tags": [{"name": "card_draw", "category": "mechanic"}]}`, "CARD_DRAW", false},
+		{"Code fence", "```json\n{\"summary\": \"Draws.\", \"tags\": [{\"name\": \"CARD_DRAW\", \"category\": \"MECHANIC\"}]}\n```", "CARD_DRAW", false},
+		{"Invalid tags dropped", `{"summary": "x", "tags": [{"name": "", "category": "MECHANIC"}, {"name": "FOO", "category": "BOGUS"}, {"name": "RAMP", "category": "STRATEGY"}]}`, "RAMP", false},
+		{"Not JSON", "Draws a card.", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			analysis, err := parseAnalysis(tt.content)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseAnalysis() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && tagNames(analysis.Tags) != tt.wantTags {
+				t.Errorf("tags = %q, want %q", tagNames(analysis.Tags), tt.wantTags)
+			}
+		})
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	cache := NewCache(2)
+	analysis := func(summary string) *types.EffectAnalysis {
+		return &types.EffectAnalysis{Summary: summary}
+	}
+
+	cache.Put("a", analysis("A"))
+	cache.Put("b", analysis("B"))
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("a should be cached")
+	}
+	cache.Put("c", analysis("C"))
+
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+	if _, ok := cache.Get("b"); ok {
+		t.Error("least recently used b should have been evicted")
+	}
+	for _, effect := range []string{"a", "c"} {
+		if _, ok := cache.Get(effect); !ok {
+			t.Errorf("%s should be cached", effect)
+		}
+	}
+
+	cache.Put("a", analysis("A2"))
+	if got, _ := cache.Get("a"); got.Summary != "A2" || cache.Len() != 2 {
+		t.Errorf("Get(a) = %+v, Len() = %d after replacing", got, cache.Len())
+	}
+}
@@ -0,0 +1,95 @@
+// Package llmtest provides a fake OpenAI-compatible endpoint for tests
+package llmtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync/atomic"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+)
+
+// rules map effect text to the tags the fake model suggests
+var rules = []struct {
+	pattern *regexp.Regexp
+	tag     types.Tag
+	summary string
+}{
+	{regexp.MustCompile(`(?i)\bdraws? (a|\d+) cards?\b`), types.Tag{Name: "CARD_DRAW", Category: types.TagMechanic, Weight: 2}, "draws cards"},
+	{regexp.MustCompile(`(?i)\bdeals? \d+ damage\b`), types.Tag{Name: "DIRECT_DAMAGE", Category: types.TagMechanic, Weight: 2}, "deals damage"},
+	{regexp.MustCompile(`(?i)\bdestroy target\b`), types.Tag{Name: "REMOVAL", Category: types.TagStrategy, Weight: 3}, "removes a threat"},
+	{regexp.MustCompile(`(?i)\bgain \d+ life\b`), types.Tag{Name: "LIFEGAIN", Category: types.TagMechanic, Weight: 1}, "gains life"},
+	{regexp.MustCompile(`(?i)\bcreate .*tokens?\b`), types.Tag{Name: "TOKEN_GENERATOR", Category: types.TagMechanic, Weight: 2}, "creates tokens"},
+	{regexp.MustCompile(`(?i)\bsacrifice\b`), types.Tag{Name: "SACRIFICE", Category: types.TagMechanic, Weight: 2}, "sacrifices permanents"},
+}
+
+// message is a single message of a chat completion request or response
+type message struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// Server is a deterministic local stand-in for an OpenAI-compatible endpoint.
+// It suggests tags from a few fixed patterns and counts the completions it served.
+// Point an llm.Config's BaseURL at its URL.
+type Server struct {
+	*httptest.Server
+	requests atomic.Int64
+}
+
+// NewServer starts a fake chat completions server; close it when done
+func NewServer() *Server {
+	fake := &Server{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/chat/completions", fake.handleCompletion)
+	fake.Server = httptest.NewServer(mux)
+	return fake
+}
+
+// Requests returns the number of completions served
+func (f *Server) Requests() int {
+	return int(f.requests.Load())
+}
+
+func (f *Server) handleCompletion(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Messages []message `json:"messages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Messages) == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]interface{}{"error": map[string]string{"message": "invalid request"}})
+		return
+	}
+	f.requests.Add(1)
+
+	content, _ := json.Marshal(Analysis(req.Messages[len(req.Messages)-1].Content))
+	resp := map[string]interface{}{
+		"choices": []map[string]interface{}{
+			{"message": message{Role: "assistant", Content: string(content)}},
+		},
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+// Analysis is the analysis the fake server returns for an effect text
+func Analysis(effect string) *types.EffectAnalysis {
+	analysis := &types.EffectAnalysis{Tags: make([]types.Tag, 0)}
+	var parts []string
+	for _, rule := range rules {
+		if rule.pattern.MatchString(effect) {
+			analysis.Tags = append(analysis.Tags, rule.tag)
+			parts = append(parts, rule.summary)
+		}
+	}
+
+	if len(parts) == 0 {
+		analysis.Summary = "This card has no recognized effect."
+	} else {
+		analysis.Summary = "This card " + strings.Join(parts, " and ") + "."
+	}
+	return analysis
+}
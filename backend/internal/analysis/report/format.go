@@ -0,0 +1,265 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/archetype"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/balance"
+)
+
+// Format is a report output format
+type Format string
+
+const (
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+	FormatHTML     Format = "html"
+)
+
+// ParseFormat converts a user-supplied format name to a Format
+func ParseFormat(value string) (Format, error) {
+	switch strings.ToLower(value) {
+	case "json":
+		return FormatJSON, nil
+	case "markdown", "md":
+		return FormatMarkdown, nil
+	case "html":
+		return FormatHTML, nil
+	default:
+		return "", fmt.Errorf("unsupported report format: %s", value)
+	}
+}
+
+// Write renders the report in the given format
+func Write(w io.Writer, r *Report, format Format) error {
+	switch format {
+	case FormatJSON:
+		return WriteJSON(w, r)
+	case FormatMarkdown:
+		return WriteMarkdown(w, r)
+	case FormatHTML:
+		return WriteHTML(w, r)
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+}
+
+// WriteJSON writes the report as indented JSON
+func WriteJSON(w io.Writer, r *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(r); err != nil {
+		return fmt.Errorf("failed to encode report: %w", err)
+	}
+	return nil
+}
+
+// WriteMarkdown writes the report as Markdown tables with text bar charts
+func WriteMarkdown(w io.Writer, r *Report) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n", r.Title)
+	fmt.Fprintf(&b, "Generated %s — %d cards\n\n", r.GeneratedAt.Format("2006-01-02 15:04"), r.TotalCards)
+
+	writeMarkdownFrequencies(&b, "Card Types", "Type", r.TypeCounts)
+	writeMarkdownBins(&b, "Cost Curve", "Cost", r.CostCurve)
+	for _, curve := range r.TypeCurves {
+		writeMarkdownBins(&b, fmt.Sprintf("Cost Curve: %s", curve.Type), "Cost", curve.Bins)
+	}
+	writeMarkdownBins(&b, "Attack Distribution", "Attack", r.AttackDistribution)
+	writeMarkdownBins(&b, "Defense Distribution", "Defense", r.DefenseDistribution)
+	writeMarkdownFrequencies(&b, "Traits", "Trait", r.Traits)
+	writeMarkdownFrequencies(&b, "Keywords", "Keyword", r.Keywords)
+	writeMarkdownFrequencies(&b, "Tag Categories", "Category", r.TagCategories)
+	writeMarkdownFrequencies(&b, "Tags", "Tag", r.Tags)
+	writeMarkdownArchetype(&b, r.Archetype)
+	writeMarkdownBalance(&b, r.Balance)
+
+	b.WriteString("## Gaps\n\n")
+	if len(r.Gaps) == 0 {
+		b.WriteString("No gaps found.\n\n")
+	}
+	for _, gap := range r.Gaps {
+		fmt.Fprintf(&b, "- **%s**: %s\n", gap.Kind, gap.Message)
+	}
+	if len(r.Gaps) > 0 {
+		b.WriteString("\n")
+	}
+
+	if len(r.Errors) > 0 {
+		b.WriteString("## Errors\n\n")
+		for _, e := range r.Errors {
+			fmt.Fprintf(&b, "- %s\n", e)
+		}
+		b.WriteString("\n")
+	}
+
+	if _, err := io.WriteString(w, b.String()); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+func writeMarkdownArchetype(b *strings.Builder, result *archetype.Result) {
+	if result == nil {
+		return
+	}
+	fmt.Fprintf(b, "## Archetype: %s\n\n| Archetype | Probability | Reasons |\n| --- | ---: | --- |\n", result.Archetype)
+	for _, p := range result.Probabilities {
+		fmt.Fprintf(b, "| %s | %.0f%% | %s |\n", p.Archetype, p.Probability*100, archetypeReasons(p))
+	}
+	b.WriteString("\n")
+}
+
+func writeMarkdownBalance(b *strings.Builder, result *balance.Report) {
+	if result == nil {
+		return
+	}
+	b.WriteString("## Balance Outliers\n\n")
+	if len(result.Outliers) == 0 {
+		b.WriteString("No outliers found.\n\n")
+		return
+	}
+	b.WriteString("| Card | Cost | Power | Expected | Verdict |\n| --- | ---: | ---: | ---: | --- |\n")
+	for _, a := range result.Outliers {
+		fmt.Fprintf(b, "| %s | %d | %.1f | %.1f | %s |\n", a.CardName, a.Cost, a.Power, a.Expected, a.Verdict)
+	}
+	b.WriteString("\n")
+}
+
+// archetypeReasons joins the explanations of an archetype's score
+func archetypeReasons(p archetype.Probability) string {
+	reasons := make([]string, 0, len(p.Explanations))
+	for _, e := range p.Explanations {
+		reasons = append(reasons, e.Reason)
+	}
+	return strings.Join(reasons, "; ")
+}
+
+func writeMarkdownBins(b *strings.Builder, title, label string, bins []Bin) {
+	if len(bins) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "## %s\n\n| %s | Count | |\n| ---: | ---: | --- |\n", title, label)
+	for _, bin := range bins {
+		fmt.Fprintf(b, "| %d | %d | %s |\n", bin.Value, bin.Count, strings.Repeat("█", bin.Count))
+	}
+	b.WriteString("\n")
+}
+
+func writeMarkdownFrequencies(b *strings.Builder, title, label string, frequencies []Frequency) {
+	if len(frequencies) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "## %s\n\n| %s | Count |\n| --- | ---: |\n", title, label)
+	for _, f := range frequencies {
+		fmt.Fprintf(b, "| %s | %d |\n", f.Name, f.Count)
+	}
+	b.WriteString("\n")
+}
+
+// WriteHTML writes the report as a standalone HTML page
+func WriteHTML(w io.Writer, r *Report) error {
+	if err := htmlTemplate.Execute(w, r); err != nil {
+		return fmt.Errorf("failed to render HTML report: %w", err)
+	}
+	return nil
+}
+
+// barWidth scales a count to a percentage of the largest bin
+func barWidth(count int, bins []Bin) int {
+	max := 0
+	for _, bin := range bins {
+		if bin.Count > max {
+			max = bin.Count
+		}
+	}
+	if max == 0 {
+		return 0
+	}
+	return count * 100 / max
+}
+
+// histogramView and frequencyView pass a table label alongside its rows to the HTML templates
+type histogramView struct {
+	Label string
+	Bins  []Bin
+}
+
+type frequencyView struct {
+	Label string
+	Items []Frequency
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"barWidth": barWidth,
+	"percent": func(value float64) string {
+		return fmt.Sprintf("%.0f%%", value*100)
+	},
+	"archetypeReasons": archetypeReasons,
+	"histogram": func(label string, bins []Bin) histogramView {
+		return histogramView{Label: label, Bins: bins}
+	},
+	"frequencyTable": func(label string, items []Frequency) frequencyView {
+		return frequencyView{Label: label, Items: items}
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; color: #222; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { padding: 0.2em 0.8em; border-bottom: 1px solid #ddd; text-align: left; }
+.bar { background: #4a7ebb; height: 0.9em; }
+.gap { color: #b03a2e; }
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+<p>Generated {{.GeneratedAt.Format "2006-01-02 15:04"}} &mdash; {{.TotalCards}} cards</p>
+{{define "bins"}}<table><tr><th>{{.Label}}</th><th>Count</th><th></th></tr>
+{{range .Bins}}<tr><td>{{.Value}}</td><td>{{.Count}}</td><td style="width: 300px"><div class="bar" style="width: {{barWidth .Count $.Bins}}%"></div></td></tr>
+{{end}}</table>{{end}}
+{{define "frequencies"}}<table><tr><th>{{.Label}}</th><th>Count</th></tr>
+{{range .Items}}<tr><td>{{.Name}}</td><td>{{.Count}}</td></tr>
+{{end}}</table>{{end}}
+<h2>Card Types</h2>
+{{template "frequencies" (frequencyTable "Type" .TypeCounts)}}
+<h2>Cost Curve</h2>
+{{template "bins" (histogram "Cost" .CostCurve)}}
+{{range .TypeCurves}}<h3>{{.Type}}</h3>
+{{template "bins" (histogram "Cost" .Bins)}}
+{{end}}
+{{if .AttackDistribution}}<h2>Attack Distribution</h2>
+{{template "bins" (histogram "Attack" .AttackDistribution)}}{{end}}
+{{if .DefenseDistribution}}<h2>Defense Distribution</h2>
+{{template "bins" (histogram "Defense" .DefenseDistribution)}}{{end}}
+{{if .Traits}}<h2>Traits</h2>
+{{template "frequencies" (frequencyTable "Trait" .Traits)}}{{end}}
+{{if .Keywords}}<h2>Keywords</h2>
+{{template "frequencies" (frequencyTable "Keyword" .Keywords)}}{{end}}
+{{if .TagCategories}}<h2>Tag Categories</h2>
+{{template "frequencies" (frequencyTable "Category" .TagCategories)}}{{end}}
+{{if .Tags}}<h2>Tags</h2>
+{{template "frequencies" (frequencyTable "Tag" .Tags)}}{{end}}
+{{with .Archetype}}<h2>Archetype: {{.Archetype}}</h2>
+<table><tr><th>Archetype</th><th>Probability</th><th>Reasons</th></tr>
+{{range .Probabilities}}<tr><td>{{.Archetype}}</td><td>{{percent .Probability}}</td><td>{{archetypeReasons .}}</td></tr>
+{{end}}</table>{{end}}
+{{with .Balance}}<h2>Balance Outliers</h2>
+{{if .Outliers}}<table><tr><th>Card</th><th>Cost</th><th>Power</th><th>Expected</th><th>Verdict</th></tr>
+{{range .Outliers}}<tr><td>{{.CardName}}</td><td>{{.Cost}}</td><td>{{printf "%.1f" .Power}}</td><td>{{printf "%.1f" .Expected}}</td><td>{{.Verdict}}</td></tr>
+{{end}}</table>{{else}}<p>No outliers found.</p>{{end}}{{end}}
+<h2>Gaps</h2>
+{{if .Gaps}}<ul>{{range .Gaps}}<li class="gap"><strong>{{.Kind}}</strong>: {{.Message}}</li>{{end}}</ul>{{else}}<p>No gaps found.</p>{{end}}
+{{if .Errors}}<h2>Errors</h2>
+<ul>{{range .Errors}}<li>{{.}}</li>{{end}}</ul>{{end}}
+</body>
+</html>
+`))
@@ -0,0 +1,291 @@
+// Package report builds set-level analytics for a pool of cards
+package report
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/archetype"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/balance"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// Tagger generates tags for a card; satisfied by tagger.CardTagger
+type Tagger interface {
+	GenerateTags(card interface{}) ([]types.Tag, error)
+}
+
+// Bin is a single histogram bucket
+type Bin struct {
+	Value int `json:"value"`
+	Count int `json:"count"`
+}
+
+// Frequency is how often a named value occurs in the set
+type Frequency struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+// Gap is a hole in the set's shape worth a designer's attention
+type Gap struct {
+	Kind    string `json:"kind"`
+	Message string `json:"message"`
+}
+
+// Gap kinds
+const (
+	GapCost     = "cost"
+	GapType     = "type"
+	GapTag      = "tag"
+	GapCreature = "creature"
+)
+
+// TypeCurve is the cost histogram of one card type
+type TypeCurve struct {
+	Type card.CardType `json:"type"`
+	Bins []Bin         `json:"bins"`
+}
+
+// Report describes the shape of a set of cards
+type Report struct {
+	Title       string    `json:"title"`
+	GeneratedAt time.Time `json:"generated_at"`
+	TotalCards  int       `json:"total_cards"`
+
+	TypeCounts []Frequency `json:"type_counts"`
+	CostCurve  []Bin       `json:"cost_curve"`
+	TypeCurves []TypeCurve `json:"type_curves"`
+
+	AttackDistribution  []Bin `json:"attack_distribution"`
+	DefenseDistribution []Bin `json:"defense_distribution"`
+
+	Traits        []Frequency `json:"traits"`
+	Keywords      []Frequency `json:"keywords"`
+	TagCategories []Frequency `json:"tag_categories"`
+	Tags          []Frequency `json:"tags"`
+
+	Archetype *archetype.Result `json:"archetype,omitempty"`
+	Balance   *balance.Report   `json:"balance,omitempty"`
+
+	Gaps   []Gap    `json:"gaps"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Options controls gap detection and balance analysis
+type Options struct {
+	// MinTypeShare is the fraction of the set below which a card type is reported as thin
+	MinTypeShare float64
+
+	// MaxCurveCost is the highest cost checked for empty curve slots
+	MaxCurveCost int
+
+	// RequiredTags are tags a healthy set should contain at least once
+	RequiredTags []string
+
+	// Balance controls the cost curve fit and outlier threshold of the balance section
+	Balance balance.Options
+}
+
+// DefaultOptions returns the options used when none are configured
+func DefaultOptions() Options {
+	return Options{
+		MinTypeShare: 0.05,
+		MaxCurveCost: 6,
+		RequiredTags: []string{"REMOVAL", "CARD_ADVANTAGE"},
+		Balance:      balance.DefaultOptions(),
+	}
+}
+
+// AllTypes lists the card types a set is expected to contain
+var AllTypes = []card.CardType{
+	card.TypeCreature,
+	card.TypeSpell,
+	card.TypeArtifact,
+	card.TypeIncantation,
+	card.TypeAnthem,
+}
+
+// Builder builds reports from cards
+type Builder struct {
+	tagger  Tagger
+	options Options
+}
+
+// NewBuilder creates a report builder; a nil tagger skips tag statistics
+func NewBuilder(tagger Tagger, options Options) *Builder {
+	return &Builder{
+		tagger:  tagger,
+		options: options,
+	}
+}
+
+// FromStore builds a report over every card in the store
+func (b *Builder) FromStore(s store.Store, title string) (*Report, error) {
+	cards, err := s.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %w", err)
+	}
+
+	dtos := make([]*card.CardDTO, 0, len(cards))
+	for _, c := range cards {
+		dtos = append(dtos, c.ToDTO())
+	}
+	return b.Build(dtos, title), nil
+}
+
+// Build computes the report for the given cards
+func (b *Builder) Build(cards []*card.CardDTO, title string) *Report {
+	report := &Report{
+		Title:       title,
+		GeneratedAt: time.Now(),
+		TotalCards:  len(cards),
+	}
+
+	typeCounts := make(map[string]int)
+	costs := make(map[int]int)
+	typeCosts := make(map[card.CardType]map[int]int)
+	attacks := make(map[int]int)
+	defenses := make(map[int]int)
+	traits := make(map[string]int)
+	keywords := make(map[string]int)
+	categories := make(map[string]int)
+	tags := make(map[string]int)
+
+	for _, c := range cards {
+		typeCounts[string(c.Type)]++
+		costs[c.Cost]++
+		if typeCosts[c.Type] == nil {
+			typeCosts[c.Type] = make(map[int]int)
+		}
+		typeCosts[c.Type][c.Cost]++
+
+		if c.Type == card.TypeCreature {
+			attacks[c.Attack]++
+			defenses[c.Defense]++
+		}
+		if c.Trait != "" {
+			traits[c.Trait]++
+		}
+		for _, keyword := range c.Keywords {
+			keywords[strings.ToUpper(keyword)]++
+		}
+
+		if b.tagger == nil {
+			continue
+		}
+		cardTags, err := b.tagger.GenerateTags(c)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to tag %s: %v", c.Name, err))
+			continue
+		}
+		for _, tag := range cardTags {
+			categories[string(tag.Category)]++
+			tags[tag.Name]++
+		}
+	}
+
+	report.TypeCounts = frequencies(typeCounts)
+	report.CostCurve = bins(costs)
+	for _, cardType := range AllTypes {
+		if counts, ok := typeCosts[cardType]; ok {
+			report.TypeCurves = append(report.TypeCurves, TypeCurve{Type: cardType, Bins: bins(counts)})
+		}
+	}
+	report.AttackDistribution = bins(attacks)
+	report.DefenseDistribution = bins(defenses)
+	report.Traits = frequencies(traits)
+	report.Keywords = frequencies(keywords)
+	report.TagCategories = frequencies(categories)
+	report.Tags = frequencies(tags)
+	if b.tagger != nil && len(cards) > 0 {
+		result, err := archetype.NewClassifier(b.tagger).Classify(cards)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to classify archetype: %v", err))
+		}
+		report.Archetype = result
+	}
+	if len(cards) > 0 {
+		result, err := balance.NewAnalyzer(balance.NewScorer(nil), b.options.Balance).Analyze(cards)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to analyze balance: %v", err))
+		}
+		report.Balance = result
+	}
+	report.Gaps = b.findGaps(report, typeCounts, costs, typeCosts, tags)
+
+	return report
+}
+
+// findGaps flags empty cost slots, missing or thin types and missing staple tags
+func (b *Builder) findGaps(report *Report, typeCounts map[string]int, costs map[int]int, typeCosts map[card.CardType]map[int]int, tags map[string]int) []Gap {
+	gaps := make([]Gap, 0)
+	if report.TotalCards == 0 {
+		return append(gaps, Gap{Kind: GapType, Message: "set contains no cards"})
+	}
+
+	for cost := 1; cost <= b.options.MaxCurveCost; cost++ {
+		if costs[cost] == 0 {
+			gaps = append(gaps, Gap{Kind: GapCost, Message: fmt.Sprintf("no cards at cost %d", cost)})
+		}
+	}
+
+	for _, cardType := range AllTypes {
+		count := typeCounts[string(cardType)]
+		share := float64(count) / float64(report.TotalCards)
+		switch {
+		case count == 0:
+			gaps = append(gaps, Gap{Kind: GapType, Message: fmt.Sprintf("no %s cards", cardType)})
+		case share < b.options.MinTypeShare:
+			gaps = append(gaps, Gap{Kind: GapType, Message: fmt.Sprintf("only %d %s cards (%.0f%% of set)", count, cardType, share*100)})
+		}
+	}
+
+	// A creature curve with holes in the early game makes for awkward openings
+	if creatureCosts, ok := typeCosts[card.TypeCreature]; ok {
+		for cost := 1; cost <= 3; cost++ {
+			if creatureCosts[cost] == 0 {
+				gaps = append(gaps, Gap{Kind: GapCreature, Message: fmt.Sprintf("no creatures at cost %d", cost)})
+			}
+		}
+	}
+
+	if b.tagger != nil {
+		for _, tag := range b.options.RequiredTags {
+			if tags[tag] == 0 {
+				gaps = append(gaps, Gap{Kind: GapTag, Message: fmt.Sprintf("no cards tagged %s", tag)})
+			}
+		}
+	}
+
+	return gaps
+}
+
+// bins converts counts to a histogram sorted by value
+func bins(counts map[int]int) []Bin {
+	result := make([]Bin, 0, len(counts))
+	for value, count := range counts {
+		result = append(result, Bin{Value: value, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Value < result[j].Value })
+	return result
+}
+
+// frequencies converts counts to a list sorted by count, then name
+func frequencies(counts map[string]int) []Frequency {
+	result := make([]Frequency, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, Frequency{Name: name, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
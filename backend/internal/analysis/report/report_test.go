@@ -0,0 +1,154 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+func testCards() []*card.CardDTO {
+	return []*card.CardDTO{
+		{Type: card.TypeCreature, Name: "Squire", Cost: 1, Effect: "A squire.", Attack: 1, Defense: 1, Trait: "Warrior"},
+		{Type: card.TypeCreature, Name: "Knight", Cost: 3, Effect: "CRITICAL", Attack: 3, Defense: 3, Trait: "Warrior", Keywords: []string{"critical"}},
+		{Type: card.TypeCreature, Name: "Wyrm", Cost: 6, Effect: "A dragon.", Attack: 6, Defense: 6, Trait: "Dragon"},
+		{Type: card.TypeSpell, Name: "Research", Cost: 1, Effect: "Draw 2 cards."},
+		{Type: card.TypeSpell, Name: "Smite", Cost: 3, Effect: "Destroy target creature."},
+	}
+}
+
+func TestBuild(t *testing.T) {
+	r := NewBuilder(tagger.NewCardTagger(), DefaultOptions()).Build(testCards(), "Test Set")
+
+	if r.TotalCards != 5 {
+		t.Errorf("TotalCards = %d, want 5", r.TotalCards)
+	}
+
+	wantCurve := []Bin{{Value: 1, Count: 2}, {Value: 3, Count: 2}, {Value: 6, Count: 1}}
+	if len(r.CostCurve) != len(wantCurve) {
+		t.Fatalf("CostCurve = %+v, want %+v", r.CostCurve, wantCurve)
+	}
+	for i := range wantCurve {
+		if r.CostCurve[i] != wantCurve[i] {
+			t.Errorf("CostCurve[%d] = %+v, want %+v", i, r.CostCurve[i], wantCurve[i])
+		}
+	}
+
+	if len(r.TypeCurves) != 2 || r.TypeCurves[0].Type != card.TypeCreature {
+		t.Errorf("TypeCurves = %+v", r.TypeCurves)
+	}
+	if len(r.Traits) == 0 || r.Traits[0] != (Frequency{Name: "Warrior", Count: 2}) {
+		t.Errorf("Traits = %+v", r.Traits)
+	}
+	if len(r.Keywords) != 1 || r.Keywords[0].Name != "CRITICAL" {
+		t.Errorf("Keywords = %+v", r.Keywords)
+	}
+	if len(r.TagCategories) == 0 {
+		t.Error("expected tag category counts")
+	}
+	if r.Archetype == nil || len(r.Archetype.Probabilities) != 4 {
+		t.Errorf("Archetype = %+v, want probabilities for every archetype", r.Archetype)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, r); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "## Archetype: "+string(r.Archetype.Archetype)) {
+		t.Errorf("markdown missing archetype section:\n%s", buf.String())
+	}
+	if r.Balance == nil || len(r.Balance.Assessments) != r.TotalCards {
+		t.Errorf("Balance = %+v, want an assessment for every card", r.Balance)
+	}
+	if !strings.Contains(buf.String(), "## Balance Outliers") {
+		t.Errorf("markdown missing balance section:\n%s", buf.String())
+	}
+
+	tests := []struct {
+		kind    string
+		message string
+	}{
+		{GapCost, "no cards at cost 2"},
+		{GapType, "no Artifact cards"},
+		{GapCreature, "no creatures at cost 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.message, func(t *testing.T) {
+			for _, gap := range r.Gaps {
+				if gap.Kind == tt.kind && gap.Message == tt.message {
+					return
+				}
+			}
+			t.Errorf("missing gap %s: %s in %+v", tt.kind, tt.message, r.Gaps)
+		})
+	}
+}
+
+func TestBuildBalanceOutliers(t *testing.T) {
+	cards := []*card.CardDTO{
+		{Type: card.TypeCreature, Name: "Squire", Cost: 1, Effect: "A squire.", Attack: 1, Defense: 1},
+		{Type: card.TypeCreature, Name: "Pikeman", Cost: 2, Effect: "A soldier.", Attack: 2, Defense: 2},
+		{Type: card.TypeCreature, Name: "Sentry", Cost: 3, Effect: "A soldier.", Attack: 3, Defense: 3},
+		{Type: card.TypeCreature, Name: "Captain", Cost: 4, Effect: "A soldier.", Attack: 4, Defense: 4},
+		{Type: card.TypeCreature, Name: "Warlord", Cost: 6, Effect: "A soldier.", Attack: 6, Defense: 6},
+		{Type: card.TypeCreature, Name: "Giant Rat", Cost: 1, Effect: "A rat.", Attack: 7, Defense: 7},
+	}
+	r := NewBuilder(nil, DefaultOptions()).Build(cards, "Outliers")
+
+	if r.Balance == nil || len(r.Balance.Outliers) != 1 || r.Balance.Outliers[0].CardName != "Giant Rat" {
+		t.Fatalf("Balance = %+v, want Giant Rat as the only outlier", r.Balance)
+	}
+
+	var buf bytes.Buffer
+	if err := WriteMarkdown(&buf, r); err != nil {
+		t.Fatalf("WriteMarkdown() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "| Giant Rat | 1 |") {
+		t.Errorf("markdown missing outlier row:\n%s", buf.String())
+	}
+}
+
+func TestBuildEmpty(t *testing.T) {
+	r := NewBuilder(nil, DefaultOptions()).Build(nil, "Empty")
+
+	if len(r.Gaps) != 1 || r.Gaps[0].Message != "set contains no cards" {
+		t.Errorf("Gaps = %+v", r.Gaps)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	r := NewBuilder(nil, DefaultOptions()).Build(testCards(), "Test <Set>")
+
+	tests := []struct {
+		format Format
+		want   []string
+	}{
+		{FormatMarkdown, []string{"# Test <Set>", "## Cost Curve", "| 1 | 2 | ██ |", "## Gaps"}},
+		{FormatHTML, []string{"<h1>Test &lt;Set&gt;</h1>", "<h2>Cost Curve</h2>", `style="width: 100%"`}},
+		{FormatJSON, []string{`"total_cards": 5`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Write(&buf, r, tt.format); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("output missing %q:\n%s", want, buf.String())
+				}
+			}
+			if tt.format == FormatJSON && !json.Valid(buf.Bytes()) {
+				t.Error("output is not valid JSON")
+			}
+		})
+	}
+
+	if _, err := ParseFormat("pdf"); err == nil {
+		t.Error("expected error for unsupported format")
+	}
+}
@@ -66,7 +66,7 @@ var ArchetypeRules = []types.TagRule{
 		Name:     "CONTROL",
 		Category: types.TagArchetype,
 		Patterns: []types.Pattern{
-			{Value: "counter", Type: types.ExactMatch},
+			{Value: "negate", Type: types.ExactMatch},
 			{Value: "destroy", Type: types.ExactMatch},
 			{Value: "exile", Type: types.ExactMatch},
 		},
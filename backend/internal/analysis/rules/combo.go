@@ -130,7 +130,6 @@ func contains(text, substring string) bool {
 }
 
 func matchPattern(text string, pattern types.Pattern) bool {
-	// Pattern matching implementation would go here
-	// This is a simplified version
-	return contains(text, pattern.Value)
+	_, matched := defaultMatcher.MatchPattern(pattern, text)
+	return matched
 }
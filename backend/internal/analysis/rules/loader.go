@@ -0,0 +1,395 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/dlclark/regexp2"
+	"gopkg.in/yaml.v3"
+)
+
+// RuleFile is the on-disk format of a declarative tag rule file
+type RuleFile struct {
+	Rules []RuleSpec `yaml:"rules" json:"rules"`
+}
+
+// RuleSpec describes a single types.TagRule in a rule file
+type RuleSpec struct {
+	Name        string          `yaml:"name" json:"name"`
+	Category    string          `yaml:"category" json:"category"`
+	Weight      int             `yaml:"weight" json:"weight"`
+	Description string          `yaml:"description" json:"description"`
+	Patterns    []PatternSpec   `yaml:"patterns" json:"patterns"`
+	Conditions  []ConditionSpec `yaml:"conditions" json:"conditions"`
+}
+
+// PatternSpec describes a types.Pattern; Type is exact, regex, proximity or negation
+type PatternSpec struct {
+	Value     string `yaml:"value" json:"value"`
+	Type      string `yaml:"type" json:"type"`
+	Proximity int    `yaml:"proximity" json:"proximity"`
+	Group     int    `yaml:"group" json:"group"`
+}
+
+// ConditionSpec describes a types.Condition
+type ConditionSpec struct {
+	Type  string      `yaml:"type" json:"type"`
+	Value interface{} `yaml:"value" json:"value"`
+}
+
+// ruleFileExtensions lists the file extensions LoadRuleDir reads
+var ruleFileExtensions = map[string]bool{
+	".yaml": true,
+	".yml":  true,
+	".json": true,
+}
+
+// LoadRules reads tag rules from a rule file or from every rule file in a directory
+func LoadRules(path string) ([]types.TagRule, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat rule path: %w", err)
+	}
+	if info.IsDir() {
+		return LoadRuleDir(path)
+	}
+	return LoadRuleFile(path)
+}
+
+// LoadRuleFile reads and validates tag rules from a YAML or JSON file
+func LoadRuleFile(path string) ([]types.TagRule, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule file: %w", err)
+	}
+
+	var file RuleFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse rule file %s: %w", path, err)
+	}
+
+	rules := make([]types.TagRule, 0, len(file.Rules))
+	seen := make(map[string]bool)
+	for i, spec := range file.Rules {
+		rule, err := spec.ToTagRule()
+		if err != nil {
+			return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+		}
+		if seen[rule.Name] {
+			return nil, fmt.Errorf("%s: duplicate rule %s", path, rule.Name)
+		}
+		seen[rule.Name] = true
+		rules = append(rules, rule)
+	}
+
+	return rules, nil
+}
+
+// LoadRuleDir reads every rule file in a directory, in file name order
+func LoadRuleDir(dir string) ([]types.TagRule, error) {
+	paths, err := ruleFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var rules []types.TagRule
+	origin := make(map[string]string)
+	for _, path := range paths {
+		fileRules, err := LoadRuleFile(path)
+		if err != nil {
+			return nil, err
+		}
+		for _, rule := range fileRules {
+			if other, exists := origin[rule.Name]; exists {
+				return nil, fmt.Errorf("rule %s is defined in both %s and %s", rule.Name, other, path)
+			}
+			origin[rule.Name] = path
+		}
+		rules = append(rules, fileRules...)
+	}
+
+	return rules, nil
+}
+
+// ruleFiles lists the rule files in a directory sorted by name
+func ruleFiles(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rule directory: %w", err)
+	}
+
+	var paths []string
+	for _, entry := range entries {
+		if entry.IsDir() || !ruleFileExtensions[strings.ToLower(filepath.Ext(entry.Name()))] {
+			continue
+		}
+		paths = append(paths, filepath.Join(dir, entry.Name()))
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// ToTagRule converts the spec to a validated types.TagRule
+func (s RuleSpec) ToTagRule() (types.TagRule, error) {
+	rule := types.TagRule{
+		Name:        strings.TrimSpace(s.Name),
+		Category:    types.TagCategory(strings.ToUpper(strings.TrimSpace(s.Category))),
+		Weight:      s.Weight,
+		Description: s.Description,
+	}
+
+	for _, p := range s.Patterns {
+		patternType, err := types.ParsePatternType(p.Type)
+		if err != nil {
+			return types.TagRule{}, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		rule.Patterns = append(rule.Patterns, types.Pattern{
+			Value:     p.Value,
+			Type:      patternType,
+			Proximity: p.Proximity,
+			Group:     p.Group,
+		})
+	}
+
+	for _, c := range s.Conditions {
+		condition, err := c.toCondition()
+		if err != nil {
+			return types.TagRule{}, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		rule.Conditions = append(rule.Conditions, condition)
+	}
+
+	if err := ValidateRule(rule); err != nil {
+		return types.TagRule{}, err
+	}
+	return rule, nil
+}
+
+// toCondition converts the spec into the value shapes the tagger evaluates
+func (s ConditionSpec) toCondition() (types.Condition, error) {
+	conditionType := types.ConditionType(strings.ToUpper(strings.TrimSpace(s.Type)))
+
+	switch conditionType {
+	case types.IsType:
+		var names []string
+		switch v := s.Value.(type) {
+		case string:
+			names = []string{v}
+		case []interface{}:
+			for _, raw := range v {
+				name, ok := raw.(string)
+				if !ok {
+					return types.Condition{}, fmt.Errorf("%s condition needs card type names", conditionType)
+				}
+				names = append(names, name)
+			}
+		default:
+			return types.Condition{}, fmt.Errorf("%s condition needs a card type name", conditionType)
+		}
+		cardTypes := make([]card.CardType, 0, len(names))
+		for _, name := range names {
+			cardType, err := parseCardType(name)
+			if err != nil {
+				return types.Condition{}, err
+			}
+			cardTypes = append(cardTypes, cardType)
+		}
+		if _, single := s.Value.(string); single {
+			return types.Condition{Type: conditionType, Value: cardTypes[0]}, nil
+		}
+		return types.Condition{Type: conditionType, Value: cardTypes}, nil
+
+	case types.HasKeyword:
+		keyword, ok := s.Value.(string)
+		if !ok || keyword == "" {
+			return types.Condition{}, fmt.Errorf("%s condition needs a keyword", conditionType)
+		}
+		return types.Condition{Type: conditionType, Value: keyword}, nil
+
+	case types.CostCondition:
+		if cost, ok := toInt(s.Value); ok {
+			return types.Condition{Type: conditionType, Value: cost}, nil
+		}
+		bounds, ok := s.Value.(map[string]interface{})
+		if !ok {
+			return types.Condition{}, fmt.Errorf("%s condition needs a cost or a min/max range", conditionType)
+		}
+		costRange := make(map[string]int)
+		for key, raw := range bounds {
+			value, ok := toInt(raw)
+			if !ok || (key != "min" && key != "max") {
+				return types.Condition{}, fmt.Errorf("invalid cost range entry %s: %v", key, raw)
+			}
+			costRange[key] = value
+		}
+		return types.Condition{Type: conditionType, Value: costRange}, nil
+
+	case types.PowerCondition:
+		predicates, err := toPredicates(s.Value)
+		if err != nil {
+			return types.Condition{}, fmt.Errorf("%s condition: %w", conditionType, err)
+		}
+		if len(predicates) == 1 {
+			return types.Condition{Type: conditionType, Value: predicates[0]}, nil
+		}
+		return types.Condition{Type: conditionType, Value: predicates}, nil
+
+	case types.ComboCondition:
+		switch v := s.Value.(type) {
+		case string:
+			return types.Condition{Type: conditionType, Value: strings.ToUpper(v)}, nil
+		case []interface{}:
+			names := make([]string, 0, len(v))
+			for _, raw := range v {
+				name, ok := raw.(string)
+				if !ok {
+					return types.Condition{}, fmt.Errorf("%s condition needs combo tag names", conditionType)
+				}
+				names = append(names, strings.ToUpper(name))
+			}
+			return types.Condition{Type: conditionType, Value: names}, nil
+		default:
+			return types.Condition{}, fmt.Errorf("%s condition needs a combo tag name", conditionType)
+		}
+
+	case types.AnyCondition:
+		list, ok := s.Value.([]interface{})
+		if !ok || len(list) == 0 {
+			return types.Condition{}, fmt.Errorf("%s condition needs a list of conditions", conditionType)
+		}
+		conditions := make([]types.Condition, 0, len(list))
+		for _, raw := range list {
+			entry, ok := raw.(map[string]interface{})
+			if !ok {
+				return types.Condition{}, fmt.Errorf("%s condition entries must be conditions", conditionType)
+			}
+			name, _ := entry["type"].(string)
+			condition, err := ConditionSpec{Type: name, Value: entry["value"]}.toCondition()
+			if err != nil {
+				return types.Condition{}, err
+			}
+			conditions = append(conditions, condition)
+		}
+		return types.Condition{Type: conditionType, Value: conditions}, nil
+
+	default:
+		return types.Condition{}, fmt.Errorf("invalid condition type: %s", s.Type)
+	}
+}
+
+// parseCardType converts a card type name to a card.CardType
+func parseCardType(name string) (card.CardType, error) {
+	for _, cardType := range []card.CardType{card.TypeCreature, card.TypeSpell, card.TypeArtifact, card.TypeIncantation, card.TypeAnthem} {
+		if strings.EqualFold(name, string(cardType)) {
+			return cardType, nil
+		}
+	}
+	return "", fmt.Errorf("invalid card type: %s", name)
+}
+
+// toPredicates parses stat predicates written as "attack >= 4", as {stat, op, value} or as a list of either
+func toPredicates(value interface{}) ([]types.StatPredicate, error) {
+	var predicate types.StatPredicate
+	switch v := value.(type) {
+	case []interface{}:
+		var predicates []types.StatPredicate
+		for _, raw := range v {
+			parsed, err := toPredicates(raw)
+			if err != nil {
+				return nil, err
+			}
+			predicates = append(predicates, parsed...)
+		}
+		if len(predicates) == 0 {
+			return nil, fmt.Errorf("at least one stat predicate is required")
+		}
+		return predicates, nil
+
+	case string:
+		fields := strings.Fields(v)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("invalid stat predicate %q, want e.g. \"attack >= 4\"", v)
+		}
+		n, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return nil, fmt.Errorf("invalid stat predicate value %q: %w", fields[2], err)
+		}
+		predicate = types.StatPredicate{Stat: types.Stat(strings.ToLower(fields[0])), Op: fields[1], Value: n}
+
+	case map[string]interface{}:
+		stat, _ := v["stat"].(string)
+		op, _ := v["op"].(string)
+		n, ok := toInt(v["value"])
+		if !ok {
+			return nil, fmt.Errorf("stat predicate needs an integer value")
+		}
+		predicate = types.StatPredicate{Stat: types.Stat(strings.ToLower(stat)), Op: op, Value: n}
+
+	default:
+		return nil, fmt.Errorf("invalid stat predicate: %v", value)
+	}
+
+	if err := predicate.Validate(); err != nil {
+		return nil, err
+	}
+	return []types.StatPredicate{predicate}, nil
+}
+
+// ValidateRule checks that a rule has a name, a valid category, patterns that compile and at least
+// one pattern or condition; a rule without patterns matches on its conditions alone
+func ValidateRule(rule types.TagRule) error {
+	if rule.Name == "" {
+		return fmt.Errorf("rule name cannot be empty")
+	}
+	if err := types.NewTagValidator().ValidateTag(types.Tag{Name: rule.Name, Category: rule.Category}); err != nil {
+		return fmt.Errorf("rule %s: %w", rule.Name, err)
+	}
+	if len(rule.Patterns) == 0 && len(rule.Conditions) == 0 {
+		return fmt.Errorf("rule %s: at least one pattern or condition is required", rule.Name)
+	}
+
+	for _, pattern := range rule.Patterns {
+		if strings.TrimSpace(pattern.Value) == "" {
+			return fmt.Errorf("rule %s: pattern value cannot be empty", rule.Name)
+		}
+		switch pattern.Type {
+		case types.RegexMatch, types.NegationMatch:
+			if _, err := regexp2.Compile(pattern.Value, regexp2.IgnoreCase); err != nil {
+				return fmt.Errorf("rule %s: invalid regex %q: %w", rule.Name, pattern.Value, err)
+			}
+		case types.ProximityMatch:
+			if pattern.Proximity < 0 {
+				return fmt.Errorf("rule %s: proximity cannot be negative", rule.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// toInt converts YAML and JSON numbers to int
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case float64:
+		if v != float64(int(v)) {
+			return 0, false
+		}
+		return int(v), true
+	default:
+		return 0, false
+	}
+}
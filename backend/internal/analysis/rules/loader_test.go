@@ -0,0 +1,258 @@
+package rules
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+func writeRuleFile(t *testing.T, dir, name, content string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRuleFile(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "YAML rule with conditions",
+			file: "valid.yaml",
+			content: `rules:
+  - name: CHEAP_REMOVAL
+    category: strategy
+    weight: 3
+    patterns:
+      - value: 'destroy target'
+        type: regex
+    conditions:
+      - type: IS_TYPE
+        value: spell
+      - type: COST
+        value: {max: 2}
+`,
+		},
+		{
+			name:    "JSON rule",
+			file:    "valid.json",
+			content: `{"rules": [{"name": "LIFEGAIN", "category": "MECHANIC", "patterns": [{"value": "gain", "type": "exact"}], "conditions": [{"type": "COST", "value": 2}]}]}`,
+		},
+		{
+			name:    "Invalid regex",
+			file:    "regex.yaml",
+			content: "rules:\n  - name: BROKEN\n    category: MECHANIC\n    patterns:\n      - value: 'draw (\\d+'\n        type: regex\n",
+			wantErr: "invalid regex",
+		},
+		{
+			name:    "Invalid category",
+			file:    "category.yaml",
+			content: "rules:\n  - name: BAD\n    category: FLAVOR\n    patterns:\n      - value: draw\n        type: exact\n",
+			wantErr: "invalid tag category",
+		},
+		{
+			name:    "Invalid pattern type",
+			file:    "pattern.yaml",
+			content: "rules:\n  - name: BAD\n    category: MECHANIC\n    patterns:\n      - value: draw\n        type: fuzzy\n",
+			wantErr: "invalid pattern type",
+		},
+		{
+			name:    "Condition-only rule",
+			file:    "conditions.yaml",
+			content: "rules:\n  - name: CHEAP\n    category: STRATEGY\n    conditions:\n      - type: COST\n        value: {max: 1}\n",
+		},
+		{
+			name:    "Missing patterns and conditions",
+			file:    "empty.yaml",
+			content: "rules:\n  - name: BAD\n    category: MECHANIC\n",
+			wantErr: "at least one pattern or condition",
+		},
+		{
+			name:    "Invalid card type condition",
+			file:    "condition.yaml",
+			content: "rules:\n  - name: BAD\n    category: MECHANIC\n    patterns:\n      - value: draw\n        type: exact\n    conditions:\n      - type: IS_TYPE\n        value: Planeswalker\n",
+			wantErr: "invalid card type",
+		},
+		{
+			name:    "Duplicate rule names",
+			file:    "duplicate.yaml",
+			content: "rules:\n  - name: DUP\n    category: MECHANIC\n    patterns: [{value: a, type: exact}]\n  - name: DUP\n    category: MECHANIC\n    patterns: [{value: b, type: exact}]\n",
+			wantErr: "duplicate rule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeRuleFile(t, dir, tt.file, tt.content)
+
+			rules, err := LoadRuleFile(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadRuleFile() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadRuleFile() error = %v", err)
+			}
+			if len(rules) != 1 {
+				t.Fatalf("got %d rules, want 1", len(rules))
+			}
+		})
+	}
+}
+
+func TestRuleSpecConditions(t *testing.T) {
+	rules, err := LoadRuleFile(writeRuleFile(t, t.TempDir(), "rules.yaml", `rules:
+  - name: CHEAP_REMOVAL
+    category: STRATEGY
+    patterns: [{value: destroy, type: exact}]
+    conditions:
+      - {type: IS_TYPE, value: Spell}
+      - {type: COST, value: {min: 1, max: 2}}
+`))
+	if err != nil {
+		t.Fatalf("LoadRuleFile() error = %v", err)
+	}
+
+	rule := rules[0]
+	if rule.Category != types.TagStrategy {
+		t.Errorf("Category = %s", rule.Category)
+	}
+	if rule.Conditions[0].Value != card.TypeSpell {
+		t.Errorf("IS_TYPE value = %#v, want card.TypeSpell", rule.Conditions[0].Value)
+	}
+	costRange, ok := rule.Conditions[1].Value.(map[string]int)
+	if !ok || costRange["min"] != 1 || costRange["max"] != 2 {
+		t.Errorf("COST value = %#v", rule.Conditions[1].Value)
+	}
+}
+
+func TestConditionSpec(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    ConditionSpec
+		want    interface{}
+		wantErr string
+	}{
+		{"Type list", ConditionSpec{"IS_TYPE", []interface{}{"spell", "Incantation"}}, []card.CardType{card.TypeSpell, card.TypeIncantation}, ""},
+		{"Power string", ConditionSpec{"POWER", "attack >= 4"}, types.StatPredicate{Stat: types.StatAttack, Op: ">=", Value: 4}, ""},
+		{"Power map", ConditionSpec{"POWER", map[string]interface{}{"stat": "defense", "op": "<", "value": 2.0}}, types.StatPredicate{Stat: types.StatDefense, Op: "<", Value: 2}, ""},
+		{"Power list", ConditionSpec{"POWER", []interface{}{"total >= 8", "cost <= 4"}}, []types.StatPredicate{
+			{Stat: types.StatTotal, Op: ">=", Value: 8},
+			{Stat: types.StatCost, Op: "<=", Value: 4},
+		}, ""},
+		{"Power bad stat", ConditionSpec{"POWER", "speed > 1"}, nil, "invalid stat"},
+		{"Power bad operator", ConditionSpec{"POWER", "attack => 1"}, nil, "invalid comparison operator"},
+		{"Combo list", ConditionSpec{"COMBO", []interface{}{"combo_potential", "TOKEN_DOUBLING"}}, []string{"COMBO_POTENTIAL", "TOKEN_DOUBLING"}, ""},
+		{"Any", ConditionSpec{"ANY", []interface{}{
+			map[string]interface{}{"type": "IS_TYPE", "value": "Artifact"},
+			map[string]interface{}{"type": "POWER", "value": "attack >= 4"},
+		}}, []types.Condition{
+			{Type: types.IsType, Value: card.TypeArtifact},
+			{Type: types.PowerCondition, Value: types.StatPredicate{Stat: types.StatAttack, Op: ">=", Value: 4}},
+		}, ""},
+		{"Any invalid entry", ConditionSpec{"ANY", []interface{}{map[string]interface{}{"type": "COST", "value": "cheap"}}}, nil, "min/max range"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			condition, err := tt.spec.toCondition()
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("toCondition() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("toCondition() error = %v", err)
+			}
+			if !reflect.DeepEqual(condition.Value, tt.want) {
+				t.Errorf("Value = %#v, want %#v", condition.Value, tt.want)
+			}
+		})
+	}
+}
+
+func TestPatternGroups(t *testing.T) {
+	rules, err := LoadRuleFile(writeRuleFile(t, t.TempDir(), "rules.yaml", `rules:
+  - name: CARD_FILTERING
+    category: STRATEGY
+    patterns:
+      - {value: draw, type: exact}
+      - {value: guide, type: exact, group: 1}
+      - {value: search, type: exact, group: 1}
+      - {value: 'cannot draw', type: negation}
+`))
+	if err != nil {
+		t.Fatalf("LoadRuleFile() error = %v", err)
+	}
+
+	patterns := rules[0].Patterns
+	if patterns[1].Group != 1 || patterns[2].Group != 1 || patterns[3].Type != types.NegationMatch {
+		t.Errorf("unexpected patterns: %+v", patterns)
+	}
+	if _, ok := NewMatcher().EvaluateRule(corpus["Research"], rules[0]); !ok {
+		t.Error("rule did not match Research")
+	}
+}
+
+func TestLoadRuleDir(t *testing.T) {
+	dir := t.TempDir()
+	writeRuleFile(t, dir, "a.yaml", "rules:\n  - {name: A, category: MECHANIC, patterns: [{value: a, type: exact}]}\n")
+	writeRuleFile(t, dir, "b.json", `{"rules": [{"name": "B", "category": "MECHANIC", "patterns": [{"value": "b", "type": "exact"}]}]}`)
+	writeRuleFile(t, dir, "notes.txt", "ignored")
+
+	rules, err := LoadRules(dir)
+	if err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+	if len(rules) != 2 || rules[0].Name != "A" || rules[1].Name != "B" {
+		t.Errorf("unexpected rules: %+v", rules)
+	}
+
+	writeRuleFile(t, dir, "c.yaml", "rules:\n  - {name: A, category: MECHANIC, patterns: [{value: c, type: exact}]}\n")
+	if _, err := LoadRules(dir); err == nil || !strings.Contains(err.Error(), "defined in both") {
+		t.Errorf("LoadRules() error = %v, want duplicate across files", err)
+	}
+}
+
+func TestWatcherCheck(t *testing.T) {
+	dir := t.TempDir()
+	path := writeRuleFile(t, dir, "a.yaml", "rules: []\n")
+
+	w := NewWatcher(dir, time.Second, func([]types.TagRule, error) {})
+	if w.Check() {
+		t.Error("Check() reported a change before any file changed")
+	}
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if !w.Check() {
+		t.Error("Check() missed a modified file")
+	}
+
+	writeRuleFile(t, dir, "b.yaml", "rules: []\n")
+	if !w.Check() {
+		t.Error("Check() missed a new file")
+	}
+	if w.Check() {
+		t.Error("Check() reported a change twice")
+	}
+}
@@ -0,0 +1,373 @@
+package rules
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/dlclark/regexp2"
+)
+
+// Span is the part of the effect text a pattern matched, as byte offsets
+type Span struct {
+	Pattern     string `json:"pattern"`
+	PatternType string `json:"pattern_type"`
+	Start       int    `json:"start"`
+	End         int    `json:"end"`
+	Text        string `json:"text"`
+}
+
+// RuleMatch records why a rule matched a card
+type RuleMatch struct {
+	Spans      []Span
+	Conditions []types.Condition
+}
+
+// Matcher evaluates rule patterns and conditions, caching compiled regular expressions
+type Matcher struct {
+	regexCache map[string]*regexp2.Regexp
+	cacheMutex sync.RWMutex
+}
+
+// NewMatcher creates a new matcher
+func NewMatcher() *Matcher {
+	return &Matcher{
+		regexCache: make(map[string]*regexp2.Regexp),
+	}
+}
+
+// defaultMatcher backs the package-level tribal and combo detection
+var defaultMatcher = NewMatcher()
+
+// EvaluateRule checks a rule against a card: every pattern group and every condition must hold
+func (m *Matcher) EvaluateRule(c *card.CardDTO, rule types.TagRule) (RuleMatch, bool) {
+	spans, ok := m.MatchPatterns(rule.Patterns, c.Effect)
+	if !ok {
+		return RuleMatch{}, false
+	}
+
+	for _, condition := range rule.Conditions {
+		if !m.CheckCondition(c, condition) {
+			return RuleMatch{}, false
+		}
+	}
+
+	return RuleMatch{Spans: spans, Conditions: rule.Conditions}, true
+}
+
+// MatchPatterns applies pattern semantics to text: patterns sharing a Group are
+// alternatives, every group must match, and no NegationMatch pattern may match.
+// It returns the spans of every positive pattern that matched.
+func (m *Matcher) MatchPatterns(patterns []types.Pattern, text string) ([]Span, bool) {
+	groups := make(map[int][]types.Pattern)
+	for _, pattern := range patterns {
+		if pattern.Type == types.NegationMatch {
+			if _, absent := m.MatchPattern(pattern, text); !absent {
+				return nil, false
+			}
+			continue
+		}
+		groups[pattern.Group] = append(groups[pattern.Group], pattern)
+	}
+
+	groupIDs := make([]int, 0, len(groups))
+	for id := range groups {
+		groupIDs = append(groupIDs, id)
+	}
+	sort.Ints(groupIDs)
+
+	var spans []Span
+	for _, id := range groupIDs {
+		matched := false
+		for _, pattern := range groups[id] {
+			if span, ok := m.MatchPattern(pattern, text); ok {
+				spans = append(spans, span)
+				matched = true
+			}
+		}
+		if !matched {
+			return nil, false
+		}
+	}
+
+	return spans, true
+}
+
+// MatchPattern matches a single pattern against text. For NegationMatch it
+// reports true when the pattern is absent.
+func (m *Matcher) MatchPattern(pattern types.Pattern, text string) (Span, bool) {
+	span := Span{Pattern: pattern.Value, PatternType: pattern.Type.String()}
+
+	var start, end int
+	var found bool
+	switch pattern.Type {
+	case types.ExactMatch:
+		start, end, found = m.findRegex(regexp2.Escape(pattern.Value), text)
+	case types.RegexMatch:
+		start, end, found = m.findRegex(pattern.Value, text)
+	case types.ProximityMatch:
+		start, end, found = findProximity(pattern.Value, text, pattern.Proximity)
+	case types.NegationMatch:
+		_, _, found = m.findRegex(pattern.Value, text)
+		return span, !found
+	default:
+		return span, false
+	}
+
+	if !found {
+		return span, false
+	}
+	span.Start, span.End, span.Text = start, end, text[start:end]
+	return span, true
+}
+
+// findRegex returns the byte offsets of the first regex match, compiling patterns through the cache
+func (m *Matcher) findRegex(pattern, text string) (int, int, bool) {
+	regex, err := m.compile(pattern)
+	if err != nil {
+		return 0, 0, false
+	}
+
+	match, err := regex.FindStringMatch(text)
+	if err != nil || match == nil {
+		return 0, 0, false
+	}
+
+	// regexp2 reports offsets in runes
+	runes := []rune(text)
+	start := len(string(runes[:match.Index]))
+	end := start + len(string(runes[match.Index:match.Index+match.Length]))
+	return start, end, true
+}
+
+// compile returns the cached case-insensitive regex for a pattern
+func (m *Matcher) compile(pattern string) (*regexp2.Regexp, error) {
+	m.cacheMutex.RLock()
+	regex, exists := m.regexCache[pattern]
+	m.cacheMutex.RUnlock()
+	if exists {
+		return regex, nil
+	}
+
+	regex, err := regexp2.Compile(pattern, regexp2.IgnoreCase)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regex %q: %w", pattern, err)
+	}
+
+	m.cacheMutex.Lock()
+	m.regexCache[pattern] = regex
+	m.cacheMutex.Unlock()
+	return regex, nil
+}
+
+// word is a lowercased word of the effect text and its byte offsets
+type word struct {
+	text       string
+	start, end int
+}
+
+var wordPattern = regexp.MustCompile(`[\p{L}\p{N}+/'-]+`)
+
+// words splits text into words, ignoring punctuation
+func words(text string) []word {
+	var result []word
+	for _, loc := range wordPattern.FindAllStringIndex(text, -1) {
+		result = append(result, word{text: strings.ToLower(text[loc[0]:loc[1]]), start: loc[0], end: loc[1]})
+	}
+	return result
+}
+
+// findProximity finds the pattern's words in order anywhere in the text, with at most
+// maxDistance other words between consecutive pattern words
+func findProximity(pattern, text string, maxDistance int) (int, int, bool) {
+	patternWords := words(pattern)
+	if len(patternWords) == 0 {
+		return 0, 0, false
+	}
+	textWords := words(text)
+
+	for i, w := range textWords {
+		if w.text != patternWords[0].text {
+			continue
+		}
+		last, ok := i, true
+		for _, next := range patternWords[1:] {
+			found := -1
+			for j := last + 1; j < len(textWords) && j <= last+1+maxDistance; j++ {
+				if textWords[j].text == next.text {
+					found = j
+					break
+				}
+			}
+			if found < 0 {
+				ok = false
+				break
+			}
+			last = found
+		}
+		if ok {
+			return w.start, textWords[last].end, true
+		}
+	}
+	return 0, 0, false
+}
+
+// CheckCondition evaluates a single condition against a card
+func (m *Matcher) CheckCondition(c *card.CardDTO, condition types.Condition) bool {
+	switch condition.Type {
+	case types.IsType:
+		return matchesType(c.Type, condition.Value)
+
+	case types.HasKeyword:
+		keyword, ok := condition.Value.(string)
+		return ok && hasKeyword(c, keyword)
+
+	case types.CostCondition:
+		return checkCost(c.Cost, condition.Value)
+
+	case types.PowerCondition:
+		return checkStats(c, condition.Value)
+
+	case types.ComboCondition:
+		return hasCombo(c.Effect, condition.Value)
+
+	case types.AnyCondition:
+		conditions, ok := condition.Value.([]types.Condition)
+		if !ok {
+			return false
+		}
+		for _, sub := range conditions {
+			if m.CheckCondition(c, sub) {
+				return true
+			}
+		}
+		return false
+
+	default:
+		return false
+	}
+}
+
+// matchesType compares the card type with a type or list of types
+func matchesType(cardType card.CardType, value interface{}) bool {
+	switch v := value.(type) {
+	case card.CardType:
+		return cardType == v
+	case string:
+		return strings.EqualFold(string(cardType), v)
+	case []card.CardType:
+		for _, t := range v {
+			if cardType == t {
+				return true
+			}
+		}
+	case []string:
+		for _, t := range v {
+			if strings.EqualFold(string(cardType), t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasKeyword checks the card's declared keywords and the words of its effect text
+func hasKeyword(c *card.CardDTO, keyword string) bool {
+	if keyword == "" {
+		return false
+	}
+	for _, k := range c.Keywords {
+		if strings.EqualFold(k, keyword) {
+			return true
+		}
+	}
+	_, _, found := findProximity(keyword, c.Effect, 0)
+	return found
+}
+
+// checkCost compares the cost with an exact value, a min/max range or a StatPredicate
+func checkCost(cost int, value interface{}) bool {
+	switch v := value.(type) {
+	case int:
+		return cost == v
+	case map[string]int:
+		if min, ok := v["min"]; ok && cost < min {
+			return false
+		}
+		if max, ok := v["max"]; ok && cost > max {
+			return false
+		}
+		return true
+	case types.StatPredicate:
+		ok, err := v.Compare(cost)
+		return err == nil && ok
+	default:
+		return false
+	}
+}
+
+// checkStats evaluates one or more stat predicates, all of which must hold
+func checkStats(c *card.CardDTO, value interface{}) bool {
+	var predicates []types.StatPredicate
+	switch v := value.(type) {
+	case types.StatPredicate:
+		predicates = []types.StatPredicate{v}
+	case []types.StatPredicate:
+		predicates = v
+	default:
+		return false
+	}
+
+	for _, predicate := range predicates {
+		var actual int
+		switch predicate.Stat {
+		case types.StatCost:
+			actual = c.Cost
+		case types.StatAttack, types.StatDefense, types.StatTotal:
+			if c.Type != card.TypeCreature {
+				return false
+			}
+			switch predicate.Stat {
+			case types.StatAttack:
+				actual = c.Attack
+			case types.StatDefense:
+				actual = c.Defense
+			default:
+				actual = c.Attack + c.Defense
+			}
+		default:
+			return false
+		}
+
+		if ok, err := predicate.Compare(actual); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// hasCombo checks whether the effect produces any of the named combo tags
+func hasCombo(effect string, value interface{}) bool {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		names = []string{v}
+	case []string:
+		names = v
+	default:
+		return false
+	}
+
+	for _, tag := range DetectComboPotential(effect) {
+		for _, name := range names {
+			if strings.EqualFold(tag.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,186 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+// corpus holds real cards from test/testdata
+var corpus = map[string]*card.CardDTO{
+	"Demon Pup":          {Type: card.TypeCreature, Name: "Demon Pup", Cost: 1, Effect: "Each time you OFFER; gain +1/1.", Attack: 1, Defense: 1, Trait: "Demon"},
+	"Goblin Scout":       {Type: card.TypeCreature, Name: "Goblin Scout", Cost: 1, Effect: "On Play - Draw a card. When destroyed, draw 1 card.", Attack: 1, Defense: 1, Trait: "Goblin"},
+	"Lilith":             {Type: card.TypeCreature, Name: "Lilith, Queen of Demons", Cost: 6, Effect: "On Play - COMMAND target player to give you selected creature.", Attack: 4, Defense: 4, Trait: "Demon"},
+	"War Golem":          {Type: card.TypeCreature, Name: "War Golem", Cost: 4, Effect: "INDESTRUCTIBLE", Attack: 4, Defense: 4},
+	"Enchanted Mace":     {Type: card.TypeArtifact, Name: "Enchanted Mace", Cost: 2, Effect: "pay 1 - Equip to target creature. Equipped creature gains CRITICAL."},
+	"The Gods' Blessing": {Type: card.TypeArtifact, Name: "The Gods' Blessing", Cost: 4, Effect: "pay 3 - Equip to target creature. Equipped creature gains INDESTRUCTIBLE - cannot be destroyed"},
+	"Research":           {Type: card.TypeSpell, Name: "Research", Cost: 1, Effect: "GUIDE 2, draw 1."},
+	"Carnival Games":     {Type: card.TypeSpell, Name: "Carnival Games", Cost: 2, Effect: "OFFER to search the deck for a cost 2 card or less and add it to their hand. For Each contract; Draw a card. - You gain double souls for each contract."},
+	"Fireball":           {Type: card.TypeIncantation, Name: "Fireball", Cost: 2, Effect: "Deal 3 damage to any creature."},
+	"Divine judgment":    {Type: card.TypeIncantation, Name: "Divine judgment", Cost: 2, Effect: "Destroy a creature with mana cost 4 or less."},
+	"No!":                {Type: card.TypeIncantation, Name: "No!", Cost: 2, Effect: "Negate the play of an Incantation or Spell."},
+	"Nature's Dance":     {Type: card.TypeAnthem, Name: "Nature's Dance", Cost: 2, Effect: "During your draw phase, you may draw an extra card."},
+	"Mana Droid":         {Type: card.TypeAnthem, Name: "Mana Droid", Cost: 3, Effect: "Whenever you charge mana you may draw a card."},
+	"Goblin Assembly":    {Type: card.TypeAnthem, Name: "Goblin Assembly", Cost: 3, Effect: "At the end of your draw phase; create a 1/1 Goblin Token."},
+	"Vampire Castle":     {Type: card.TypeAnthem, Name: "Vampire Castle", Cost: 5, Effect: "Vampires you control don't tap when attacking."},
+}
+
+func TestEvaluateRule(t *testing.T) {
+	rule := func(patterns []types.Pattern, conditions ...types.Condition) types.TagRule {
+		return types.TagRule{Name: "TEST", Category: types.TagMechanic, Patterns: patterns, Conditions: conditions}
+	}
+	draw := []types.Pattern{{Value: "draw card", Type: types.ProximityMatch, Proximity: 1}}
+
+	tests := []struct {
+		name string
+		card string
+		rule types.TagRule
+		want bool
+	}{
+		// Pattern types
+		{"Exact match", "No!", rule([]types.Pattern{{Value: "negate", Type: types.ExactMatch}}), true},
+		{"Exact match is literal", "Fireball", rule([]types.Pattern{{Value: "deal.*damage", Type: types.ExactMatch}}), false},
+		{"Regex match", "Fireball", rule([]types.Pattern{{Value: `deal (\d+|x) damage`, Type: types.RegexMatch}}), true},
+		{"Proximity at start", "Goblin Scout", rule(draw), true},
+		{"Proximity mid-text", "Carnival Games", rule(draw), true},
+		{"Proximity too far apart", "Nature's Dance", rule(draw), false},
+		{"Proximity wider window", "Nature's Dance", rule([]types.Pattern{{Value: "draw card", Type: types.ProximityMatch, Proximity: 2}}), true},
+		{"Proximity ignores punctuation", "Research", rule([]types.Pattern{{Value: "guide 2 draw", Type: types.ProximityMatch}}), true},
+		{"Negation absent", "Divine judgment", rule([]types.Pattern{
+			{Value: `\bdestroy`, Type: types.RegexMatch},
+			{Value: `cannot be destroyed`, Type: types.NegationMatch},
+		}), true},
+		{"Negation present", "The Gods' Blessing", rule([]types.Pattern{
+			{Value: `\bdestroy`, Type: types.RegexMatch},
+			{Value: `cannot be destroyed`, Type: types.NegationMatch},
+		}), false},
+
+		// OR groups
+		{"Same group is OR", "Fireball", rule([]types.Pattern{
+			{Value: "destroy", Type: types.ExactMatch},
+			{Value: "damage", Type: types.ExactMatch},
+		}), true},
+		{"Groups are AND", "Research", rule([]types.Pattern{
+			{Value: "draw", Type: types.ExactMatch},
+			{Value: "guide", Type: types.ExactMatch, Group: 1},
+			{Value: "search", Type: types.ExactMatch, Group: 1},
+		}), true},
+		{"Second group alternative", "Carnival Games", rule([]types.Pattern{
+			{Value: "draw", Type: types.ExactMatch},
+			{Value: "guide", Type: types.ExactMatch, Group: 1},
+			{Value: "search", Type: types.ExactMatch, Group: 1},
+		}), true},
+		{"Missing group", "Goblin Scout", rule([]types.Pattern{
+			{Value: "draw", Type: types.ExactMatch},
+			{Value: "guide", Type: types.ExactMatch, Group: 1},
+			{Value: "search", Type: types.ExactMatch, Group: 1},
+		}), false},
+
+		// Conditions
+		{"IS_TYPE", "No!", rule(nil, types.Condition{Type: types.IsType, Value: card.TypeIncantation}), true},
+		{"IS_TYPE list", "Research", rule(nil, types.Condition{Type: types.IsType, Value: []card.CardType{card.TypeSpell, card.TypeIncantation}}), true},
+		{"IS_TYPE list miss", "War Golem", rule(nil, types.Condition{Type: types.IsType, Value: []card.CardType{card.TypeSpell, card.TypeIncantation}}), false},
+		{"HAS_KEYWORD in effect", "War Golem", rule(nil, types.Condition{Type: types.HasKeyword, Value: "indestructible"}), true},
+		{"HAS_KEYWORD whole word", "Goblin Scout", rule(nil, types.Condition{Type: types.HasKeyword, Value: "destroy"}), false},
+		{"COST range", "Fireball", rule(nil, types.Condition{Type: types.CostCondition, Value: map[string]int{"max": 2}}), true},
+		{"COST predicate", "Lilith", rule(nil, types.Condition{Type: types.CostCondition, Value: types.StatPredicate{Stat: types.StatCost, Op: ">=", Value: 5}}), true},
+		{"POWER attack", "Lilith", rule(nil, types.Condition{Type: types.PowerCondition, Value: types.StatPredicate{Stat: types.StatAttack, Op: ">=", Value: 4}}), true},
+		{"POWER attack too low", "Demon Pup", rule(nil, types.Condition{Type: types.PowerCondition, Value: types.StatPredicate{Stat: types.StatAttack, Op: ">=", Value: 4}}), false},
+		{"POWER all predicates", "War Golem", rule(nil, types.Condition{Type: types.PowerCondition, Value: []types.StatPredicate{
+			{Stat: types.StatTotal, Op: ">=", Value: 8},
+			{Stat: types.StatCost, Op: "<", Value: 5},
+		}}), true},
+		{"POWER needs a creature", "Enchanted Mace", rule(nil, types.Condition{Type: types.PowerCondition, Value: types.StatPredicate{Stat: types.StatDefense, Op: ">=", Value: 0}}), false},
+		{"COMBO", "Mana Droid", rule(nil, types.Condition{Type: types.ComboCondition, Value: "COMBO_POTENTIAL"}), true},
+		{"COMBO absent", "Fireball", rule(nil, types.Condition{Type: types.ComboCondition, Value: []string{"COMBO_POTENTIAL", "TOKEN_DOUBLING"}}), false},
+		{"ANY first", "Enchanted Mace", rule(nil, types.Condition{Type: types.AnyCondition, Value: []types.Condition{
+			{Type: types.IsType, Value: card.TypeArtifact},
+			{Type: types.PowerCondition, Value: types.StatPredicate{Stat: types.StatAttack, Op: ">=", Value: 4}},
+		}}), true},
+		{"ANY second", "War Golem", rule(nil, types.Condition{Type: types.AnyCondition, Value: []types.Condition{
+			{Type: types.IsType, Value: card.TypeArtifact},
+			{Type: types.PowerCondition, Value: types.StatPredicate{Stat: types.StatAttack, Op: ">=", Value: 4}},
+		}}), true},
+		{"ANY none", "Demon Pup", rule(nil, types.Condition{Type: types.AnyCondition, Value: []types.Condition{
+			{Type: types.IsType, Value: card.TypeArtifact},
+			{Type: types.PowerCondition, Value: types.StatPredicate{Stat: types.StatAttack, Op: ">=", Value: 4}},
+		}}), false},
+		{"Unknown condition", "Fireball", rule(nil, types.Condition{Type: "RARITY", Value: "rare"}), false},
+	}
+
+	m := NewMatcher()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, ok := corpus[tt.card]
+			if !ok {
+				t.Fatalf("card %s not in corpus", tt.card)
+			}
+			match, got := m.EvaluateRule(c, tt.rule)
+			if got != tt.want {
+				t.Fatalf("EvaluateRule(%s) = %v, want %v", tt.card, got, tt.want)
+			}
+			for _, span := range match.Spans {
+				if c.Effect[span.Start:span.End] != span.Text {
+					t.Errorf("span %+v does not match effect text", span)
+				}
+			}
+		})
+	}
+
+	// A rule without patterns matches on its conditions alone, even without effect text
+	wall := &card.CardDTO{Type: card.TypeCreature, Name: "Stone Wall", Cost: 2, Defense: 6}
+	if _, ok := m.EvaluateRule(wall, rule(nil, types.Condition{Type: types.PowerCondition, Value: types.StatPredicate{Stat: types.StatDefense, Op: ">=", Value: 5}})); !ok {
+		t.Error("EvaluateRule() of a condition-only rule should match a card without effect text")
+	}
+}
+
+func TestGenerateTribalTags(t *testing.T) {
+	tests := []struct {
+		name string
+		card string
+		want []string
+	}{
+		{"Member without synergy", "Goblin Scout", []string{"GOBLIN_TRIBAL"}},
+		{"Plural tribe reference", "Vampire Castle", []string{"VAMPIRE_SYNERGY"}},
+		{"Token of a tribe", "Goblin Assembly", []string{"GOBLIN_SYNERGY"}},
+		{"No tribe", "Fireball", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := corpus[tt.card]
+			var tribes []string
+			if c.Trait != "" {
+				tribes = []string{c.Trait}
+			}
+
+			tags := GenerateTribalTags(string(c.Type), c.Effect, tribes)
+			if len(tags) != len(tt.want) {
+				t.Fatalf("got %+v, want %v", tags, tt.want)
+			}
+			for i, tag := range tags {
+				if tag.Name != tt.want[i] {
+					t.Errorf("tag %d = %s, want %s", i, tag.Name, tt.want[i])
+				}
+			}
+		})
+	}
+
+	t.Run("Member matching tribe patterns", func(t *testing.T) {
+		tags := GenerateTribalTags(string(card.TypeCreature), "Sacrifice a creature: deal 2 damage to target player.", []string{"Demon"})
+		if len(tags) != 2 || tags[1].Name != "DEMON_SYNERGY" {
+			t.Errorf("got %+v, want DEMON_TRIBAL and DEMON_SYNERGY", tags)
+		}
+	})
+}
+
+func TestDetectComboPotentialRegex(t *testing.T) {
+	tags := DetectComboPotential("When this creature dies, return target creature from your graveyard to your hand.")
+	for _, tag := range tags {
+		if tag.Name == "RESURRECTION_LOOP" {
+			return
+		}
+	}
+	t.Errorf("got %+v, want RESURRECTION_LOOP", tags)
+}
@@ -0,0 +1,42 @@
+package rules
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"gopkg.in/yaml.v3"
+)
+
+// TaxonomyFile is the on-disk format of a tag taxonomy
+type TaxonomyFile struct {
+	Tags []types.TaxonomyEntry `yaml:"tags" json:"tags"`
+}
+
+// LoadTaxonomy reads and validates a tag taxonomy from a YAML or JSON file
+func LoadTaxonomy(path string) (*types.Taxonomy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read taxonomy file: %w", err)
+	}
+
+	var file TaxonomyFile
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &file)
+	default:
+		err = yaml.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse taxonomy file %s: %w", path, err)
+	}
+
+	taxonomy, err := types.NewTaxonomy(file.Tags)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return taxonomy, nil
+}
@@ -0,0 +1,68 @@
+package rules
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadTaxonomy(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string
+	}{
+		{
+			name: "YAML",
+			file: "taxonomy.yaml",
+			content: `tags:
+  - {name: removal, category: strategy}
+  - {name: destroy, category: strategy, parent: removal, synonyms: [destruction]}
+`,
+		},
+		{
+			name:    "JSON",
+			file:    "taxonomy.json",
+			content: `{"tags": [{"name": "REMOVAL", "category": "STRATEGY"}, {"name": "DESTROY", "category": "STRATEGY", "parent": "REMOVAL", "synonyms": ["DESTRUCTION"]}]}`,
+		},
+		{
+			name:    "Unknown parent",
+			file:    "parent.yaml",
+			content: "tags:\n  - {name: DESTROY, category: STRATEGY, parent: REMOVAL}\n",
+			wantErr: "unknown parent",
+		},
+		{
+			name:    "Malformed",
+			file:    "broken.yaml",
+			content: "tags: [",
+			wantErr: "failed to parse taxonomy file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			taxonomy, err := LoadTaxonomy(writeRuleFile(t, dir, tt.file, tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("LoadTaxonomy() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadTaxonomy() error = %v", err)
+			}
+			if got := taxonomy.Canonical("destruction"); got != "DESTROY" {
+				t.Errorf("Canonical(destruction) = %s, want DESTROY", got)
+			}
+			if got := strings.Join(taxonomy.Descendants("REMOVAL"), ","); got != "REMOVAL,DESTROY" {
+				t.Errorf("Descendants(REMOVAL) = %s", got)
+			}
+		})
+	}
+
+	if _, err := LoadTaxonomy(dir + "/missing.yaml"); err == nil {
+		t.Error("LoadTaxonomy() of a missing file should fail")
+	}
+}
@@ -1,6 +1,9 @@
 package rules
 
 import (
+	"sort"
+	"strings"
+
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/common"
 )
@@ -50,33 +53,44 @@ var TribalEffectPatterns = map[common.Tribe][]types.Pattern{
 	// Add patterns for other tribes as needed
 }
 
-// GenerateTribalTags generates tribal-specific tags
+// GenerateTribalTags generates tribal-specific tags. A card gets <TRIBE>_SYNERGY when its
+// effect names the tribe, or when it belongs to the tribe and matches one of the tribe's effect patterns.
 func GenerateTribalTags(cardType string, effect string, tribes []string) []types.Tag {
 	var tags []types.Tag
 
-	// Check if card is of a tribal type
-	for _, tribalType := range TribalTypes {
-		if cardType == tribalType {
+	// Check if card belongs to a known tribe
+	members := make(map[common.Tribe]bool)
+	for _, tribe := range tribes {
+		if common.ValidTribes[common.Tribe(tribe)] {
+			members[common.Tribe(tribe)] = true
 			tags = append(tags, types.Tag{
-				Name:     tribalType + "_TRIBAL",
+				Name:     strings.ToUpper(tribe) + "_TRIBAL",
 				Category: types.TagTribal,
 				Weight:   2,
 			})
 		}
 	}
 
-	// Check for tribal effect patterns
-	for tribalType, patterns := range TribalEffectPatterns {
-		for _, pattern := range patterns {
-			// Pattern matching implementation would go here
-			// For brevity, using simple contains check
-			if pattern.Type == types.ExactMatch && effect == pattern.Value {
-				tags = append(tags, types.Tag{
-					Name:     tribalType + "_SYNERGY",
-					Category: types.TagSynergy,
-					Weight:   1,
-				})
-			}
+	// Check for tribe references and tribal effect patterns, in a stable order
+	known := make([]string, 0, len(common.ValidTribes))
+	for tribe := range common.ValidTribes {
+		known = append(known, string(tribe))
+	}
+	sort.Strings(known)
+
+	for _, name := range known {
+		tribe := common.Tribe(name)
+		mention := types.Pattern{Value: `\b` + name + `s?\b`, Type: types.RegexMatch}
+		_, synergy := defaultMatcher.MatchPattern(mention, effect)
+		if patterns := TribalEffectPatterns[tribe]; !synergy && members[tribe] && len(patterns) > 0 {
+			_, synergy = defaultMatcher.MatchPatterns(patterns, effect)
+		}
+		if synergy {
+			tags = append(tags, types.Tag{
+				Name:     strings.ToUpper(name) + "_SYNERGY",
+				Category: types.TagSynergy,
+				Weight:   1,
+			})
 		}
 	}
 
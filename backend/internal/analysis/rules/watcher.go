@@ -0,0 +1,94 @@
+package rules
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+)
+
+// ReloadFunc receives freshly loaded rules, or the error that prevented loading them
+type ReloadFunc func(rules []types.TagRule, err error)
+
+// Watcher polls a rule file or directory and reloads it when files change
+type Watcher struct {
+	path     string
+	interval time.Duration
+	onReload ReloadFunc
+	snapshot map[string]fileState
+}
+
+// fileState is the part of a file's metadata that signals a change
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// NewWatcher creates a watcher for a rule file or directory
+func NewWatcher(path string, interval time.Duration, onReload ReloadFunc) *Watcher {
+	w := &Watcher{
+		path:     path,
+		interval: interval,
+		onReload: onReload,
+	}
+	w.snapshot = w.scan()
+	return w
+}
+
+// Run polls until the context is cancelled, calling onReload whenever the rule files change
+func (w *Watcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if w.Check() {
+				w.onReload(LoadRules(w.path))
+			}
+		}
+	}
+}
+
+// Check reports whether the rule files changed since the last check
+func (w *Watcher) Check() bool {
+	current := w.scan()
+	changed := len(current) != len(w.snapshot)
+	if !changed {
+		for path, state := range current {
+			if previous, ok := w.snapshot[path]; !ok || previous != state {
+				changed = true
+				break
+			}
+		}
+	}
+	w.snapshot = current
+	return changed
+}
+
+// scan records the state of every rule file under the watched path
+func (w *Watcher) scan() map[string]fileState {
+	states := make(map[string]fileState)
+
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return states
+	}
+
+	paths := []string{w.path}
+	if info.IsDir() {
+		if paths, err = ruleFiles(w.path); err != nil {
+			return states
+		}
+	}
+
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil {
+			states[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+		}
+	}
+	return states
+}
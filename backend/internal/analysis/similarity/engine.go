@@ -0,0 +1,244 @@
+package similarity
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// Options controls how similarity scores are combined and reported
+type Options struct {
+	// Threshold is the combined score at which two cards are reported as near-duplicates
+	Threshold float64
+
+	// TextWeight and StatWeight balance rules text against cost and stats in the combined score
+	TextWeight float64
+	StatWeight float64
+
+	// StatTolerance is the summed cost/attack/defense difference at which stat similarity reaches zero
+	StatTolerance int
+
+	// MaxPairs caps the reported pairs, keeping the highest scores; zero reports every pair.
+	// Clusters are still built from every pair above the threshold.
+	MaxPairs int
+}
+
+// DefaultOptions returns the options used when none are configured
+func DefaultOptions() Options {
+	return Options{
+		Threshold:     0.85,
+		TextWeight:    0.75,
+		StatWeight:    0.25,
+		StatTolerance: 4,
+	}
+}
+
+// Member identifies a card in a similarity result
+type Member struct {
+	ID   string        `json:"id,omitempty"`
+	Name string        `json:"name"`
+	Type card.CardType `json:"type"`
+}
+
+// Pair is the similarity between two cards
+type Pair struct {
+	A         Member  `json:"a"`
+	B         Member  `json:"b"`
+	TextScore float64 `json:"text_score"`
+	StatScore float64 `json:"stat_score"`
+	Score     float64 `json:"score"`
+}
+
+// Cluster is a group of cards linked by near-duplicate pairs
+type Cluster struct {
+	Members  []Member `json:"members"`
+	MaxScore float64  `json:"max_score"`
+}
+
+// Result lists near-duplicate pairs and the clusters they form
+type Result struct {
+	Threshold float64   `json:"threshold"`
+	Pairs     []Pair    `json:"pairs"`
+	Truncated bool      `json:"truncated"` // pairs beyond MaxPairs were left out
+	Clusters  []Cluster `json:"clusters"`
+}
+
+// Engine compares cards for near-duplicate rules text and stats
+type Engine struct {
+	normalizer *Normalizer
+	options    Options
+}
+
+// NewEngine creates a similarity engine
+func NewEngine(normalizer *Normalizer, options Options) *Engine {
+	if normalizer == nil {
+		normalizer = NewNormalizer(nil)
+	}
+	return &Engine{
+		normalizer: normalizer,
+		options:    options,
+	}
+}
+
+// Compare scores the similarity of two cards
+func (e *Engine) Compare(a, b *card.CardDTO) Pair {
+	return e.compare(a, b,
+		shingles(e.normalizer.Normalize(a.Name, a.Effect)),
+		shingles(e.normalizer.Normalize(b.Name, b.Effect)),
+	)
+}
+
+func (e *Engine) compare(a, b *card.CardDTO, aShingles, bShingles map[string]bool) Pair {
+	pair := Pair{
+		A:         memberOf(a),
+		B:         memberOf(b),
+		TextScore: jaccard(aShingles, bShingles),
+		StatScore: e.statSimilarity(a, b),
+	}
+
+	totalWeight := e.options.TextWeight + e.options.StatWeight
+	if totalWeight > 0 {
+		pair.Score = (e.options.TextWeight*pair.TextScore + e.options.StatWeight*pair.StatScore) / totalWeight
+	}
+	return pair
+}
+
+// statSimilarity compares cost and, for creatures, attack and defense; different types never match
+func (e *Engine) statSimilarity(a, b *card.CardDTO) float64 {
+	if a.Type != b.Type {
+		return 0
+	}
+
+	diff := abs(a.Cost - b.Cost)
+	if a.Type == card.TypeCreature {
+		diff += abs(a.Attack-b.Attack) + abs(a.Defense-b.Defense)
+	}
+
+	if e.options.StatTolerance <= 0 {
+		if diff == 0 {
+			return 1
+		}
+		return 0
+	}
+	return math.Max(0, 1-float64(diff)/float64(e.options.StatTolerance))
+}
+
+// FindInStore finds near-duplicates among every card in the store
+func (e *Engine) FindInStore(s store.Store) (*Result, error) {
+	cards, err := s.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %w", err)
+	}
+
+	dtos := make([]*card.CardDTO, 0, len(cards))
+	for _, c := range cards {
+		dtos = append(dtos, c.ToDTO())
+	}
+	return e.FindDuplicates(dtos), nil
+}
+
+// FindDuplicates compares every pair of cards and groups those above the threshold into clusters
+func (e *Engine) FindDuplicates(cards []*card.CardDTO) *Result {
+	// Sort so results are stable regardless of store ordering
+	sorted := make([]*card.CardDTO, len(cards))
+	copy(sorted, cards)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+
+	cardShingles := make([]map[string]bool, len(sorted))
+	for i, c := range sorted {
+		cardShingles[i] = shingles(e.normalizer.Normalize(c.Name, c.Effect))
+	}
+
+	result := &Result{
+		Threshold: e.options.Threshold,
+		Pairs:     make([]Pair, 0),
+		Clusters:  make([]Cluster, 0),
+	}
+
+	sets := newDisjointSet(len(sorted))
+	maxScores := make(map[int]float64)
+	for i := 0; i < len(sorted); i++ {
+		for j := i + 1; j < len(sorted); j++ {
+			pair := e.compare(sorted[i], sorted[j], cardShingles[i], cardShingles[j])
+			if pair.Score < e.options.Threshold {
+				continue
+			}
+			result.Pairs = append(result.Pairs, pair)
+			sets.union(i, j)
+			maxScores[i] = math.Max(maxScores[i], pair.Score)
+			maxScores[j] = math.Max(maxScores[j], pair.Score)
+		}
+	}
+
+	sort.SliceStable(result.Pairs, func(i, j int) bool { return result.Pairs[i].Score > result.Pairs[j].Score })
+	if e.options.MaxPairs > 0 && len(result.Pairs) > e.options.MaxPairs {
+		result.Pairs = result.Pairs[:e.options.MaxPairs]
+		result.Truncated = true
+	}
+
+	groups := make(map[int][]int)
+	var roots []int
+	for i := range sorted {
+		if _, linked := maxScores[i]; !linked {
+			continue
+		}
+		root := sets.find(i)
+		if _, seen := groups[root]; !seen {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], i)
+	}
+
+	for _, root := range roots {
+		cluster := Cluster{}
+		for _, i := range groups[root] {
+			cluster.Members = append(cluster.Members, memberOf(sorted[i]))
+			cluster.MaxScore = math.Max(cluster.MaxScore, maxScores[i])
+		}
+		result.Clusters = append(result.Clusters, cluster)
+	}
+
+	return result
+}
+
+func memberOf(c *card.CardDTO) Member {
+	return Member{ID: c.ID, Name: c.Name, Type: c.Type}
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// disjointSet is a union-find structure used to build clusters
+type disjointSet struct {
+	parent []int
+}
+
+func newDisjointSet(n int) *disjointSet {
+	parent := make([]int, n)
+	for i := range parent {
+		parent[i] = i
+	}
+	return &disjointSet{parent: parent}
+}
+
+func (d *disjointSet) find(i int) int {
+	for d.parent[i] != i {
+		d.parent[i] = d.parent[d.parent[i]]
+		i = d.parent[i]
+	}
+	return i
+}
+
+func (d *disjointSet) union(a, b int) {
+	rootA, rootB := d.find(a), d.find(b)
+	if rootA != rootB {
+		d.parent[rootB] = rootA
+	}
+}
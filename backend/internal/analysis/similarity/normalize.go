@@ -0,0 +1,109 @@
+// Package similarity finds cards whose rules text and stats are near-duplicates
+package similarity
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// SelfToken replaces references a card makes to itself
+const SelfToken = "~"
+
+// DefaultSynonyms maps alternate wordings onto a canonical word
+var DefaultSynonyms = map[string]string{
+	"slay":      "destroy",
+	"kill":      "destroy",
+	"kills":     "destroy",
+	"destroys":  "destroy",
+	"banish":    "exile",
+	"exiles":    "exile",
+	"deals":     "deal",
+	"gains":     "gain",
+	"receive":   "gain",
+	"receives":  "gain",
+	"get":       "gain",
+	"gets":      "gain",
+	"obtain":    "gain",
+	"draws":     "draw",
+	"cards":     "card",
+	"creatures": "creature",
+	"tokens":    "token",
+	"an":        "a",
+	"one":       "1",
+}
+
+var numberWords = map[string]int{
+	"zero": 0, "two": 2, "three": 3, "four": 4, "five": 5,
+	"six": 6, "seven": 7, "eight": 8, "nine": 9, "ten": 10,
+}
+
+var (
+	selfReferencePattern = regexp.MustCompile(`(?i)\bthis (card|creature|artifact|spell|incantation|anthem)\b`)
+	tokenPattern         = regexp.MustCompile(`[+-]?\d+/[+-]?\d+|[+-]?\d+|[a-z~']+`)
+)
+
+// Normalizer turns effect text into comparable tokens
+type Normalizer struct {
+	synonyms map[string]string
+}
+
+// NewNormalizer creates a normalizer; nil synonyms selects DefaultSynonyms
+func NewNormalizer(synonyms map[string]string) *Normalizer {
+	if synonyms == nil {
+		synonyms = DefaultSynonyms
+	}
+	return &Normalizer{synonyms: synonyms}
+}
+
+// Normalize lowercases the effect, replaces the card's own name and self references
+// with SelfToken, spells numbers as digits and maps synonyms onto canonical words
+func (n *Normalizer) Normalize(name, effect string) []string {
+	text := effect
+	if name != "" {
+		text = regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(name)+`\b`).ReplaceAllString(text, SelfToken)
+	}
+	text = selfReferencePattern.ReplaceAllString(text, SelfToken)
+	text = strings.ToLower(text)
+
+	words := tokenPattern.FindAllString(text, -1)
+	tokens := make([]string, 0, len(words))
+	for _, word := range words {
+		if value, ok := numberWords[word]; ok {
+			word = strconv.Itoa(value)
+		}
+		if canonical, ok := n.synonyms[word]; ok {
+			word = canonical
+		}
+		tokens = append(tokens, word)
+	}
+	return tokens
+}
+
+// shingles returns the set of unigrams and bigrams in the tokens
+func shingles(tokens []string) map[string]bool {
+	set := make(map[string]bool, len(tokens)*2)
+	for i, token := range tokens {
+		set[token] = true
+		if i > 0 {
+			set[tokens[i-1]+" "+token] = true
+		}
+	}
+	return set
+}
+
+// jaccard returns the Jaccard index of two sets
+func jaccard(a, b map[string]bool) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	intersection := 0
+	for key := range a {
+		if b[key] {
+			intersection++
+		}
+	}
+	union := len(a) + len(b) - intersection
+	return float64(intersection) / float64(union)
+}
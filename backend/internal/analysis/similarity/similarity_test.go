@@ -0,0 +1,170 @@
+package similarity
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+func TestNormalize(t *testing.T) {
+	n := NewNormalizer(nil)
+
+	tests := []struct {
+		name     string
+		cardName string
+		effect   string
+		want     []string
+	}{
+		{
+			name:     "Number words and plurals",
+			cardName: "Insight",
+			effect:   "Draw two cards.",
+			want:     []string{"draw", "2", "card"},
+		},
+		{
+			name:     "Card name substitution",
+			cardName: "War Golem",
+			effect:   "War Golem gets +2/+0.",
+			want:     []string{"~", "gain", "+2/+0"},
+		},
+		{
+			name:     "Self reference phrase",
+			cardName: "Iron Wall",
+			effect:   "This creature can't be blocked.",
+			want:     []string{"~", "can't", "be", "blocked"},
+		},
+		{
+			name:     "Synonyms",
+			cardName: "Smite",
+			effect:   "Slay target creature.",
+			want:     []string{"destroy", "target", "creature"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := n.Normalize(tt.cardName, tt.effect); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Normalize() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompare(t *testing.T) {
+	e := NewEngine(nil, DefaultOptions())
+
+	tests := []struct {
+		name    string
+		a, b    *card.CardDTO
+		wantMin float64
+		wantMax float64
+	}{
+		{
+			name:    "Renamed copy",
+			a:       &card.CardDTO{Type: card.TypeSpell, Name: "Smite", Cost: 2, Effect: "Destroy target creature."},
+			b:       &card.CardDTO{Type: card.TypeSpell, Name: "Execute", Cost: 2, Effect: "Slay target creature."},
+			wantMin: 1,
+			wantMax: 1,
+		},
+		{
+			name:    "Same text, different cost",
+			a:       &card.CardDTO{Type: card.TypeSpell, Name: "Insight", Cost: 1, Effect: "Draw two cards."},
+			b:       &card.CardDTO{Type: card.TypeSpell, Name: "Research", Cost: 3, Effect: "Draw 2 cards."},
+			wantMin: 0.85,
+			wantMax: 0.9,
+		},
+		{
+			name:    "Same text, different type",
+			a:       &card.CardDTO{Type: card.TypeSpell, Name: "Insight", Cost: 1, Effect: "Draw 2 cards."},
+			b:       &card.CardDTO{Type: card.TypeIncantation, Name: "Study", Cost: 1, Effect: "Draw 2 cards."},
+			wantMin: 0.75,
+			wantMax: 0.75,
+		},
+		{
+			name:    "Unrelated",
+			a:       &card.CardDTO{Type: card.TypeSpell, Name: "Insight", Cost: 1, Effect: "Draw 2 cards."},
+			b:       &card.CardDTO{Type: card.TypeSpell, Name: "Smite", Cost: 5, Effect: "Destroy target creature."},
+			wantMin: 0,
+			wantMax: 0.1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := e.Compare(tt.a, tt.b)
+			if pair.Score < tt.wantMin-1e-9 || pair.Score > tt.wantMax+1e-9 {
+				t.Errorf("Score = %v, want in [%v, %v] (%+v)", pair.Score, tt.wantMin, tt.wantMax, pair)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	cards := []*card.CardDTO{
+		{Type: card.TypeCreature, Name: "Squire", Cost: 1, Effect: "ON PLAY - Draw 1 card.", Attack: 1, Defense: 1},
+		{Type: card.TypeCreature, Name: "Page", Cost: 1, Effect: "ON PLAY - Draw one card.", Attack: 1, Defense: 1},
+		{Type: card.TypeCreature, Name: "Herald", Cost: 1, Effect: "ON PLAY - draw a card.", Attack: 1, Defense: 2},
+		{Type: card.TypeSpell, Name: "Smite", Cost: 2, Effect: "Destroy target creature."},
+		{Type: card.TypeSpell, Name: "Execute", Cost: 2, Effect: "Slay target creature."},
+		{Type: card.TypeSpell, Name: "Insight", Cost: 1, Effect: "Draw 2 cards."},
+	}
+
+	result := NewEngine(nil, DefaultOptions()).FindDuplicates(cards)
+
+	if len(result.Clusters) != 2 {
+		t.Fatalf("got %d clusters, want 2: %+v", len(result.Clusters), result.Clusters)
+	}
+
+	var names [][]string
+	for _, cluster := range result.Clusters {
+		var members []string
+		for _, m := range cluster.Members {
+			members = append(members, m.Name)
+		}
+		names = append(names, members)
+	}
+
+	want := [][]string{{"Execute", "Smite"}, {"Page", "Squire"}}
+	if !reflect.DeepEqual(names, want) {
+		t.Errorf("clusters = %v, want %v", names, want)
+	}
+	if result.Pairs[0].Score != 1 {
+		t.Errorf("top pair score = %v, want 1", result.Pairs[0].Score)
+	}
+}
+
+func TestFindDuplicatesMaxPairs(t *testing.T) {
+	cards := []*card.CardDTO{
+		{Type: card.TypeCreature, Name: "Squire", Cost: 1, Effect: "ON PLAY - Draw 1 card.", Attack: 1, Defense: 1},
+		{Type: card.TypeCreature, Name: "Page", Cost: 1, Effect: "ON PLAY - Draw one card.", Attack: 1, Defense: 1},
+		{Type: card.TypeSpell, Name: "Smite", Cost: 2, Effect: "Destroy target creature."},
+		{Type: card.TypeSpell, Name: "Execute", Cost: 2, Effect: "Slay target creature."},
+	}
+
+	tests := []struct {
+		name          string
+		maxPairs      int
+		wantPairs     int
+		wantTruncated bool
+	}{
+		{"Unlimited", 0, 2, false},
+		{"Above the pair count", 5, 2, false},
+		{"Capped", 1, 1, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := DefaultOptions()
+			options.MaxPairs = tt.maxPairs
+			result := NewEngine(nil, options).FindDuplicates(cards)
+
+			if len(result.Pairs) != tt.wantPairs || result.Truncated != tt.wantTruncated {
+				t.Errorf("got %d pairs (truncated %v), want %d (truncated %v)", len(result.Pairs), result.Truncated, tt.wantPairs, tt.wantTruncated)
+			}
+			if len(result.Clusters) != 2 {
+				t.Errorf("got %d clusters, want 2", len(result.Clusters))
+			}
+		})
+	}
+}
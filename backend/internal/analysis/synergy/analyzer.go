@@ -0,0 +1,482 @@
+package synergy
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/rules"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/deck"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// Kind names a family of interactions
+type Kind string
+
+const (
+	KindTribal    Kind = "tribal"    // tribe member feeds tribal payoff
+	KindTokens    Kind = "tokens"    // token maker feeds token doubler
+	KindSacrifice Kind = "sacrifice" // sacrifice outlet feeds death trigger
+)
+
+// Coherence weights; they sum to 1
+const (
+	participationWeight = 0.6
+	connectivityWeight  = 0.4
+)
+
+// MaxCardScore is the highest CardScore; a card scores one point per card it interacts with
+const MaxCardScore = 10
+
+// Tagger generates tags for a card; satisfied by tagger.CardTagger
+type Tagger interface {
+	GenerateTags(card interface{}) ([]types.Tag, error)
+}
+
+// versionedTagger is a Tagger whose output only changes when its TagsVersion does;
+// satisfied by tagger.CardTagger. Only such taggers let CardScore cache the pool's profiles.
+type versionedTagger interface {
+	TagsVersion() string
+}
+
+// Entry is a card and how many copies of it are analyzed
+type Entry struct {
+	ID    string
+	Card  *card.CardDTO
+	Count int
+}
+
+// Node is a card in the interaction graph
+type Node struct {
+	ID    string        `json:"id"`
+	Name  string        `json:"name"`
+	Type  card.CardType `json:"type"`
+	Count int           `json:"count"`
+	Profile
+}
+
+// Interaction is a directed edge from an enabler to the card it feeds
+type Interaction struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Kind   Kind   `json:"kind"`
+	Tribe  string `json:"tribe,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Package is a multi-card synergy: every enabler and payoff of one kind (and tribe)
+type Package struct {
+	Kind     Kind     `json:"kind"`
+	Tribe    string   `json:"tribe,omitempty"`
+	Enablers []string `json:"enablers"`
+	Payoffs  []string `json:"payoffs"`
+	Copies   int      `json:"copies"`
+}
+
+// Report is the result of analyzing a deck or card pool
+type Report struct {
+	Deck          string        `json:"deck,omitempty"`
+	Cards         int           `json:"cards"`
+	Coherence     float64       `json:"coherence"`     // 0-100
+	Participation float64       `json:"participation"` // share of copies in at least one interaction
+	Connectivity  float64       `json:"connectivity"`  // share of copies in the largest connected group
+	Nodes         []Node        `json:"nodes"`
+	Interactions  []Interaction `json:"interactions"`
+	Packages      []Package     `json:"packages"`
+}
+
+// Analyzer builds interaction graphs for decks
+type Analyzer struct {
+	tagger  Tagger
+	matcher *rules.Matcher
+
+	// poolProfiles caches the profiles of the cards CardScore last scored against, keyed by
+	// card contents; it is dropped when poolVersion no longer matches the tagger's TagsVersion
+	poolMutex    sync.Mutex
+	poolVersion  string
+	poolProfiles map[string]Profile
+}
+
+// NewAnalyzer creates an analyzer that derives card roles from the tagger's tags
+func NewAnalyzer(tagger Tagger) *Analyzer {
+	return &Analyzer{
+		tagger:  tagger,
+		matcher: rules.NewMatcher(),
+	}
+}
+
+// AnalyzeStore analyzes one copy of every card in the store
+func (a *Analyzer) AnalyzeStore(s store.Store) (*Report, error) {
+	entries, err := storeEntries(s)
+	if err != nil {
+		return nil, err
+	}
+	return a.Analyze(entries)
+}
+
+// CardScore rates from 0 to MaxCardScore how many cards of the store the card interacts with.
+// Stored cards with the card's ID or name are left out, so a card never scores against itself.
+// Profiles of stored cards are cached between calls while the tagger's TagsVersion is unchanged.
+func (a *Analyzer) CardScore(c *card.CardDTO, s store.Store) (float64, error) {
+	pool, err := storeEntries(s)
+	if err != nil {
+		return 0, err
+	}
+
+	candidate := Entry{Card: c, Count: 1}
+	entries := make([]Entry, 0, len(pool))
+	for _, e := range pool {
+		if (c.ID != "" && e.Card.ID == c.ID) || strings.EqualFold(e.Card.Name, c.Name) {
+			continue
+		}
+		entries = append(entries, e)
+	}
+
+	nodes, err := a.nodes([]Entry{candidate})
+	if err != nil {
+		return 0, err
+	}
+	poolNodes, err := a.poolNodes(entries)
+	if err != nil {
+		return 0, err
+	}
+	nodes = append(nodes, poolNodes...)
+
+	id := nodeID(candidate)
+	partners := make(map[string]bool)
+	for _, edge := range interactions(nodes) {
+		switch id {
+		case edge.From:
+			partners[edge.To] = true
+		case edge.To:
+			partners[edge.From] = true
+		}
+	}
+	return math.Min(float64(len(partners)), MaxCardScore), nil
+}
+
+// poolNodes is nodes for stored cards, reusing the profiles cached by the previous call.
+// The cache is replaced by the profiles of this call, so cards no longer stored drop out of it.
+func (a *Analyzer) poolNodes(entries []Entry) ([]Node, error) {
+	versioned, ok := a.tagger.(versionedTagger)
+	if !ok {
+		return a.nodes(entries)
+	}
+	version := versioned.TagsVersion()
+
+	a.poolMutex.Lock()
+	cached := a.poolProfiles
+	if a.poolVersion != version {
+		cached = nil
+	}
+	a.poolMutex.Unlock()
+
+	profiles := make(map[string]Profile, len(entries))
+	nodes, err := a.buildNodes(entries, func(c *card.CardDTO) (Profile, error) {
+		key, err := cardFingerprint(c)
+		if err != nil {
+			return a.profile(c)
+		}
+		if p, ok := cached[key]; ok {
+			profiles[key] = p
+			return p, nil
+		}
+		p, err := a.profile(c)
+		if err == nil {
+			profiles[key] = p
+		}
+		return p, err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	a.poolMutex.Lock()
+	a.poolVersion, a.poolProfiles = version, profiles
+	a.poolMutex.Unlock()
+	return nodes, nil
+}
+
+// cardFingerprint identifies a card by its full contents, so an edited card is profiled again
+func cardFingerprint(c *card.CardDTO) (string, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// storeEntries lists one copy of every card in the store
+func storeEntries(s store.Store) ([]Entry, error) {
+	cards, err := s.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %w", err)
+	}
+
+	entries := make([]Entry, 0, len(cards))
+	for _, c := range cards {
+		entries = append(entries, Entry{Card: c.ToDTO(), Count: 1})
+	}
+	return entries, nil
+}
+
+// AnalyzeDeck analyzes a deck's main cards, resolving them through the card source
+func (a *Analyzer) AnalyzeDeck(d *deck.Deck, cards deck.CardSource) (*Report, error) {
+	entries := make([]Entry, 0, len(d.Cards))
+	for _, e := range d.Cards {
+		c, err := cards.Load(e.CardID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load card %s: %w", e.CardID, err)
+		}
+		entries = append(entries, Entry{ID: e.CardID, Card: c.ToDTO(), Count: e.Count})
+	}
+
+	report, err := a.Analyze(entries)
+	if err != nil {
+		return nil, err
+	}
+	report.Deck = d.Name
+	return report, nil
+}
+
+// Analyze builds the interaction graph, packages and coherence score for the entries
+func (a *Analyzer) Analyze(entries []Entry) (*Report, error) {
+	nodes, err := a.nodes(entries)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		Nodes:        nodes,
+		Interactions: make([]Interaction, 0),
+		Packages:     make([]Package, 0),
+	}
+	for _, n := range nodes {
+		report.Cards += n.Count
+	}
+
+	report.Interactions = interactions(nodes)
+	report.Packages = packages(nodes, report.Interactions)
+	report.Participation, report.Connectivity = coverage(nodes, report.Interactions, report.Cards)
+	report.Coherence = round(100 * (participationWeight*report.Participation + connectivityWeight*report.Connectivity))
+	return report, nil
+}
+
+// nodes tags each card and merges entries for the same card
+func (a *Analyzer) nodes(entries []Entry) ([]Node, error) {
+	return a.buildNodes(entries, a.profile)
+}
+
+// profile tags a card and derives its synergy profile
+func (a *Analyzer) profile(c *card.CardDTO) (Profile, error) {
+	tags, err := a.tagger.GenerateTags(c)
+	if err != nil {
+		return Profile{}, fmt.Errorf("failed to tag card %s: %w", c.Name, err)
+	}
+	return profile(a.matcher, c, tags), nil
+}
+
+// buildNodes is nodes with the profile of each card coming from profileOf
+func (a *Analyzer) buildNodes(entries []Entry, profileOf func(*card.CardDTO) (Profile, error)) ([]Node, error) {
+	index := make(map[string]int)
+	var nodes []Node
+
+	for _, e := range entries {
+		if e.Card == nil || e.Count <= 0 {
+			continue
+		}
+		id := nodeID(e)
+		if i, exists := index[id]; exists {
+			nodes[i].Count += e.Count
+			continue
+		}
+
+		p, err := profileOf(e.Card)
+		if err != nil {
+			return nil, err
+		}
+
+		index[id] = len(nodes)
+		nodes = append(nodes, Node{
+			ID:      id,
+			Name:    e.Card.Name,
+			Type:    e.Card.Type,
+			Count:   e.Count,
+			Profile: p,
+		})
+	}
+
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes, nil
+}
+
+// nodeID prefers the entry ID, then the card ID, then the card name
+func nodeID(e Entry) string {
+	switch {
+	case e.ID != "":
+		return e.ID
+	case e.Card.ID != "":
+		return e.Card.ID
+	default:
+		return e.Card.Name
+	}
+}
+
+// interactions pairs every enabler with every other card it feeds
+func interactions(nodes []Node) []Interaction {
+	result := make([]Interaction, 0)
+	for _, from := range nodes {
+		for _, to := range nodes {
+			if from.ID == to.ID {
+				continue
+			}
+
+			for _, tribe := range from.Members {
+				if contains(to.Payoffs, tribe) {
+					result = append(result, Interaction{
+						From:   from.ID,
+						To:     to.ID,
+						Kind:   KindTribal,
+						Tribe:  tribe,
+						Reason: fmt.Sprintf("%s is a %s for %s", from.Name, tribe, to.Name),
+					})
+				}
+			}
+			if from.Has(RoleTokenMaker) && to.Has(RoleTokenDoubler) {
+				result = append(result, Interaction{
+					From:   from.ID,
+					To:     to.ID,
+					Kind:   KindTokens,
+					Reason: fmt.Sprintf("%s creates tokens that %s multiplies", from.Name, to.Name),
+				})
+			}
+			if from.Has(RoleSacrificeOutlet) && to.Has(RoleDeathTrigger) {
+				result = append(result, Interaction{
+					From:   from.ID,
+					To:     to.ID,
+					Kind:   KindSacrifice,
+					Reason: fmt.Sprintf("%s can destroy creatures to trigger %s", from.Name, to.Name),
+				})
+			}
+		}
+	}
+	return result
+}
+
+// packages groups the interactions of each kind and tribe into multi-card synergies
+func packages(nodes []Node, edges []Interaction) []Package {
+	counts := make(map[string]int, len(nodes))
+	for _, n := range nodes {
+		counts[n.ID] = n.Count
+	}
+
+	type key struct {
+		kind  Kind
+		tribe string
+	}
+	enablers := make(map[key]map[string]bool)
+	payoffs := make(map[key]map[string]bool)
+	for _, edge := range edges {
+		k := key{edge.Kind, edge.Tribe}
+		if enablers[k] == nil {
+			enablers[k] = make(map[string]bool)
+			payoffs[k] = make(map[string]bool)
+		}
+		enablers[k][edge.From] = true
+		payoffs[k][edge.To] = true
+	}
+
+	result := make([]Package, 0, len(enablers))
+	for k := range enablers {
+		p := Package{
+			Kind:     k.kind,
+			Tribe:    k.tribe,
+			Enablers: sortedKeys(enablers[k]),
+			Payoffs:  sortedKeys(payoffs[k]),
+		}
+		members := make(map[string]bool)
+		for _, id := range append(p.Enablers, p.Payoffs...) {
+			if !members[id] {
+				members[id] = true
+				p.Copies += counts[id]
+			}
+		}
+		result = append(result, p)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Copies != result[j].Copies {
+			return result[i].Copies > result[j].Copies
+		}
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Tribe < result[j].Tribe
+	})
+	return result
+}
+
+// coverage returns the share of copies that interact with another card and
+// the share that sit in the largest connected group of interacting cards
+func coverage(nodes []Node, edges []Interaction, total int) (float64, float64) {
+	if total == 0 {
+		return 0, 0
+	}
+
+	index := make(map[string]int, len(nodes))
+	parent := make([]int, len(nodes))
+	for i, n := range nodes {
+		index[n.ID] = i
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	connected := make(map[int]bool)
+	for _, edge := range edges {
+		from, to := index[edge.From], index[edge.To]
+		connected[from], connected[to] = true, true
+		parent[find(from)] = find(to)
+	}
+
+	interacting := 0
+	groups := make(map[int]int)
+	largest := 0
+	for i, n := range nodes {
+		if !connected[i] {
+			continue
+		}
+		interacting += n.Count
+		root := find(i)
+		groups[root] += n.Count
+		if groups[root] > largest {
+			largest = groups[root]
+		}
+	}
+
+	return round(float64(interacting) / float64(total)), round(float64(largest) / float64(total))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// round keeps two decimal places
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
@@ -0,0 +1,155 @@
+// Package synergy finds interactions between the cards of a deck and scores how well they fit together
+package synergy
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/rules"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/common"
+)
+
+// Role is the part a card plays in a synergy
+type Role string
+
+const (
+	RoleTribalMember    Role = "tribal_member"    // belongs to a tribe or creates its tokens
+	RoleTribalPayoff    Role = "tribal_payoff"    // rewards a tribe
+	RoleTokenMaker      Role = "token_maker"      // creates tokens
+	RoleTokenDoubler    Role = "token_doubler"    // multiplies or rewards token creation
+	RoleSacrificeOutlet Role = "sacrifice_outlet" // destroys your own creatures on demand
+	RoleDeathTrigger    Role = "death_trigger"    // does something when a creature dies
+)
+
+// Tags that map directly to roles, produced by tagger.CardTagger
+const (
+	tagTokenGenerator = "TOKEN_GENERATOR"
+	tagTokenDoubling  = "TOKEN_DOUBLING"
+)
+
+// RoleRules detect the roles the tagger has no tag for
+var RoleRules = []types.TagRule{
+	{
+		Name:     string(RoleTokenDoubler),
+		Category: types.TagSynergy,
+		Patterns: []types.Pattern{
+			{Value: `double (the number of )?([\w/+-]+ )?tokens`, Type: types.RegexMatch},
+			{Value: `twice (that many|as many)[^.]*tokens`, Type: types.RegexMatch},
+			{Value: `tokens? would be created[^.]*instead`, Type: types.RegexMatch},
+			{Value: `whenever[^.;]*tokens? (enters|is created|are created)`, Type: types.RegexMatch},
+		},
+		Description: "Card multiplies or rewards token creation",
+	},
+	{
+		Name:     string(RoleSacrificeOutlet),
+		Category: types.TagSynergy,
+		Patterns: []types.Pattern{
+			{Value: `\bsacrifice (a|an|\d+|x|another|any number of)\b[^.]*\b(creature|permanent)s?\b`, Type: types.RegexMatch},
+			{Value: `\bdestroy (a|an|\d+|x) creatures? you control`, Type: types.RegexMatch},
+		},
+		Description: "Card destroys your own creatures on demand",
+	},
+	{
+		Name:     string(RoleDeathTrigger),
+		Category: types.TagSynergy,
+		Patterns: []types.Pattern{
+			{Value: `\bwhen(ever)?\b[^.;]*\b(dies|die|is destroyed|are destroyed|destroyed)\b`, Type: types.RegexMatch},
+		},
+		Description: "Card triggers when a creature dies",
+	},
+}
+
+// Profile is the set of roles a card plays
+type Profile struct {
+	Roles   []Role   `json:"roles"`
+	Members []string `json:"member_of,omitempty"` // tribes the card belongs to or creates
+	Payoffs []string `json:"payoff_for,omitempty"`
+}
+
+// Has reports whether the profile includes a role
+func (p Profile) Has(role Role) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// profile derives a card's roles from its tags and the role rules
+func profile(matcher *rules.Matcher, c *card.CardDTO, tags []types.Tag) Profile {
+	roles := make(map[Role]bool)
+	members := make(map[string]bool)
+	payoffs := make(map[string]bool)
+
+	for _, tag := range tags {
+		switch {
+		case tag.Name == tagTokenGenerator:
+			roles[RoleTokenMaker] = true
+		case tag.Name == tagTokenDoubling:
+			roles[RoleTokenDoubler] = true
+		case strings.HasSuffix(tag.Name, "_TRIBAL"):
+			if tribe, ok := tribeName(strings.TrimSuffix(tag.Name, "_TRIBAL")); ok {
+				members[tribe] = true
+			}
+		case strings.HasSuffix(tag.Name, "_SYNERGY"):
+			tribe, ok := tribeName(strings.TrimSuffix(tag.Name, "_SYNERGY"))
+			if !ok {
+				continue
+			}
+			// A card creating the tribe's tokens feeds the tribe rather than rewarding it
+			creates := types.Pattern{Value: `\bcreate\b[^.;]*\b` + tribe + `s?\b[^.;]*\btokens?\b`, Type: types.RegexMatch}
+			if _, ok := matcher.MatchPattern(creates, c.Effect); ok {
+				members[tribe] = true
+			} else {
+				payoffs[tribe] = true
+			}
+		}
+	}
+
+	for _, rule := range RoleRules {
+		if _, ok := matcher.EvaluateRule(c, rule); ok {
+			roles[Role(rule.Name)] = true
+		}
+	}
+	if len(members) > 0 {
+		roles[RoleTribalMember] = true
+	}
+	if len(payoffs) > 0 {
+		roles[RoleTribalPayoff] = true
+	}
+
+	p := Profile{
+		Members: sortedKeys(members),
+		Payoffs: sortedKeys(payoffs),
+	}
+	for role := range roles {
+		p.Roles = append(p.Roles, role)
+	}
+	sort.Slice(p.Roles, func(i, j int) bool { return p.Roles[i] < p.Roles[j] })
+	return p
+}
+
+// tribeName converts an upper-case tag prefix to the tribe it names
+func tribeName(prefix string) (string, bool) {
+	for tribe := range common.ValidTribes {
+		if strings.EqualFold(prefix, string(tribe)) {
+			return string(tribe), true
+		}
+	}
+	return "", false
+}
+
+func sortedKeys(set map[string]bool) []string {
+	if len(set) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,254 @@
+package synergy
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/deck"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/memory"
+)
+
+var (
+	goblinScout = &card.CardDTO{Type: card.TypeCreature, Name: "Goblin Scout", Cost: 1, Effect: "On Play - Draw a card. When destroyed, draw 1 card.", Attack: 1, Defense: 1, Trait: "Goblin"}
+	warchief    = &card.CardDTO{Type: card.TypeCreature, Name: "Goblin Warchief", Cost: 3, Effect: "Goblins you control gain HASTE.", Attack: 2, Defense: 2, Trait: "Goblin"}
+	assembly    = &card.CardDTO{Type: card.TypeAnthem, Name: "Goblin Assembly", Cost: 3, Effect: "At the end of your draw phase; create a 1/1 Goblin Token."}
+	parade      = &card.CardDTO{Type: card.TypeAnthem, Name: "Parade", Cost: 4, Effect: "Whenever a token enters the field under your control, draw a card."}
+	shadowbane  = &card.CardDTO{Type: card.TypeIncantation, Name: "Shadowbane Sacrifice", Cost: 3, Effect: "Negate target card being played and destroy 1 creature you control."}
+	fireball    = &card.CardDTO{Type: card.TypeIncantation, Name: "Fireball", Cost: 2, Effect: "Deal 3 damage to any creature."}
+)
+
+func TestProfile(t *testing.T) {
+	tests := []struct {
+		name        string
+		card        *card.CardDTO
+		wantRoles   []Role
+		wantMembers []string
+		wantPayoffs []string
+	}{
+		{"Member with death trigger", goblinScout, []Role{RoleDeathTrigger, RoleTribalMember}, []string{"Goblin"}, nil},
+		{"Member and payoff", warchief, []Role{RoleTribalMember, RoleTribalPayoff}, []string{"Goblin"}, []string{"Goblin"}},
+		{"Tribal token maker", assembly, []Role{RoleTokenMaker, RoleTribalMember}, []string{"Goblin"}, nil},
+		{"Token payoff", parade, []Role{RoleTokenDoubler}, nil, nil},
+		{"Sacrifice outlet", shadowbane, []Role{RoleSacrificeOutlet}, nil, nil},
+		{"No roles", fireball, nil, nil, nil},
+	}
+
+	analyzer := NewAnalyzer(tagger.NewCardTagger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nodes, err := analyzer.nodes([]Entry{{Card: tt.card, Count: 1}})
+			if err != nil {
+				t.Fatalf("nodes() error = %v", err)
+			}
+			p := nodes[0].Profile
+			if !reflect.DeepEqual(p.Roles, tt.wantRoles) {
+				t.Errorf("Roles = %v, want %v", p.Roles, tt.wantRoles)
+			}
+			if !reflect.DeepEqual(p.Members, tt.wantMembers) {
+				t.Errorf("Members = %v, want %v", p.Members, tt.wantMembers)
+			}
+			if !reflect.DeepEqual(p.Payoffs, tt.wantPayoffs) {
+				t.Errorf("Payoffs = %v, want %v", p.Payoffs, tt.wantPayoffs)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	analyzer := NewAnalyzer(tagger.NewCardTagger())
+	report, err := analyzer.Analyze([]Entry{
+		{Card: goblinScout, Count: 3},
+		{Card: warchief, Count: 3},
+		{Card: assembly, Count: 2},
+		{Card: parade, Count: 1},
+		{Card: shadowbane, Count: 2},
+		{Card: fireball, Count: 3},
+		{Card: fireball, Count: 1},
+	})
+	if err != nil {
+		t.Fatalf("Analyze() error = %v", err)
+	}
+
+	if report.Cards != 15 || len(report.Nodes) != 6 {
+		t.Errorf("Cards = %d, Nodes = %d, want 15 and 6", report.Cards, len(report.Nodes))
+	}
+
+	type edge struct {
+		from, to string
+		kind     Kind
+	}
+	got := make(map[edge]bool)
+	for _, i := range report.Interactions {
+		got[edge{i.From, i.To, i.Kind}] = true
+	}
+	want := []edge{
+		{"Goblin Scout", "Goblin Warchief", KindTribal},
+		{"Goblin Assembly", "Goblin Warchief", KindTribal},
+		{"Goblin Assembly", "Parade", KindTokens},
+		{"Shadowbane Sacrifice", "Goblin Scout", KindSacrifice},
+	}
+	if len(got) != len(want) {
+		t.Errorf("got %d interactions, want %d: %+v", len(got), len(want), report.Interactions)
+	}
+	for _, e := range want {
+		if !got[e] {
+			t.Errorf("missing interaction %+v", e)
+		}
+	}
+
+	if len(report.Packages) != 3 {
+		t.Fatalf("got %d packages, want 3: %+v", len(report.Packages), report.Packages)
+	}
+	tribal := report.Packages[0]
+	if tribal.Kind != KindTribal || tribal.Tribe != "Goblin" || tribal.Copies != 8 || len(tribal.Enablers) != 2 {
+		t.Errorf("unexpected tribal package: %+v", tribal)
+	}
+
+	// 11 of 15 copies interact and all of them are connected
+	if report.Participation != 0.73 || report.Connectivity != 0.73 || report.Coherence != 73 {
+		t.Errorf("Participation = %v, Connectivity = %v, Coherence = %v", report.Participation, report.Connectivity, report.Coherence)
+	}
+}
+
+func TestAnalyzeDeck(t *testing.T) {
+	cards := memory.New()
+	imp := &card.CardDTO{Type: card.TypeCreature, Name: "Imp", Cost: 1, Effect: "When destroyed, deal 1 damage to target player.", Attack: 1, Defense: 1, Trait: "Demon"}
+	for _, c := range []card.Card{card.NewCreatureFromDTO(imp), card.NewIncantationFromDTO(shadowbane)} {
+		if _, err := cards.Save(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	d := deck.New("Sacrifice")
+	d.AddCard("Creature-Imp", 3)
+	d.AddCard("Incantation-Shadowbane Sacrifice", 2)
+
+	analyzer := NewAnalyzer(tagger.NewCardTagger())
+	report, err := analyzer.AnalyzeDeck(d, cards)
+	if err != nil {
+		t.Fatalf("AnalyzeDeck() error = %v", err)
+	}
+	if report.Deck != "Sacrifice" || len(report.Interactions) != 1 || report.Coherence != 100 {
+		t.Errorf("unexpected report: %+v", report)
+	}
+
+	d.AddCard("Creature-Missing", 1)
+	if _, err := analyzer.AnalyzeDeck(d, cards); err == nil {
+		t.Error("AnalyzeDeck() succeeded with an unknown card")
+	}
+}
+
+func TestCardScore(t *testing.T) {
+	imp := &card.CardDTO{Type: card.TypeCreature, Name: "Imp", Cost: 1, Effect: "When destroyed, deal 1 damage to target player.", Attack: 1, Defense: 1, Trait: "Demon"}
+	overlord := &card.CardDTO{Type: card.TypeCreature, Name: "Pit Overlord", Cost: 5, Effect: "Demons you control gain +1/+1.", Attack: 4, Defense: 4}
+	fiend := &card.CardDTO{Type: card.TypeCreature, Name: "Fiend", Cost: 2, Effect: "When destroyed, draw 1 card.", Attack: 2, Defense: 1, Trait: "Demon"}
+	storedParade := *parade
+	storedParade.Continuous = true
+
+	cards := memory.New()
+	for _, c := range []card.Card{
+		card.NewCreatureFromDTO(imp),
+		card.NewCreatureFromDTO(overlord),
+		card.NewAnthemFromDTO(&storedParade),
+		card.NewIncantationFromDTO(shadowbane),
+		card.NewIncantationFromDTO(fireball),
+	} {
+		if _, err := cards.Save(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		card *card.CardDTO
+		want float64
+	}{
+		{"Tribe member with death trigger", fiend, 3},
+		{"Token maker", assembly, 1},
+		{"Stored card is not its own partner", shadowbane, 1},
+		{"No roles", fireball, 0},
+	}
+
+	analyzer := NewAnalyzer(tagger.NewCardTagger())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := analyzer.CardScore(tt.card, cards)
+			if err != nil {
+				t.Fatalf("CardScore() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("CardScore() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// countingTagger counts the cards a CardTagger is asked to tag
+type countingTagger struct {
+	*tagger.CardTagger
+	calls int
+}
+
+func (t *countingTagger) GenerateTags(c interface{}) ([]types.Tag, error) {
+	t.calls++
+	return t.CardTagger.GenerateTags(c)
+}
+
+func TestCardScoreCachesPool(t *testing.T) {
+	cards := memory.New()
+	for _, c := range []card.Card{
+		card.NewIncantationFromDTO(shadowbane),
+		card.NewIncantationFromDTO(fireball),
+	} {
+		if _, err := cards.Save(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counter := &countingTagger{CardTagger: tagger.NewCardTagger()}
+	analyzer := NewAnalyzer(counter)
+	imp := &card.CardDTO{Type: card.TypeCreature, Name: "Imp", Cost: 1, Effect: "When destroyed, draw 1 card.", Attack: 1, Defense: 1}
+
+	tests := []struct {
+		name   string
+		change func()
+		calls  int
+	}{
+		{"First call tags the pool", nil, 3},
+		{"Unchanged pool is cached", nil, 1},
+		{"Edited card is tagged again", func() {
+			edited := *fireball
+			edited.Effect = "Deal 4 damage to any creature."
+			if _, err := cards.Save(card.NewIncantationFromDTO(&edited)); err != nil {
+				t.Fatal(err)
+			}
+		}, 2},
+		{"Manual tag invalidates the pool", func() {
+			if err := counter.AddManualTag("Fireball", types.Tag{Name: "STAPLE", Category: types.TagStrategy, Weight: 1}); err != nil {
+				t.Fatal(err)
+			}
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.change != nil {
+				tt.change()
+			}
+			counter.calls = 0
+			score, err := analyzer.CardScore(imp, cards)
+			if err != nil {
+				t.Fatalf("CardScore() error = %v", err)
+			}
+			if score != 1 {
+				t.Errorf("CardScore() = %v, want 1", score)
+			}
+			if counter.calls != tt.calls {
+				t.Errorf("tagged %d cards, want %d", counter.calls, tt.calls)
+			}
+		})
+	}
+}
@@ -4,7 +4,8 @@ import (
 	"regexp"
 	"strings"
 
-	"github.com/ControlYourPotatoes/card-generator/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/complexity"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
 )
 
 // EffectDetector handles complex effect analysis
@@ -90,8 +91,7 @@ func (ed *EffectDetector) AnalyzeEffect(effect string) []types.Tag {
 	}
 
 	// Add complexity tag
-	complexity := ed.AnalyzeComplexity(effect)
-	if complexity > 3 {
+	if ed.AnalyzeComplexity(effect).Score > HighComplexityScore {
 		tags = append(tags, types.Tag{
 			Name:     "HIGH_COMPLEXITY",
 			Category: types.TagMechanic,
@@ -135,57 +135,17 @@ func (ed *EffectDetector) isConditional(effect string) bool {
 	return false
 }
 
-// DetectKeywords identifies keywords in effect text
+// DetectKeywords identifies keywords in effect text, sorted by name
 func (ed *EffectDetector) DetectKeywords(effect string) []string {
-	var keywords []string
-	keywordPatterns := map[string]string{
-		"FLYING":        "flying",
-		"FIRST_STRIKE":  "first strike",
-		"TRAMPLE":       "trample",
-		"HASTE":         "haste",
-		"VIGILANCE":     "vigilance",
-		"DEATHTOUCH":    "deathtouch",
-		"DOUBLE_STRIKE": "double strike",
-	}
-
-	for keyword, pattern := range keywordPatterns {
-		if strings.Contains(strings.ToLower(effect), pattern) {
-			keywords = append(keywords, keyword)
-		}
-	}
-
-	return keywords
+	return complexity.Keywords(effect)
 }
 
-// AnalyzeComplexity determines the complexity of an effect
-func (ed *EffectDetector) AnalyzeComplexity(effect string) int {
-	complexity := 0
-
-	// Count conditional statements
-	complexity += strings.Count(strings.ToLower(effect), "if")
-	complexity += strings.Count(strings.ToLower(effect), "when")
-	complexity += strings.Count(strings.ToLower(effect), "whenever")
-
-	// Count targeting
-	complexity += strings.Count(strings.ToLower(effect), "target")
-
-	// Count choices
-	complexity += strings.Count(strings.ToLower(effect), "choose")
-	complexity += strings.Count(strings.ToLower(effect), "may")
-
-	// Count numbers (usually indicating quantity)
-	numberPattern := regexp.MustCompile("\\d+")
-	complexity += len(numberPattern.FindAllString(effect, -1))
-
-	// Count keywords
-	keywords := ed.DetectKeywords(effect)
-	complexity += len(keywords)
-
-	// Count sentence separators
-	complexity += strings.Count(effect, ".")
-	complexity += strings.Count(effect, ";")
+// HighComplexityScore is the score above which effects are tagged HIGH_COMPLEXITY
+const HighComplexityScore = 8
 
-	return complexity
+// AnalyzeComplexity measures how hard an effect is to read
+func (ed *EffectDetector) AnalyzeComplexity(effect string) complexity.Complexity {
+	return complexity.Analyze(effect)
 }
 
 // AnalyzeInteractions detects card interactions
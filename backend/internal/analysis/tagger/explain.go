@@ -0,0 +1,133 @@
+package tagger
+
+import (
+	"context"
+	"log"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/rules"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+)
+
+// Tag sources reported in explanations
+const (
+	SourceBasic  = "basic"  // card type and cost
+	SourceTribal = "tribal" // card trait and tribal references
+	SourceCombo  = "combo"  // rules.DetectComboPotential
+	SourceRule   = "rule"   // a pattern-based types.TagRule
+	SourceModel  = "model"  // suggested by the types.EffectAnalyzer
+	SourceManual = "manual" // added with AddManualTag
+)
+
+// Span is the part of the effect text a pattern matched, as byte offsets
+type Span = rules.Span
+
+// ConditionResult is a rule condition the card satisfied
+type ConditionResult struct {
+	Type  types.ConditionType `json:"type"`
+	Value interface{}         `json:"value"`
+}
+
+// Explanation records why a card received a tag
+type Explanation struct {
+	Tag         types.Tag         `json:"tag"`
+	Source      string            `json:"source"`
+	Rule        string            `json:"rule,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Matches     []Span            `json:"matches,omitempty"`
+	Conditions  []ConditionResult `json:"conditions,omitempty"`
+}
+
+// ExplainTags generates the same tags as GenerateTags, each with the rule,
+// matched text spans and satisfied conditions that produced it
+func (ct *CardTagger) ExplainTags(c interface{}) ([]Explanation, error) {
+	return ct.ExplainTagsContext(context.Background(), c)
+}
+
+// ExplainTagsContext is ExplainTags with a context bounding the effect analyzer call
+func (ct *CardTagger) ExplainTagsContext(ctx context.Context, c interface{}) ([]Explanation, error) {
+	cardData, err := toCardDTO(c)
+	if err != nil {
+		return nil, err
+	}
+
+	var explanations []Explanation
+	add := func(source string, tags []types.Tag) {
+		for _, tag := range tags {
+			explanations = append(explanations, Explanation{Tag: tag, Source: source})
+		}
+	}
+
+	// Generate basic tags
+	add(SourceBasic, ct.generateBasicTags(cardData))
+
+	// Generate tribal tags using the card's trait and tribe references in its effect
+	var tribes []string
+	if cardData.Trait != "" {
+		tribes = []string{cardData.Trait}
+	}
+	add(SourceTribal, rules.GenerateTribalTags(string(cardData.Type), cardData.Effect, tribes))
+
+	// Generate combo tags
+	add(SourceCombo, rules.DetectComboPotential(cardData.Effect))
+
+	// Apply pattern-based rules
+	explanations = append(explanations, ct.applyRules(cardData)...)
+
+	// Add tags suggested by the effect analyzer; a failing analyzer or invalid suggestion never fails tagging
+	validator := ct.validator()
+	if analysis, err := ct.analyzeEffect(ctx, cardData.Effect); err != nil {
+		log.Printf("Effect analysis failed for %s: %v", cardData.Name, err)
+	} else if analysis != nil {
+		for _, tag := range analysis.Tags {
+			if err := validator.ValidateTag(tag); err != nil {
+				log.Printf("Dropping suggested tag for %s: %v", cardData.Name, err)
+				continue
+			}
+			add(SourceModel, []types.Tag{tag})
+		}
+	}
+
+	// Add any manual tags
+	manualTags, err := ct.manualTagsFor(cardData)
+	if err != nil {
+		return nil, err
+	}
+	add(SourceManual, manualTags)
+
+	// Deduplicate and validate tags
+	explanations = ct.deduplicateExplanations(explanations)
+	for _, explanation := range explanations {
+		if err := validator.ValidateTag(explanation.Tag); err != nil {
+			return nil, err
+		}
+	}
+
+	return explanations, nil
+}
+
+// SummarizeEffect returns the effect analyzer's summary of a card's effect,
+// or an empty string when no analyzer is configured
+func (ct *CardTagger) SummarizeEffect(ctx context.Context, c interface{}) (string, error) {
+	cardData, err := toCardDTO(c)
+	if err != nil {
+		return "", err
+	}
+
+	analysis, err := ct.analyzeEffect(ctx, cardData.Effect)
+	if err != nil || analysis == nil {
+		return "", err
+	}
+	return analysis.Summary, nil
+}
+
+// analyzeEffect consults the effect analyzer, returning nil when none is configured
+func (ct *CardTagger) analyzeEffect(ctx context.Context, effect string) (*types.EffectAnalysis, error) {
+	ct.cacheMutex.RLock()
+	analyzer := ct.analyzer
+	ct.cacheMutex.RUnlock()
+
+	if analyzer == nil || effect == "" {
+		return nil, nil
+	}
+	return analyzer.AnalyzeEffect(ctx, effect)
+}
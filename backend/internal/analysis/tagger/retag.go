@@ -0,0 +1,87 @@
+package tagger
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// RetagResult summarizes a re-tag run
+type RetagResult struct {
+	RulesVersion string `json:"rules_version"`
+	Checked      int    `json:"checked"`
+	Retagged     int    `json:"retagged"`
+	Unchanged    int    `json:"unchanged"`
+}
+
+// Retag regenerates the stored auto tags of every card in the card store whose
+// tags were generated by a different rule version, or of every card when force is set.
+// Manual tags are left untouched.
+func (ct *CardTagger) Retag(ctx context.Context, cards store.Store, force bool) (*RetagResult, error) {
+	ct.cacheMutex.RLock()
+	tagStore := ct.tagStore
+	ct.cacheMutex.RUnlock()
+	if tagStore == nil {
+		return nil, fmt.Errorf("no tag store configured")
+	}
+
+	list, err := cards.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list cards: %w", err)
+	}
+
+	versions, err := tagStore.RuleVersions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rule versions: %w", err)
+	}
+
+	result := &RetagResult{RulesVersion: ct.RulesVersion()}
+	for _, c := range list {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		result.Checked++
+
+		dto := c.ToDTO()
+		key := CardKey(dto)
+		if !force && versions[key] == result.RulesVersion {
+			result.Unchanged++
+			continue
+		}
+
+		tags, err := ct.autoTags(ctx, dto, result.RulesVersion)
+		if err != nil {
+			return result, fmt.Errorf("failed to tag card %s: %w", dto.Name, err)
+		}
+		if err := tagStore.ReplaceTags(key, types.TagSourceAuto, tags); err != nil {
+			return result, fmt.Errorf("failed to store tags for card %s: %w", dto.Name, err)
+		}
+		result.Retagged++
+	}
+
+	return result, nil
+}
+
+// autoTags converts every generated explanation for a card into stored tags
+func (ct *CardTagger) autoTags(ctx context.Context, c interface{}, version string) ([]types.CardTag, error) {
+	explanations, err := ct.ExplainTagsContext(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]types.CardTag, 0, len(explanations))
+	for _, explanation := range explanations {
+		if explanation.Source == SourceManual {
+			continue
+		}
+		tags = append(tags, types.CardTag{
+			Tag:         explanation.Tag,
+			Source:      types.TagSourceAuto,
+			Rule:        explanation.Rule,
+			RuleVersion: version,
+		})
+	}
+	return tags, nil
+}
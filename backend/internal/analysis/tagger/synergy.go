@@ -99,7 +99,7 @@ func (sd *SynergyDetector) AnalyzeSynergies(card *card.CardDTO) []types.Tag {
 }
 
 // detectTribalSynergies checks for tribal-based synergies
-func (sd *SynergyDetector) detectTribalSynergies(card *card.CardData) []types.Tag {
+func (sd *SynergyDetector) detectTribalSynergies(card *card.CardDTO) []types.Tag {
 	var tags []types.Tag
 	effectLower := strings.ToLower(card.Effect)
 
@@ -130,7 +130,7 @@ func (sd *SynergyDetector) detectTribalSynergies(card *card.CardData) []types.Ta
 }
 
 // detectMechanicSynergies checks for mechanic-based synergies
-func (sd *SynergyDetector) detectMechanicSynergies(card *card.CardData) []types.Tag {
+func (sd *SynergyDetector) detectMechanicSynergies(card *card.CardDTO) []types.Tag {
 	var tags []types.Tag
 	effectLower := strings.ToLower(card.Effect)
 
@@ -151,7 +151,7 @@ func (sd *SynergyDetector) detectMechanicSynergies(card *card.CardData) []types.
 }
 
 // detectSynergyPatterns checks for specific synergy patterns
-func (sd *SynergyDetector) detectSynergyPatterns(card *card.CardData) []types.Tag {
+func (sd *SynergyDetector) detectSynergyPatterns(card *card.CardDTO) []types.Tag {
 	var tags []types.Tag
 	effectLower := strings.ToLower(card.Effect)
 
@@ -172,7 +172,7 @@ func (sd *SynergyDetector) detectSynergyPatterns(card *card.CardData) []types.Ta
 }
 
 // AnalyzeComboSynergies detects potential combo synergies between multiple cards
-func (sd *SynergyDetector) AnalyzeComboSynergies(cards []card.CardData) []types.Tag {
+func (sd *SynergyDetector) AnalyzeComboSynergies(cards []*card.CardDTO) []types.Tag {
 	var tags []types.Tag
 
 	// Check each combo pattern
@@ -208,7 +208,7 @@ func (sd *SynergyDetector) AnalyzeComboSynergies(cards []card.CardData) []types.
 }
 
 // detectComboPattern checks if a set of cards matches a combo pattern
-func (sd *SynergyDetector) detectComboPattern(cards []card.CardData, patterns []string) bool {
+func (sd *SynergyDetector) detectComboPattern(cards []*card.CardDTO, patterns []string) bool {
 	patternMatches := make(map[string]bool)
 
 	for _, card := range cards {
@@ -231,7 +231,7 @@ func (sd *SynergyDetector) detectComboPattern(cards []card.CardData, patterns []
 }
 
 // detectCardAdvantageCombo checks for card draw/filtering combinations
-func (sd *SynergyDetector) detectCardAdvantageCombo(cards []card.CardData) bool {
+func (sd *SynergyDetector) detectCardAdvantageCombo(cards []*card.CardDTO) bool {
 	drawCount := 0
 	filterCount := 0
 
@@ -250,7 +250,7 @@ func (sd *SynergyDetector) detectCardAdvantageCombo(cards []card.CardData) bool
 }
 
 // detectResourceEngine checks for resource generation combinations
-func (sd *SynergyDetector) detectResourceEngine(cards []card.CardData) bool {
+func (sd *SynergyDetector) detectResourceEngine(cards []*card.CardDTO) bool {
 	resourceGen := 0
 	resourceUse := 0
 
@@ -1,29 +1,41 @@
 package tagger
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"strings"
+	"log"
 	"sync"
+	"time"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/rules"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
-	"github.com/dlclark/regexp2"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
 )
 
 // CardTagger implements the main tagging logic
 type CardTagger struct {
 	rules        []types.TagRule
-	regexCache   map[string]*regexp2.Regexp
+	version      string
+	reloadHooks  []func()
+	rulesMutex   sync.RWMutex
+	matcher      *rules.Matcher
 	cacheMutex   sync.RWMutex
 	manualTags   map[string][]types.Tag
+	changes      int
+	tagStore     store.TagStore
+	analyzer     types.EffectAnalyzer
 	tagValidator *types.TagValidator
 }
 
 // NewCardTagger creates a new instance of CardTagger
 func NewCardTagger() *CardTagger {
 	tagger := &CardTagger{
-		regexCache:   make(map[string]*regexp2.Regexp),
+		matcher:      rules.NewMatcher(),
 		manualTags:   make(map[string][]types.Tag),
 		tagValidator: types.NewTagValidator(),
 	}
@@ -33,71 +45,164 @@ func NewCardTagger() *CardTagger {
 
 // initializeRules loads all tagging rules
 func (ct *CardTagger) initializeRules() {
-	ct.rules = append(ct.rules, rules.BaseRules...)
-	ct.rules = append(ct.rules, rules.TribalRules...)
+	ct.rules = builtinRules()
+	ct.version = rulesVersion(ct.rules)
+}
+
+// builtinRules returns the rules compiled into the rules package
+func builtinRules() []types.TagRule {
+	var builtin []types.TagRule
+	builtin = append(builtin, rules.BaseRules...)
+	builtin = append(builtin, rules.TribalRules...)
+	builtin = append(builtin, rules.ArchetypeRules...)
+	builtin = append(builtin, rules.TimingRules...)
+	return builtin
+}
 
-	// Add archetype rules
-	for _, rule := range rules.ArchetypeRules {
-		ct.rules = append(ct.rules, rule)
+// MergeRules replaces any previously merged external rules with the given ones.
+// An external rule with the same name as a built-in rule overrides it.
+func (ct *CardTagger) MergeRules(external []types.TagRule) {
+	overrides := make(map[string]bool, len(external))
+	for _, rule := range external {
+		overrides[rule.Name] = true
 	}
 
-	// Add timing rules
-	for _, rule := range rules.TimingRules {
-		ct.rules = append(ct.rules, rule)
+	merged := make([]types.TagRule, 0, len(external))
+	for _, rule := range builtinRules() {
+		if !overrides[rule.Name] {
+			merged = append(merged, rule)
+		}
 	}
+	merged = append(merged, external...)
+
+	ct.rulesMutex.Lock()
+	ct.rules = merged
+	ct.version = rulesVersion(merged)
+	ct.rulesMutex.Unlock()
 }
 
-// GenerateTags generates tags for a card
-func (ct *CardTagger) GenerateTags(c interface{}) ([]types.Tag, error) {
-	var cardData *card.CardDTO
+// rulesVersion fingerprints a rule set so stored tags can be traced to the rules that generated them
+func rulesVersion(ruleSet []types.TagRule) string {
+	data, err := json.Marshal(ruleSet)
+	if err != nil {
+		data = []byte(fmt.Sprintf("%+v", ruleSet))
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])[:12]
+}
 
-	switch v := c.(type) {
-	case *card.CardDTO:
-		cardData = v
-	case card.Card:
-		cardData = v.ToDTO()
-	default:
-		return nil, fmt.Errorf("invalid card type: must be *card.CardDTO or card.Card")
+// RulesVersion returns the fingerprint of the rules currently used for tagging
+func (ct *CardTagger) RulesVersion() string {
+	ct.rulesMutex.RLock()
+	defer ct.rulesMutex.RUnlock()
+	return ct.version
+}
+
+// TagsVersion changes whenever GenerateTags may return different tags for the same card:
+// when the rules change or a manual tag, tag store, effect analyzer or taxonomy is set through this tagger
+func (ct *CardTagger) TagsVersion() string {
+	ct.cacheMutex.RLock()
+	changes := ct.changes
+	ct.cacheMutex.RUnlock()
+	return fmt.Sprintf("%s-%d", ct.RulesVersion(), changes)
+}
+
+// OnRulesReloaded registers a function WatchRules calls after each successful reload
+func (ct *CardTagger) OnRulesReloaded(fn func()) {
+	ct.rulesMutex.Lock()
+	defer ct.rulesMutex.Unlock()
+	ct.reloadHooks = append(ct.reloadHooks, fn)
+}
+
+// LoadRules loads rule files from a file or directory and merges them with the built-in rules
+func (ct *CardTagger) LoadRules(path string) error {
+	external, err := rules.LoadRules(path)
+	if err == nil {
+		err = validateRules(ct.validator(), external)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to load tag rules: %w", err)
 	}
+	ct.MergeRules(external)
+	return nil
+}
 
-	var tags []types.Tag
+// validateRules checks the tag each rule produces, so a rule naming a taxonomy synonym is
+// rejected when it is loaded rather than failing the tagging of every card it matches
+func validateRules(validator *types.TagValidator, ruleSet []types.TagRule) error {
+	for _, rule := range ruleSet {
+		if err := validator.ValidateTag(types.Tag{Name: rule.Name, Category: rule.Category}); err != nil {
+			return fmt.Errorf("invalid rule %s: %w", rule.Name, err)
+		}
+	}
+	return nil
+}
 
-	// Generate basic tags
-	basicTags := ct.generateBasicTags(cardData)
-	tags = append(tags, basicTags...)
-
-	// Generate tribal tags using the card's trait
-	if cardData.Trait != "" {
-		tribalTags := rules.GenerateTribalTags(
-			string(cardData.Type),
-			cardData.Effect,
-			[]string{cardData.Trait},
-		)
-		tags = append(tags, tribalTags...)
+// WatchRules loads rule files and reloads them whenever they change until the context is cancelled.
+// A reload that fails validation is logged and the previous rules stay active.
+func (ct *CardTagger) WatchRules(ctx context.Context, path string, interval time.Duration) error {
+	if err := ct.LoadRules(path); err != nil {
+		return err
 	}
 
-	// Generate combo tags
-	comboTags := rules.DetectComboPotential(cardData.Effect)
-	tags = append(tags, comboTags...)
+	watcher := rules.NewWatcher(path, interval, func(external []types.TagRule, err error) {
+		if err == nil {
+			err = validateRules(ct.validator(), external)
+		}
+		if err != nil {
+			log.Printf("Keeping previous tag rules, reload of %s failed: %v", path, err)
+			return
+		}
+		ct.MergeRules(external)
+		log.Printf("Reloaded %d tag rules from %s", len(external), path)
+
+		ct.rulesMutex.RLock()
+		hooks := ct.reloadHooks
+		ct.rulesMutex.RUnlock()
+		for _, hook := range hooks {
+			hook()
+		}
+	})
+	go watcher.Run(ctx)
+	return nil
+}
 
-	// Apply pattern-based rules
-	ruleTags := ct.applyRules(cardData)
-	tags = append(tags, ruleTags...)
+// Rules returns the rules currently used for tagging
+func (ct *CardTagger) Rules() []types.TagRule {
+	ct.rulesMutex.RLock()
+	defer ct.rulesMutex.RUnlock()
 
-	// Add any manual tags
-	if manualTags, exists := ct.manualTags[cardData.Name]; exists {
-		tags = append(tags, manualTags...)
-	}
+	result := make([]types.TagRule, len(ct.rules))
+	copy(result, ct.rules)
+	return result
+}
 
-	// Deduplicate and validate tags
-	tags = ct.deduplicateTags(tags)
-	if err := ct.tagValidator.ValidateTags(tags); err != nil {
+// GenerateTags generates tags for a card
+func (ct *CardTagger) GenerateTags(c interface{}) ([]types.Tag, error) {
+	explanations, err := ct.ExplainTags(c)
+	if err != nil {
 		return nil, err
 	}
 
+	tags := make([]types.Tag, 0, len(explanations))
+	for _, explanation := range explanations {
+		tags = append(tags, explanation.Tag)
+	}
 	return tags, nil
 }
 
+// toCardDTO accepts either a *card.CardDTO or a card.Card
+func toCardDTO(c interface{}) (*card.CardDTO, error) {
+	switch v := c.(type) {
+	case *card.CardDTO:
+		return v, nil
+	case card.Card:
+		return v.ToDTO(), nil
+	default:
+		return nil, fmt.Errorf("invalid card type: must be *card.CardDTO or card.Card")
+	}
+}
+
 // generateClassTags creates tags based on class types
 func (ct *CardTagger) generateClassTags(classes []string) []types.Tag {
 	var tags []types.Tag
@@ -137,143 +242,168 @@ func (ct *CardTagger) generateBasicTags(card *card.CardDTO) []types.Tag {
 	return tags
 }
 
-// applyRules applies all pattern-based rules to generate tags
-func (ct *CardTagger) applyRules(card *card.CardDTO) []types.Tag {
-	var tags []types.Tag
+// applyRules applies all pattern-based rules and explains each match
+func (ct *CardTagger) applyRules(card *card.CardDTO) []Explanation {
+	var explanations []Explanation
+
+	ct.rulesMutex.RLock()
+	defer ct.rulesMutex.RUnlock()
 
 	for _, rule := range ct.rules {
-		if ct.ruleMatches(card, rule) {
-			tags = append(tags, types.Tag{
-				Name:     rule.Name,
-				Category: rule.Category,
-				Weight:   rule.Weight,
-			})
+		if explanation, ok := ct.evaluateRule(card, rule); ok {
+			explanations = append(explanations, explanation)
 		}
 	}
 
-	return tags
+	return explanations
 }
 
-// ruleMatches checks if a card matches a specific rule
-func (ct *CardTagger) ruleMatches(card *card.CardDTO, rule types.TagRule) bool {
-	for _, pattern := range rule.Patterns {
-		matched := false
-
-		switch pattern.Type {
-		case types.ExactMatch:
-			matched = strings.Contains(
-				strings.ToLower(card.Effect),
-				strings.ToLower(pattern.Value),
-			)
-
-		case types.RegexMatch:
-			matched = ct.matchRegex(pattern.Value, card.Effect)
-
-		case types.ProximityMatch:
-			matched = ct.checkProximity(
-				pattern.Value,
-				card.Effect,
-				pattern.Proximity,
-			)
-		}
+// evaluateRule checks a rule against a card, recording the matched spans and conditions
+func (ct *CardTagger) evaluateRule(card *card.CardDTO, rule types.TagRule) (Explanation, bool) {
+	explanation := Explanation{
+		Tag: types.Tag{
+			Name:     rule.Name,
+			Category: rule.Category,
+			Weight:   rule.Weight,
+		},
+		Source:      SourceRule,
+		Rule:        rule.Name,
+		Description: rule.Description,
+	}
 
-		if !matched {
-			return false
-		}
+	match, ok := ct.matcher.EvaluateRule(card, rule)
+	if !ok {
+		return Explanation{}, false
 	}
 
-	// Check conditions if patterns match
-	return ct.checkConditions(card, rule.Conditions)
+	explanation.Matches = match.Spans
+	for _, condition := range match.Conditions {
+		explanation.Conditions = append(explanation.Conditions, ConditionResult{
+			Type:  condition.Type,
+			Value: condition.Value,
+		})
+	}
+
+	return explanation, true
 }
 
-// matchRegex performs regex matching with caching
-func (ct *CardTagger) matchRegex(pattern, text string) bool {
-	ct.cacheMutex.RLock()
-	regex, exists := ct.regexCache[pattern]
-	ct.cacheMutex.RUnlock()
+// SetTagStore persists manual tags in the store instead of memory and enables Retag
+func (ct *CardTagger) SetTagStore(tags store.TagStore) {
+	ct.cacheMutex.Lock()
+	defer ct.cacheMutex.Unlock()
+	ct.tagStore = tags
+	ct.changes++
+}
 
-	if !exists {
-		var err error
-		regex, err = regexp2.Compile(pattern, regexp2.IgnoreCase)
-		if err != nil {
-			return false
-		}
+// SetEffectAnalyzer makes the tagger consult the analyzer for tag suggestions and effect summaries
+func (ct *CardTagger) SetEffectAnalyzer(analyzer types.EffectAnalyzer) {
+	ct.cacheMutex.Lock()
+	defer ct.cacheMutex.Unlock()
+	ct.analyzer = analyzer
+	ct.changes++
+}
 
-		ct.cacheMutex.Lock()
-		ct.regexCache[pattern] = regex
-		ct.cacheMutex.Unlock()
+// SetTaxonomy makes the tagger reject taxonomy synonyms and miscategorized tags.
+// Rule and manual tags fail validation, while invalid model-suggested tags are dropped.
+func (ct *CardTagger) SetTaxonomy(taxonomy *types.Taxonomy) error {
+	validator := types.NewTagValidator().WithTaxonomy(taxonomy)
+	if err := validateRules(validator, ct.Rules()); err != nil {
+		return err
 	}
 
-	match, _ := regex.MatchString(text)
-	return match
+	ct.cacheMutex.Lock()
+	defer ct.cacheMutex.Unlock()
+	ct.tagValidator = validator
+	ct.changes++
+	return nil
 }
 
-// checkProximity checks if words appear within a certain distance
-func (ct *CardTagger) checkProximity(pattern, text string, maxDistance int) bool {
-	words := strings.Fields(strings.ToLower(text))
-	patternWords := strings.Fields(strings.ToLower(pattern))
-
-	for i := 0; i <= len(words)-len(patternWords); i++ {
-		matched := true
-		for j, patternWord := range patternWords {
-			if i+j >= len(words) || words[i+j] != patternWord {
-				matched = false
-				break
-			}
-		}
-		if matched && i <= maxDistance {
-			return true
-		}
-	}
-	return false
+// ValidateTag checks a tag the way AddManualTag does
+func (ct *CardTagger) ValidateTag(tag types.Tag) error {
+	return ct.validator().ValidateTag(tag)
 }
 
-// checkConditions verifies if a card meets all conditions
-func (ct *CardTagger) checkConditions(card *card.CardDTO, conditions []types.Condition) bool {
-	for _, condition := range conditions {
-		switch condition.Type {
-		case types.IsType:
-			if card.Type != condition.Value {
-				return false
-			}
-
-		case types.HasKeyword:
-			if !ct.hasKeyword(card, condition.Value.(string)) {
-				return false
-			}
-
-		case types.CostCondition:
-			if !ct.checkCostCondition(card.Cost, condition.Value) {
-				return false
-			}
-		}
+// validator returns the validator for the current taxonomy
+func (ct *CardTagger) validator() *types.TagValidator {
+	ct.cacheMutex.RLock()
+	defer ct.cacheMutex.RUnlock()
+	return ct.tagValidator
+}
+
+// AddCardTag adds a manual tag to a card under its CardKey, where tagging reads it back
+func (ct *CardTagger) AddCardTag(c interface{}, tag types.Tag) error {
+	cardData, err := toCardDTO(c)
+	if err != nil {
+		return err
 	}
-	return true
+	return ct.AddManualTag(CardKey(cardData), tag)
 }
 
-// AddManualTag adds a manual tag to a card
+// AddManualTag adds a manual tag under a card key; tagging only finds it when cardID is the card's CardKey
 func (ct *CardTagger) AddManualTag(cardID string, tag types.Tag) error {
-	if err := ct.tagValidator.ValidateTag(tag); err != nil {
+	if err := ct.ValidateTag(tag); err != nil {
 		return err
 	}
 
 	ct.cacheMutex.Lock()
 	defer ct.cacheMutex.Unlock()
+	ct.changes++
+
+	if ct.tagStore != nil {
+		if err := ct.tagStore.AddTag(types.CardTag{CardID: cardID, Tag: tag, Source: types.TagSourceManual}); err != nil {
+			return fmt.Errorf("failed to store manual tag: %w", err)
+		}
+		return nil
+	}
 
 	ct.manualTags[cardID] = append(ct.manualTags[cardID], tag)
 	return nil
 }
 
+// manualTagsFor returns the manual tags added for a card
+func (ct *CardTagger) manualTagsFor(c *card.CardDTO) ([]types.Tag, error) {
+	ct.cacheMutex.RLock()
+	defer ct.cacheMutex.RUnlock()
+
+	if ct.tagStore == nil {
+		return ct.manualTags[CardKey(c)], nil
+	}
+
+	stored, err := ct.tagStore.ListTags(CardKey(c))
+	if errors.Is(err, store.ErrInvalidKey) {
+		// A card the store cannot key, such as one without an ID, has no stored tags
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load manual tags: %w", err)
+	}
+	var tags []types.Tag
+	for _, tag := range stored {
+		if tag.Source == types.TagSourceManual {
+			tags = append(tags, tag.Tag)
+		}
+	}
+	return tags, nil
+}
+
+// CardKey identifies a card in manual and stored tags: its ID, or its name when it has none
+func CardKey(c *card.CardDTO) string {
+	if c.ID != "" {
+		return c.ID
+	}
+	return c.Name
+}
+
 // Helper functions
-func (ct *CardTagger) deduplicateTags(tags []types.Tag) []types.Tag {
+func (ct *CardTagger) deduplicateExplanations(explanations []Explanation) []Explanation {
 	seen := make(map[string]bool)
-	result := make([]types.Tag, 0)
+	result := make([]Explanation, 0)
 
-	for _, tag := range tags {
-		key := tag.Name + string(tag.Category)
+	for _, explanation := range explanations {
+		key := explanation.Tag.Name + string(explanation.Tag.Category)
 		if !seen[key] {
 			seen[key] = true
-			result = append(result, tag)
+			result = append(result, explanation)
 		}
 	}
 	return result
@@ -291,27 +421,3 @@ func (ct *CardTagger) categorizeCost(cost int) string {
 		return "VERY_HIGH_COST"
 	}
 }
-
-func (ct *CardTagger) hasKeyword(card *card.CardDTO, keyword string) bool {
-	return strings.Contains(
-		strings.ToLower(card.Effect),
-		strings.ToLower(keyword),
-	)
-}
-
-func (ct *CardTagger) checkCostCondition(cardCost int, condition interface{}) bool {
-	switch v := condition.(type) {
-	case int:
-		return cardCost == v
-	case map[string]int:
-		if min, ok := v["min"]; ok && cardCost < min {
-			return false
-		}
-		if max, ok := v["max"]; ok && cardCost > max {
-			return false
-		}
-		return true
-	default:
-		return false
-	}
-}
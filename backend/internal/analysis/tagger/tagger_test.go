@@ -0,0 +1,437 @@
+package tagger
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/llm"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/llm/llmtest"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/memory"
+)
+
+func hasTag(tags []types.Tag, name string) bool {
+	for _, tag := range tags {
+		if tag.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func TestMergeRules(t *testing.T) {
+	ct := NewCardTagger()
+	builtinCount := len(ct.Rules())
+
+	ct.MergeRules([]types.TagRule{
+		{
+			Name:     "LIFEGAIN",
+			Category: types.TagMechanic,
+			Patterns: []types.Pattern{{Value: `gain (\d+) life`, Type: types.RegexMatch}},
+			Weight:   2,
+		},
+		{
+			Name:     "REMOVAL",
+			Category: types.TagStrategy,
+			Patterns: []types.Pattern{{Value: "destroy target", Type: types.ExactMatch}},
+			Weight:   3,
+		},
+	})
+
+	if got := len(ct.Rules()); got != builtinCount+1 {
+		t.Errorf("got %d rules, want %d (one added, one overridden)", got, builtinCount+1)
+	}
+
+	tests := []struct {
+		name   string
+		effect string
+		want   string
+	}{
+		{"External rule", "ON PLAY - Gain 3 life.", "LIFEGAIN"},
+		{"Overridden built-in", "Destroy target creature.", "REMOVAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := ct.GenerateTags(&card.CardDTO{Type: card.TypeSpell, Name: "Test", Cost: 2, Effect: tt.effect})
+			if err != nil {
+				t.Fatalf("GenerateTags() error = %v", err)
+			}
+			if !hasTag(tags, tt.want) {
+				t.Errorf("tags %+v missing %s", tags, tt.want)
+			}
+		})
+	}
+
+	// Merging again replaces the previous external rules
+	ct.MergeRules(nil)
+	if got := len(ct.Rules()); got != builtinCount {
+		t.Errorf("got %d rules after reset, want %d", got, builtinCount)
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	ct := NewCardTagger()
+	if err := ct.LoadRules("../../../config/tag_rules"); err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	tags, err := ct.GenerateTags(&card.CardDTO{Type: card.TypeSpell, Name: "Mend", Cost: 1, Effect: "Gain 2 life."})
+	if err != nil {
+		t.Fatalf("GenerateTags() error = %v", err)
+	}
+	if !hasTag(tags, "LIFEGAIN") {
+		t.Errorf("tags %+v missing LIFEGAIN", tags)
+	}
+}
+
+func TestExplainTags(t *testing.T) {
+	ct := NewCardTagger()
+	ct.MergeRules([]types.TagRule{
+		{
+			Name:        "CHEAP_TOKENS",
+			Category:    types.TagStrategy,
+			Patterns:    []types.Pattern{{Value: "token", Type: types.ExactMatch}},
+			Conditions:  []types.Condition{{Type: types.CostCondition, Value: map[string]int{"max": 2}}},
+			Weight:      1,
+			Description: "Cheap token maker",
+		},
+	})
+
+	tests := []struct {
+		name       string
+		effect     string
+		rule       string
+		wantText   []string
+		wantStart  int
+		conditions int
+	}{
+		{
+			name:      "Regex spans",
+			effect:    "ON PLAY - Create a 1/1 Soldier token.",
+			rule:      "TOKEN_GENERATOR",
+			wantText:  []string{"Create a 1/1 Soldier token", "Create a 1/1 Soldier token"},
+			wantStart: 10,
+		},
+		{
+			name:      "Byte offsets after multi-byte runes",
+			effect:    "Éclair — create a 2/2 token.",
+			rule:      "TOKEN_GENERATOR",
+			wantText:  []string{"create a 2/2 token", "create a 2/2 token"},
+			wantStart: len("Éclair — "),
+		},
+		{
+			name:       "Exact match with condition",
+			effect:     "Create a 1/1 Soldier token.",
+			rule:       "CHEAP_TOKENS",
+			wantText:   []string{"token"},
+			wantStart:  21,
+			conditions: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			explanations, err := ct.ExplainTags(&card.CardDTO{Type: card.TypeSpell, Name: "Muster", Cost: 2, Effect: tt.effect})
+			if err != nil {
+				t.Fatalf("ExplainTags() error = %v", err)
+			}
+
+			var found *Explanation
+			for i := range explanations {
+				if explanations[i].Rule == tt.rule {
+					found = &explanations[i]
+				}
+			}
+			if found == nil {
+				t.Fatalf("no explanation for %s in %+v", tt.rule, explanations)
+			}
+
+			if found.Source != SourceRule || len(found.Matches) != len(tt.wantText) {
+				t.Fatalf("unexpected explanation: %+v", found)
+			}
+			for i, span := range found.Matches {
+				if span.Text != tt.wantText[i] || tt.effect[span.Start:span.End] != span.Text {
+					t.Errorf("match %d = %+v, want text %q", i, span, tt.wantText[i])
+				}
+			}
+			if found.Matches[0].Start != tt.wantStart {
+				t.Errorf("Start = %d, want %d", found.Matches[0].Start, tt.wantStart)
+			}
+			if len(found.Conditions) != tt.conditions {
+				t.Errorf("Conditions = %+v, want %d", found.Conditions, tt.conditions)
+			}
+		})
+	}
+
+	t.Run("Basic tags", func(t *testing.T) {
+		explanations, err := ct.ExplainTags(&card.CardDTO{Type: card.TypeSpell, Name: "Muster", Cost: 2, Effect: "Draw."})
+		if err != nil {
+			t.Fatalf("ExplainTags() error = %v", err)
+		}
+		if len(explanations) == 0 || explanations[0].Tag.Name != string(card.TypeSpell) || explanations[0].Source != SourceBasic {
+			t.Errorf("unexpected explanations: %+v", explanations)
+		}
+	})
+}
+
+func TestAddCardTag(t *testing.T) {
+	staple := types.Tag{Name: "STAPLE", Category: types.TagStrategy, Weight: 1}
+	tests := []struct {
+		name     string
+		card     interface{}
+		tagStore store.TagStore
+	}{
+		{"Card with an ID", &card.CardDTO{ID: "42", Type: card.TypeSpell, Name: "Mend", Cost: 1, Effect: "Gain 2 life."}, nil},
+		{"Card without an ID", card.NewSpellFromDTO(&card.CardDTO{Type: card.TypeSpell, Name: "Mend", Cost: 1, Effect: "Gain 2 life."}), nil},
+		{"Stored tag", &card.CardDTO{ID: "42", Type: card.TypeSpell, Name: "Mend", Cost: 1, Effect: "Gain 2 life."}, memory.NewTagStore()},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ct := NewCardTagger()
+			if tt.tagStore != nil {
+				ct.SetTagStore(tt.tagStore)
+			}
+			if err := ct.AddCardTag(tt.card, staple); err != nil {
+				t.Fatalf("AddCardTag() error = %v", err)
+			}
+
+			tags, err := ct.GenerateTags(tt.card)
+			if err != nil {
+				t.Fatalf("GenerateTags() error = %v", err)
+			}
+			if !hasTag(tags, "STAPLE") {
+				t.Errorf("manual tag not found in %+v", tags)
+			}
+		})
+	}
+}
+
+// numericTagStore keys tags by numeric card ID, as the Postgres store does
+type numericTagStore struct {
+	store.TagStore
+}
+
+func (s numericTagStore) ListTags(id string) ([]types.CardTag, error) {
+	if _, err := strconv.Atoi(id); err != nil {
+		return nil, fmt.Errorf("invalid card ID: %s: %w", id, store.ErrInvalidKey)
+	}
+	return s.TagStore.ListTags(id)
+}
+
+func TestManualTagsWithNumericStore(t *testing.T) {
+	ct := NewCardTagger()
+	ct.SetTagStore(numericTagStore{memory.NewTagStore()})
+	staple := types.Tag{Name: "STAPLE", Category: types.TagStrategy, Weight: 1}
+	if err := ct.AddManualTag("42", staple); err != nil {
+		t.Fatalf("AddManualTag() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		card     *card.CardDTO
+		wantTags bool
+	}{
+		{"Card with an ID", &card.CardDTO{ID: "42", Type: card.TypeSpell, Name: "Mend", Cost: 1, Effect: "Gain 2 life."}, true},
+		{"Card without an ID", &card.CardDTO{Type: card.TypeSpell, Name: "Mend", Cost: 1, Effect: "Gain 2 life."}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tags, err := ct.GenerateTags(tt.card)
+			if err != nil {
+				t.Fatalf("GenerateTags() error = %v", err)
+			}
+			if hasTag(tags, "STAPLE") != tt.wantTags {
+				t.Errorf("GenerateTags() = %+v, want STAPLE: %v", tags, tt.wantTags)
+			}
+			if _, err := ct.ExplainTags(tt.card); err != nil {
+				t.Errorf("ExplainTags() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestRetag(t *testing.T) {
+	cards := memory.New()
+	for _, dto := range []*card.CardDTO{
+		{Type: card.TypeSpell, Name: "Mend", Cost: 1, Effect: "Gain 2 life."},
+		{Type: card.TypeSpell, Name: "Smite", Cost: 2, Effect: "Destroy target creature."},
+	} {
+		if _, err := cards.Save(card.NewSpellFromDTO(dto)); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+
+	ct := NewCardTagger()
+	if _, err := ct.Retag(context.Background(), cards, false); err == nil {
+		t.Fatal("Retag() without a tag store should fail")
+	}
+
+	tagStore := memory.NewTagStore()
+	ct.SetTagStore(tagStore)
+	if err := ct.AddManualTag("Mend", types.Tag{Name: "STAPLE", Category: types.TagStrategy, Weight: 1}); err != nil {
+		t.Fatalf("AddManualTag() error = %v", err)
+	}
+
+	lifegain := types.TagRule{
+		Name:     "LIFEGAIN",
+		Category: types.TagMechanic,
+		Patterns: []types.Pattern{{Value: `gain (\d+) life`, Type: types.RegexMatch}},
+		Weight:   2,
+	}
+
+	tests := []struct {
+		name      string
+		rules     []types.TagRule
+		force     bool
+		retagged  int
+		unchanged int
+	}{
+		{"Initial run", nil, false, 2, 0},
+		{"Same rules", nil, false, 0, 2},
+		{"Forced", nil, true, 2, 0},
+		{"Rules changed", []types.TagRule{lifegain}, false, 2, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.rules != nil {
+				before := ct.RulesVersion()
+				ct.MergeRules(tt.rules)
+				if ct.RulesVersion() == before {
+					t.Fatal("rules version did not change after MergeRules")
+				}
+			}
+
+			result, err := ct.Retag(context.Background(), cards, tt.force)
+			if err != nil {
+				t.Fatalf("Retag() error = %v", err)
+			}
+			if result.Retagged != tt.retagged || result.Unchanged != tt.unchanged {
+				t.Errorf("Retag() = %+v, want %d retagged and %d unchanged", result, tt.retagged, tt.unchanged)
+			}
+		})
+	}
+
+	ids, err := tagStore.FindCards(types.TagQuery{All: []string{"LIFEGAIN", "STAPLE"}})
+	if err != nil {
+		t.Fatalf("FindCards() error = %v", err)
+	}
+	if len(ids) != 1 || ids[0] != "Mend" {
+		t.Errorf("FindCards() = %v, want [Mend]", ids)
+	}
+
+	ids, err = tagStore.FindCards(types.TagQuery{Any: []string{"STAPLE"}, Source: types.TagSourceAuto})
+	if err != nil {
+		t.Fatalf("FindCards() error = %v", err)
+	}
+	if len(ids) != 0 {
+		t.Errorf("FindCards() with auto source = %v, want none", ids)
+	}
+
+	tags, err := ct.GenerateTags(&card.CardDTO{Type: card.TypeSpell, Name: "Mend", Cost: 1, Effect: "Gain 2 life."})
+	if err != nil {
+		t.Fatalf("GenerateTags() error = %v", err)
+	}
+	if !hasTag(tags, "STAPLE") {
+		t.Errorf("tags %+v missing stored manual tag STAPLE", tags)
+	}
+}
+
+func TestEffectAnalyzer(t *testing.T) {
+	fake := llmtest.NewServer()
+	defer fake.Close()
+
+	ct := NewCardTagger()
+	dto := &card.CardDTO{Type: card.TypeSpell, Name: "Insight", Cost: 2, Effect: "Draw 2 cards."}
+
+	summary, err := ct.SummarizeEffect(context.Background(), dto)
+	if err != nil || summary != "" {
+		t.Fatalf("SummarizeEffect() without analyzer = %q, %v", summary, err)
+	}
+
+	ct.SetEffectAnalyzer(llm.NewClient(llm.Config{BaseURL: fake.URL, Model: "fake"}))
+	explanations, err := ct.ExplainTags(dto)
+	if err != nil {
+		t.Fatalf("ExplainTags() error = %v", err)
+	}
+
+	found := false
+	for _, explanation := range explanations {
+		if explanation.Tag.Name == "CARD_DRAW" && explanation.Source == SourceModel {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("explanations %+v missing model-suggested CARD_DRAW", explanations)
+	}
+
+	summary, err = ct.SummarizeEffect(context.Background(), dto)
+	if err != nil || summary == "" {
+		t.Errorf("SummarizeEffect() = %q, %v", summary, err)
+	}
+	if fake.Requests() != 1 {
+		t.Errorf("server handled %d requests, want 1", fake.Requests())
+	}
+
+	// A failing analyzer does not fail tagging
+	fake.Close()
+	if _, err := ct.GenerateTags(&card.CardDTO{Type: card.TypeSpell, Name: "Bolt", Cost: 1, Effect: "Deal 3 damage."}); err != nil {
+		t.Errorf("GenerateTags() with unreachable analyzer error = %v", err)
+	}
+}
+
+// suggestingAnalyzer suggests the same tags for every effect
+type suggestingAnalyzer []types.Tag
+
+func (a suggestingAnalyzer) AnalyzeEffect(ctx context.Context, effect string) (*types.EffectAnalysis, error) {
+	return &types.EffectAnalysis{Tags: a}, nil
+}
+
+func TestSetTaxonomy(t *testing.T) {
+	ct := NewCardTagger()
+	if err := ct.SetTaxonomy(types.DefaultTaxonomy()); err != nil {
+		t.Fatalf("SetTaxonomy() error = %v", err)
+	}
+	if err := ct.LoadRules("../../../config/tag_rules"); err != nil {
+		t.Fatalf("LoadRules() error = %v", err)
+	}
+
+	ct.SetEffectAnalyzer(suggestingAnalyzer{
+		{Name: "DESTRUCTION", Category: types.TagStrategy, Weight: 1},
+		{Name: "DESTROY", Category: types.TagStrategy, Weight: 1},
+	})
+	explanations, err := ct.ExplainTags(&card.CardDTO{Type: card.TypeSpell, Name: "Smite", Cost: 3, Effect: "Destroy target creature."})
+	if err != nil {
+		t.Fatalf("ExplainTags() error = %v", err)
+	}
+	for _, explanation := range explanations {
+		if explanation.Tag.Name == "DESTRUCTION" {
+			t.Errorf("explanations %+v kept the suggested synonym DESTRUCTION", explanations)
+		}
+	}
+
+	dto := &card.CardDTO{ID: "7", Type: card.TypeSpell, Name: "Smite", Cost: 3, Effect: "Destroy target creature."}
+	if err := ct.AddCardTag(dto, types.Tag{Name: "DESTRUCTION", Category: types.TagStrategy}); err == nil {
+		t.Error("AddCardTag() with a synonym should fail")
+	}
+	if err := ct.AddCardTag(dto, types.Tag{Name: "DESTROY", Category: types.TagMechanic}); err == nil {
+		t.Error("AddCardTag() with the wrong category should fail")
+	}
+
+	synonymRule := types.TagRule{
+		Name:     "DESTRUCTION",
+		Category: types.TagStrategy,
+		Patterns: []types.Pattern{{Value: "destroy", Type: types.ExactMatch}},
+	}
+	ct.MergeRules([]types.TagRule{synonymRule})
+	if err := ct.SetTaxonomy(types.DefaultTaxonomy()); err == nil {
+		t.Error("SetTaxonomy() with a rule named after a synonym should fail")
+	}
+}
@@ -0,0 +1,83 @@
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// TagSource records whether a stored tag was generated or added by hand
+type TagSource string
+
+const (
+	TagSourceAuto   TagSource = "auto"
+	TagSourceManual TagSource = "manual"
+)
+
+// ParseTagSource converts a source name to a TagSource; an empty name means any source
+func ParseTagSource(name string) (TagSource, error) {
+	switch TagSource(name) {
+	case "", TagSourceAuto, TagSourceManual:
+		return TagSource(name), nil
+	default:
+		return "", fmt.Errorf("invalid tag source: %s", name)
+	}
+}
+
+// CardTag is a tag stored for a card
+type CardTag struct {
+	CardID string `json:"card_id"`
+	Tag
+	Source      TagSource `json:"source"`
+	Rule        string    `json:"rule,omitempty"`         // rule that generated an auto tag
+	RuleVersion string    `json:"rule_version,omitempty"` // version of the rule set that generated an auto tag
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// TagQuery selects cards by their stored tags; empty fields are ignored
+type TagQuery struct {
+	All    []string  // card has every one of these tags
+	Any    []string  // card has at least one of these tags
+	None   []string  // card has none of these tags
+	Source TagSource // only consider tags from this source
+
+	// Expand lists further tags that count as a match for a queried tag, e.g. its descendants
+	Expand map[string][]string
+}
+
+// Terms returns a queried tag followed by the tags that count as a match for it
+func (q TagQuery) Terms(name string) []string {
+	return append([]string{name}, q.Expand[name]...)
+}
+
+// hasAny reports whether names contain a queried tag or one of its expansions
+func (q TagQuery) hasAny(names map[string]bool, name string) bool {
+	for _, term := range q.Terms(name) {
+		if names[term] {
+			return true
+		}
+	}
+	return false
+}
+
+// Matches reports whether a card's tag names satisfy the query
+func (q TagQuery) Matches(names map[string]bool) bool {
+	for _, name := range q.All {
+		if !q.hasAny(names, name) {
+			return false
+		}
+	}
+	for _, name := range q.None {
+		if q.hasAny(names, name) {
+			return false
+		}
+	}
+	if len(q.Any) == 0 {
+		return true
+	}
+	for _, name := range q.Any {
+		if q.hasAny(names, name) {
+			return true
+		}
+	}
+	return false
+}
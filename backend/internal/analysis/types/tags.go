@@ -1,5 +1,11 @@
 package types
 
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
 // Tag represents a single card tag with its category
 type Tag struct {
 	Name     string      `json:"name"`
@@ -21,7 +27,9 @@ const (
 	TagTiming    TagCategory = "TIMING"
 )
 
-// TagRule defines a rule for generating tags
+// TagRule defines a rule for generating tags.
+// Patterns that share a Group are alternatives and every group must match;
+// NegationMatch patterns must not match. All conditions must hold.
 type TagRule struct {
 	Name        string
 	Category    TagCategory
@@ -35,19 +43,46 @@ type TagRule struct {
 type Pattern struct {
 	Value     string
 	Type      PatternType
-	Proximity int
+	Proximity int // ProximityMatch: max words allowed between consecutive pattern words
+	Group     int // patterns in the same group are ORed, groups are ANDed
 }
 
 // PatternType defines different ways to match patterns
 type PatternType int
 
 const (
-	ExactMatch PatternType = iota
-	RegexMatch
-	ProximityMatch
-	NegationMatch
+	ExactMatch     PatternType = iota // case-insensitive substring
+	RegexMatch                        // case-insensitive regular expression
+	ProximityMatch                    // words in order, each within Proximity words of the previous
+	NegationMatch                     // regular expression that must not match
 )
 
+// patternTypeNames maps each PatternType to the name used in rule files
+var patternTypeNames = map[PatternType]string{
+	ExactMatch:     "exact",
+	RegexMatch:     "regex",
+	ProximityMatch: "proximity",
+	NegationMatch:  "negation",
+}
+
+// String returns the rule file name of the pattern type
+func (p PatternType) String() string {
+	if name, ok := patternTypeNames[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("PatternType(%d)", int(p))
+}
+
+// ParsePatternType converts a rule file pattern type name to a PatternType
+func ParsePatternType(name string) (PatternType, error) {
+	for patternType, typeName := range patternTypeNames {
+		if strings.EqualFold(name, typeName) {
+			return patternType, nil
+		}
+	}
+	return 0, fmt.Errorf("invalid pattern type: %s", name)
+}
+
 // Condition defines additional requirements for a tag
 type Condition struct {
 	Type  ConditionType
@@ -63,11 +98,80 @@ const (
 	CostCondition  ConditionType = "COST"
 	PowerCondition ConditionType = "POWER"
 	ComboCondition ConditionType = "COMBO"
+	AnyCondition   ConditionType = "ANY"
 )
 
+// Condition values by type:
+//   IS_TYPE      card.CardType or string, or a slice of either (any)
+//   HAS_KEYWORD  string, found in the card's keywords or as a word in its effect
+//   COST         int, or map[string]int with "min" and/or "max"
+//   POWER        StatPredicate or []StatPredicate (all)
+//   COMBO        string or []string naming combo tags (any)
+//   ANY          []Condition (any)
+
+// Stat is a numeric card attribute a StatPredicate compares
+type Stat string
+
+const (
+	StatAttack  Stat = "attack"
+	StatDefense Stat = "defense"
+	StatTotal   Stat = "total" // attack + defense
+	StatCost    Stat = "cost"
+)
+
+// StatPredicate compares a card stat against a value, e.g. attack >= 4.
+// Attack, defense and total predicates only hold for creatures.
+type StatPredicate struct {
+	Stat  Stat   `yaml:"stat" json:"stat"`
+	Op    string `yaml:"op" json:"op"`
+	Value int    `yaml:"value" json:"value"`
+}
+
+// Compare applies the predicate's operator to a stat value
+func (p StatPredicate) Compare(actual int) (bool, error) {
+	switch p.Op {
+	case "=", "==":
+		return actual == p.Value, nil
+	case "!=":
+		return actual != p.Value, nil
+	case "<":
+		return actual < p.Value, nil
+	case "<=":
+		return actual <= p.Value, nil
+	case ">":
+		return actual > p.Value, nil
+	case ">=":
+		return actual >= p.Value, nil
+	default:
+		return false, fmt.Errorf("invalid comparison operator: %s", p.Op)
+	}
+}
+
+// Validate checks the predicate's stat and operator
+func (p StatPredicate) Validate() error {
+	switch p.Stat {
+	case StatAttack, StatDefense, StatTotal, StatCost:
+	default:
+		return fmt.Errorf("invalid stat: %s", p.Stat)
+	}
+	_, err := p.Compare(0)
+	return err
+}
+
 // Tagger defines the interface for tag generation
 type Tagger interface {
 	GenerateTags(card interface{}) ([]Tag, error)
 	AddManualTag(cardID string, tag Tag) error
 	ValidateTags(tags []Tag) error
 }
+
+// EffectAnalysis is an effect analyzer's reading of a card's effect text
+type EffectAnalysis struct {
+	Summary string `json:"summary"`
+	Tags    []Tag  `json:"tags"`
+}
+
+// EffectAnalyzer suggests tags and a short summary for effect text, e.g. with a language model
+type EffectAnalyzer interface {
+	AnalyzeEffect(ctx context.Context, effect string) (*EffectAnalysis, error)
+}
@@ -0,0 +1,244 @@
+package types
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TaxonomyEntry places a tag in the taxonomy
+type TaxonomyEntry struct {
+	Name        string      `yaml:"name" json:"name"`
+	Category    TagCategory `yaml:"category" json:"category"`
+	Parent      string      `yaml:"parent" json:"parent,omitempty"`
+	Synonyms    []string    `yaml:"synonyms" json:"synonyms,omitempty"`
+	Description string      `yaml:"description" json:"description,omitempty"`
+}
+
+// TaxonomyNode is a tag with its child tags, for displaying grouped tag trees
+type TaxonomyNode struct {
+	TaxonomyEntry
+	Children []TaxonomyNode `json:"children,omitempty"`
+}
+
+// Taxonomy relates tags through parents and synonyms, so that a query for
+// REMOVAL also matches DESTROY and EXILE
+type Taxonomy struct {
+	entries  map[string]TaxonomyEntry
+	synonyms map[string]string   // synonym -> canonical name
+	children map[string][]string // parent -> sorted child names
+}
+
+// NewTaxonomy builds a taxonomy, rejecting unknown parents, cycles and ambiguous synonyms
+func NewTaxonomy(entries []TaxonomyEntry) (*Taxonomy, error) {
+	t := &Taxonomy{
+		entries:  make(map[string]TaxonomyEntry, len(entries)),
+		synonyms: make(map[string]string),
+		children: make(map[string][]string),
+	}
+
+	validator := NewTagValidator()
+	for _, entry := range entries {
+		entry.Name = normalizeTagName(entry.Name)
+		entry.Parent = normalizeTagName(entry.Parent)
+		entry.Category = TagCategory(normalizeTagName(string(entry.Category)))
+		if err := validator.ValidateTag(Tag{Name: entry.Name, Category: entry.Category}); err != nil {
+			return nil, fmt.Errorf("invalid taxonomy entry %q: %w", entry.Name, err)
+		}
+		if _, exists := t.entries[entry.Name]; exists {
+			return nil, fmt.Errorf("duplicate taxonomy entry %s", entry.Name)
+		}
+		synonyms := make([]string, 0, len(entry.Synonyms))
+		for _, synonym := range entry.Synonyms {
+			synonyms = append(synonyms, normalizeTagName(synonym))
+		}
+		entry.Synonyms = synonyms
+		t.entries[entry.Name] = entry
+	}
+
+	for name, entry := range t.entries {
+		for _, synonym := range entry.Synonyms {
+			if synonym == "" {
+				return nil, fmt.Errorf("tag %s has an empty synonym", name)
+			}
+			if _, exists := t.entries[synonym]; exists {
+				return nil, fmt.Errorf("synonym %s of %s is also a tag", synonym, name)
+			}
+			if other, exists := t.synonyms[synonym]; exists && other != name {
+				return nil, fmt.Errorf("synonym %s is used by both %s and %s", synonym, other, name)
+			}
+			t.synonyms[synonym] = name
+		}
+
+		if entry.Parent == "" {
+			continue
+		}
+		if _, exists := t.entries[entry.Parent]; !exists {
+			return nil, fmt.Errorf("tag %s has unknown parent %s", name, entry.Parent)
+		}
+		t.children[entry.Parent] = append(t.children[entry.Parent], name)
+	}
+
+	for name := range t.entries {
+		if err := t.checkCycle(name); err != nil {
+			return nil, err
+		}
+	}
+	for parent := range t.children {
+		sort.Strings(t.children[parent])
+	}
+	return t, nil
+}
+
+// checkCycle walks up from a tag and fails if it reaches the tag again
+func (t *Taxonomy) checkCycle(name string) error {
+	seen := map[string]bool{name: true}
+	for parent := t.entries[name].Parent; parent != ""; parent = t.entries[parent].Parent {
+		if seen[parent] {
+			return fmt.Errorf("tag %s is its own ancestor", name)
+		}
+		seen[parent] = true
+	}
+	return nil
+}
+
+// normalizeTagName trims and upper-cases a tag name
+func normalizeTagName(name string) string {
+	return strings.ToUpper(strings.TrimSpace(name))
+}
+
+// Canonical resolves a tag or synonym to its tag name; unknown names are returned trimmed
+func (t *Taxonomy) Canonical(name string) string {
+	key := normalizeTagName(name)
+	if canonical, ok := t.synonyms[key]; ok {
+		return canonical
+	}
+	if _, ok := t.entries[key]; ok {
+		return key
+	}
+	return strings.TrimSpace(name)
+}
+
+// Lookup returns the taxonomy entry of a tag or synonym
+func (t *Taxonomy) Lookup(name string) (TaxonomyEntry, bool) {
+	entry, ok := t.entries[t.Canonical(name)]
+	return entry, ok
+}
+
+// Ancestors returns a tag's parent, grandparent and so on
+func (t *Taxonomy) Ancestors(name string) []string {
+	var ancestors []string
+	for parent := t.entries[t.Canonical(name)].Parent; parent != ""; parent = t.entries[parent].Parent {
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// Descendants returns the canonical tag followed by all of its descendants, depth first
+func (t *Taxonomy) Descendants(name string) []string {
+	name = t.Canonical(name)
+	result := []string{name}
+	for _, child := range t.children[name] {
+		result = append(result, t.Descendants(child)...)
+	}
+	return result
+}
+
+// ExpandQuery resolves synonyms in the query and makes each tag also match its descendants
+func (t *Taxonomy) ExpandQuery(q TagQuery) TagQuery {
+	expanded := TagQuery{Source: q.Source, Expand: make(map[string][]string)}
+	canonical := func(names []string) []string {
+		result := make([]string, 0, len(names))
+		for _, name := range names {
+			name = t.Canonical(name)
+			result = append(result, name)
+			if descendants := t.Descendants(name); len(descendants) > 1 {
+				expanded.Expand[name] = descendants[1:]
+			}
+		}
+		return result
+	}
+
+	expanded.All = canonical(q.All)
+	expanded.Any = canonical(q.Any)
+	expanded.None = canonical(q.None)
+	return expanded
+}
+
+// Tree returns the root tags with their descendants, ordered by category, then name
+func (t *Taxonomy) Tree() []TaxonomyNode {
+	var roots []string
+	for name, entry := range t.entries {
+		if entry.Parent == "" {
+			roots = append(roots, name)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		a, b := t.entries[roots[i]], t.entries[roots[j]]
+		if a.Category != b.Category {
+			return a.Category < b.Category
+		}
+		return a.Name < b.Name
+	})
+
+	nodes := make([]TaxonomyNode, 0, len(roots))
+	for _, root := range roots {
+		nodes = append(nodes, t.node(root))
+	}
+	return nodes
+}
+
+// node builds the subtree rooted at a tag
+func (t *Taxonomy) node(name string) TaxonomyNode {
+	n := TaxonomyNode{TaxonomyEntry: t.entries[name]}
+	for _, child := range t.children[name] {
+		n.Children = append(n.Children, t.node(child))
+	}
+	return n
+}
+
+// DefaultTaxonomy relates the tags produced by the built-in rules and config/tag_rules
+func DefaultTaxonomy() *Taxonomy {
+	t, err := NewTaxonomy([]TaxonomyEntry{
+		{Name: "REMOVAL", Category: TagStrategy, Description: "Deals with opposing threats"},
+		{Name: "DESTROY", Category: TagStrategy, Parent: "REMOVAL", Synonyms: []string{"DESTRUCTION"}, Description: "Destroys a card"},
+		{Name: "EXILE", Category: TagStrategy, Parent: "REMOVAL", Description: "Exiles a card"},
+		{Name: "DIRECT_DAMAGE", Category: TagMechanic, Parent: "REMOVAL", Synonyms: []string{"BURN"}, Description: "Deals damage directly"},
+		{Name: "NEGATE", Category: TagStrategy, Parent: "REMOVAL", Synonyms: []string{"COUNTER", "COUNTERSPELL"}, Description: "Negates a card being played"},
+
+		{Name: "CARD_ADVANTAGE", Category: TagStrategy, Description: "Provides card advantage"},
+		{Name: "CARD_DRAW", Category: TagMechanic, Parent: "CARD_ADVANTAGE", Synonyms: []string{"DRAW"}, Description: "Draws cards"},
+		{Name: "GUIDE", Category: TagMechanic, Parent: "CARD_ADVANTAGE", Synonyms: []string{"SCRY"}, Description: "Looks at and arranges the top of the deck"},
+		{Name: "TUTOR", Category: TagMechanic, Parent: "CARD_ADVANTAGE", Synonyms: []string{"SEARCH"}, Description: "Searches the deck for a card"},
+
+		{Name: "TOKEN_GENERATOR", Category: TagMechanic, Synonyms: []string{"TOKEN_CREATOR", "TOKENS"}, Description: "Creates one or more tokens"},
+		{Name: "GRAVEYARD_INTERACTION", Category: TagMechanic, Synonyms: []string{"GRAVEYARD"}, Description: "Interacts with the graveyard"},
+		{Name: "LIFEGAIN", Category: TagMechanic, Synonyms: []string{"LIFE_GAIN"}, Description: "Gains life"},
+		{Name: "SACRIFICE", Category: TagMechanic, Description: "Sacrifices permanents"},
+
+		{Name: "COMBO_POTENTIAL", Category: TagCombo, Description: "Pairs a repeatable trigger with an action"},
+		{Name: "INFINITE_COMBO", Category: TagCombo, Parent: "COMBO_POTENTIAL", Description: "Can loop without limit"},
+		{Name: "MANA_COMBO", Category: TagCombo, Parent: "COMBO_POTENTIAL", Description: "Multiplies mana"},
+		{Name: "TOKEN_COMBO", Category: TagCombo, Parent: "COMBO_POTENTIAL", Description: "Multiplies tokens"},
+		{Name: "RESURRECTION_LOOP", Category: TagCombo, Parent: "COMBO_POTENTIAL", Description: "Repeatedly resurrects creatures"},
+		{Name: "TOKEN_DOUBLING", Category: TagCombo, Parent: "TOKEN_COMBO", Description: "Doubles tokens created"},
+
+		{Name: "AGGRO", Category: TagArchetype, Description: "Supports aggressive strategies"},
+		{Name: "CONTROL", Category: TagArchetype, Description: "Supports control strategies"},
+
+		{Name: "TRIGGERED", Category: TagTiming, Synonyms: []string{"TRIGGER"}, Description: "Has triggered abilities"},
+		{Name: "INSTANT_SPEED", Category: TagTiming, Synonyms: []string{"FLASH"}, Description: "Can be played at instant speed"},
+
+		{Name: "LOW_COST", Category: TagCost, Description: "Costs 2 or less"},
+		{Name: "MID_COST", Category: TagCost, Description: "Costs 3 or 4"},
+		{Name: "HIGH_COST", Category: TagCost, Description: "Costs 5 or 6"},
+		{Name: "VERY_HIGH_COST", Category: TagCost, Description: "Costs 7 or more"},
+
+		{Name: "TRIBAL_LORD", Category: TagTribal, Description: "Boosts creatures of a tribe"},
+		{Name: "TRIBAL_SYNERGY", Category: TagTribal, Description: "Rewards playing a tribe"},
+	})
+	if err != nil {
+		panic(fmt.Sprintf("invalid default taxonomy: %v", err))
+	}
+	return t
+}
@@ -0,0 +1,136 @@
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNewTaxonomy(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []TaxonomyEntry
+		wantErr string
+	}{
+		{"Valid", []TaxonomyEntry{{Name: "REMOVAL", Category: TagStrategy}, {Name: "EXILE", Category: TagStrategy, Parent: "REMOVAL"}}, ""},
+		{"Invalid category", []TaxonomyEntry{{Name: "REMOVAL", Category: "BOGUS"}}, "invalid tag category"},
+		{"Duplicate", []TaxonomyEntry{{Name: "REMOVAL", Category: TagStrategy}, {Name: "removal", Category: TagStrategy}}, "duplicate"},
+		{"Unknown parent", []TaxonomyEntry{{Name: "EXILE", Category: TagStrategy, Parent: "REMOVAL"}}, "unknown parent"},
+		{"Synonym is a tag", []TaxonomyEntry{{Name: "REMOVAL", Category: TagStrategy, Synonyms: []string{"EXILE"}}, {Name: "EXILE", Category: TagStrategy}}, "also a tag"},
+		{"Shared synonym", []TaxonomyEntry{{Name: "A", Category: TagMechanic, Synonyms: []string{"X"}}, {Name: "B", Category: TagMechanic, Synonyms: []string{"X"}}}, "used by both"},
+		{"Cycle", []TaxonomyEntry{{Name: "A", Category: TagMechanic, Parent: "B"}, {Name: "B", Category: TagMechanic, Parent: "A"}}, "own ancestor"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewTaxonomy(tt.entries)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("NewTaxonomy() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("NewTaxonomy() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaxonomyLookups(t *testing.T) {
+	taxonomy := DefaultTaxonomy()
+
+	tests := []struct {
+		name string
+		got  string
+		want string
+	}{
+		{"Synonym", taxonomy.Canonical("burn"), "DIRECT_DAMAGE"},
+		{"Known tag", taxonomy.Canonical(" exile "), "EXILE"},
+		{"Unknown tag keeps its case", taxonomy.Canonical("Spell"), "Spell"},
+		{"Descendants", strings.Join(taxonomy.Descendants("REMOVAL"), ","), "REMOVAL,DESTROY,DIRECT_DAMAGE,EXILE,NEGATE"},
+		{"Nested descendants", strings.Join(taxonomy.Descendants("TOKEN_COMBO"), ","), "TOKEN_COMBO,TOKEN_DOUBLING"},
+		{"Ancestors", strings.Join(taxonomy.Ancestors("TOKEN_DOUBLING"), ","), "TOKEN_COMBO,COMBO_POTENTIAL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.want {
+				t.Errorf("got %q, want %q", tt.got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandQuery(t *testing.T) {
+	query := DefaultTaxonomy().ExpandQuery(TagQuery{All: []string{"removal"}, None: []string{"counterspell"}})
+
+	tests := []struct {
+		name  string
+		names []string
+		want  bool
+	}{
+		{"Parent tag", []string{"REMOVAL"}, true},
+		{"Descendant tag", []string{"EXILE", "CARD_DRAW"}, true},
+		{"Excluded through synonym", []string{"DESTROY", "NEGATE"}, false},
+		{"Unrelated tag", []string{"CARD_DRAW"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names := make(map[string]bool)
+			for _, name := range tt.names {
+				names[name] = true
+			}
+			if got := query.Matches(names); got != tt.want {
+				t.Errorf("Matches(%v) = %v, want %v", tt.names, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTagWithTaxonomy(t *testing.T) {
+	validator := NewTagValidator().WithTaxonomy(DefaultTaxonomy())
+
+	tests := []struct {
+		name    string
+		tag     Tag
+		wantErr string
+	}{
+		{"Known tag", Tag{Name: "EXILE", Category: TagStrategy}, ""},
+		{"Unknown tag", Tag{Name: "Spell", Category: TagMechanic}, ""},
+		{"Synonym", Tag{Name: "BURN", Category: TagMechanic}, "synonym of DIRECT_DAMAGE"},
+		{"Wrong category", Tag{Name: "EXILE", Category: TagMechanic}, "belongs to category STRATEGY"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validator.ValidateTag(tt.tag)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateTag() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateTag() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestTaxonomyTree(t *testing.T) {
+	tree := DefaultTaxonomy().Tree()
+	for i := 1; i < len(tree); i++ {
+		if tree[i-1].Category > tree[i].Category {
+			t.Fatalf("roots not ordered by category: %s before %s", tree[i-1].Name, tree[i].Name)
+		}
+	}
+	for _, root := range tree {
+		if root.Name == "REMOVAL" && len(root.Children) != 4 {
+			t.Errorf("REMOVAL has %d children, want 4", len(root.Children))
+		}
+		if root.Parent != "" {
+			t.Errorf("root %s has parent %s", root.Name, root.Parent)
+		}
+	}
+}
@@ -7,6 +7,7 @@ import (
 // TagValidator handles validation of tags
 type TagValidator struct {
 	validCategories map[TagCategory]bool
+	taxonomy        *Taxonomy
 }
 
 // NewTagValidator creates a new tag validator
@@ -25,6 +26,12 @@ func NewTagValidator() *TagValidator {
 	}
 }
 
+// WithTaxonomy makes the validator reject synonyms and category mismatches for tags in the taxonomy
+func (tv *TagValidator) WithTaxonomy(t *Taxonomy) *TagValidator {
+	tv.taxonomy = t
+	return tv
+}
+
 // ValidateTag checks if a single tag is valid
 func (tv *TagValidator) ValidateTag(tag Tag) error {
 	if tag.Name == "" {
@@ -35,6 +42,17 @@ func (tv *TagValidator) ValidateTag(tag Tag) error {
 		return fmt.Errorf("invalid tag category: %s", tag.Category)
 	}
 
+	if tv.taxonomy != nil {
+		if entry, ok := tv.taxonomy.Lookup(tag.Name); ok {
+			if entry.Name != normalizeTagName(tag.Name) {
+				return fmt.Errorf("tag %s is a synonym of %s", tag.Name, entry.Name)
+			}
+			if entry.Category != tag.Category {
+				return fmt.Errorf("tag %s belongs to category %s, not %s", entry.Name, entry.Category, tag.Category)
+			}
+		}
+	}
+
 	return nil
 }
 
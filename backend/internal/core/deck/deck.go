@@ -0,0 +1,137 @@
+// Package deck defines decks built from cards and the rules they are constructed under
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Entry is a card in a deck and how many copies are included
+type Entry struct {
+	CardID string `json:"card_id" yaml:"card_id"`
+	Count  int    `json:"count" yaml:"count"`
+}
+
+// Deck is a named list of cards with an optional sideboard
+type Deck struct {
+	ID          string    `json:"id,omitempty" yaml:"id,omitempty"`
+	Name        string    `json:"name" yaml:"name"`
+	Description string    `json:"description,omitempty" yaml:"description,omitempty"`
+	Cards       []Entry   `json:"cards" yaml:"cards"`
+	Sideboard   []Entry   `json:"sideboard,omitempty" yaml:"sideboard,omitempty"`
+	CreatedAt   time.Time `json:"created_at" yaml:"created_at,omitempty"`
+	UpdatedAt   time.Time `json:"updated_at" yaml:"updated_at,omitempty"`
+}
+
+// New creates an empty deck
+func New(name string) *Deck {
+	now := time.Now()
+	return &Deck{
+		Name:      name,
+		Cards:     make([]Entry, 0),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+// Size returns the number of cards in the main deck
+func (d *Deck) Size() int {
+	return countEntries(d.Cards)
+}
+
+// SideboardSize returns the number of cards in the sideboard
+func (d *Deck) SideboardSize() int {
+	return countEntries(d.Sideboard)
+}
+
+// AddCard adds copies of a card to the main deck
+func (d *Deck) AddCard(cardID string, count int) {
+	d.Cards = addEntry(d.Cards, cardID, count)
+	d.UpdatedAt = time.Now()
+}
+
+// AddSideboardCard adds copies of a card to the sideboard
+func (d *Deck) AddSideboardCard(cardID string, count int) {
+	d.Sideboard = addEntry(d.Sideboard, cardID, count)
+	d.UpdatedAt = time.Now()
+}
+
+// RemoveCard removes copies of a card from the main deck, dropping the entry when none remain
+func (d *Deck) RemoveCard(cardID string, count int) {
+	d.Cards = removeEntry(d.Cards, cardID, count)
+	d.UpdatedAt = time.Now()
+}
+
+// Copies returns the number of copies of a card across the main deck and sideboard
+func (d *Deck) Copies() map[string]int {
+	copies := make(map[string]int)
+	for _, entry := range d.Cards {
+		copies[entry.CardID] += entry.Count
+	}
+	for _, entry := range d.Sideboard {
+		copies[entry.CardID] += entry.Count
+	}
+	return copies
+}
+
+// Load reads a deck from a YAML or JSON file
+func Load(path string) (*Deck, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read deck file: %w", err)
+	}
+
+	d := &Deck{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, d)
+	default:
+		err = yaml.Unmarshal(data, d)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse deck file %s: %w", path, err)
+	}
+
+	if d.Name == "" {
+		d.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return d, nil
+}
+
+func countEntries(entries []Entry) int {
+	total := 0
+	for _, entry := range entries {
+		total += entry.Count
+	}
+	return total
+}
+
+func addEntry(entries []Entry, cardID string, count int) []Entry {
+	for i := range entries {
+		if entries[i].CardID == cardID {
+			entries[i].Count += count
+			return entries
+		}
+	}
+	return append(entries, Entry{CardID: cardID, Count: count})
+}
+
+func removeEntry(entries []Entry, cardID string, count int) []Entry {
+	for i := range entries {
+		if entries[i].CardID != cardID {
+			continue
+		}
+		entries[i].Count -= count
+		if entries[i].Count <= 0 {
+			return append(entries[:i], entries[i+1:]...)
+		}
+		return entries
+	}
+	return entries
+}
@@ -0,0 +1,183 @@
+package deck
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+// mapSource is a CardSource backed by a map
+type mapSource map[string]card.Card
+
+func (m mapSource) Load(id string) (card.Card, error) {
+	c, ok := m[id]
+	if !ok {
+		return nil, fmt.Errorf("card not found: %s", id)
+	}
+	return c, nil
+}
+
+func testSource() mapSource {
+	creature := func(name string, trait card.Trait) card.Card {
+		return card.NewCreatureFromDTO(&card.CardDTO{
+			Type: card.TypeCreature, Name: name, Cost: 2, Effect: "A creature.",
+			Attack: 2, Defense: 2, Trait: string(trait),
+		})
+	}
+	return mapSource{
+		"Creature-Squire":    creature("Squire", card.TraitWarrior),
+		"Creature-Knight":    creature("Knight", card.TraitWarrior),
+		"Creature-Wyrm":      creature("Wyrm", card.TraitDragon),
+		"Creature-Arthur":    creature("Arthur", card.TraitLegendary),
+		"Creature-Guinevere": creature("Guinevere", card.TraitLegendary),
+	}
+}
+
+func TestValidate(t *testing.T) {
+	rules := Rules{
+		MinSize:            6,
+		MaxSize:            8,
+		MaxSideboardSize:   2,
+		MaxCopies:          3,
+		MaxLegendaryCopies: 1,
+		MaxLegendaryTotal:  1,
+		Unlimited:          []string{"squire"},
+	}
+
+	tests := []struct {
+		name      string
+		cards     []Entry
+		sideboard []Entry
+		wantRules []string
+	}{
+		{
+			name:      "Valid deck",
+			cards:     []Entry{{"Creature-Squire", 3}, {"Creature-Knight", 3}},
+			wantRules: nil,
+		},
+		{
+			name:      "Too small",
+			cards:     []Entry{{"Creature-Knight", 3}},
+			wantRules: []string{RuleMinSize},
+		},
+		{
+			name:      "Too large with oversized sideboard",
+			cards:     []Entry{{"Creature-Squire", 6}, {"Creature-Knight", 3}},
+			sideboard: []Entry{{"Creature-Wyrm", 3}},
+			wantRules: []string{RuleMaxSize, RuleSideboardSize},
+		},
+		{
+			name:      "Copy limit counts the sideboard",
+			cards:     []Entry{{"Creature-Squire", 3}, {"Creature-Knight", 3}},
+			sideboard: []Entry{{"Creature-Knight", 1}},
+			wantRules: []string{RuleCopyLimit},
+		},
+		{
+			name:      "Unlimited card is exempt",
+			cards:     []Entry{{"Creature-Squire", 7}},
+			wantRules: nil,
+		},
+		{
+			name:      "Legendary limits",
+			cards:     []Entry{{"Creature-Squire", 4}, {"Creature-Arthur", 2}, {"Creature-Guinevere", 1}},
+			wantRules: []string{RuleLegendaryCopy, RuleLegendaryTotal},
+		},
+		{
+			name:      "Legendary total ignores the sideboard",
+			cards:     []Entry{{"Creature-Squire", 5}, {"Creature-Arthur", 1}},
+			sideboard: []Entry{{"Creature-Guinevere", 1}},
+			wantRules: nil,
+		},
+		{
+			name:      "Legendary copy limit counts the sideboard",
+			cards:     []Entry{{"Creature-Squire", 5}, {"Creature-Arthur", 1}},
+			sideboard: []Entry{{"Creature-Arthur", 1}},
+			wantRules: []string{RuleLegendaryCopy},
+		},
+		{
+			name:      "Unknown card and bad count",
+			cards:     []Entry{{"Creature-Squire", 6}, {"Creature-Missing", 1}, {"Creature-Knight", 0}},
+			wantRules: []string{RuleEntry, RuleUnknownCard},
+		},
+	}
+
+	validator := NewValidator(testSource(), rules)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := New("Test Deck")
+			d.Cards = tt.cards
+			d.Sideboard = tt.sideboard
+
+			report := validator.Validate(d)
+			if len(report.Violations) != len(tt.wantRules) {
+				t.Fatalf("got violations %+v, want rules %v", report.Violations, tt.wantRules)
+			}
+			for i, violation := range report.Violations {
+				if violation.Rule != tt.wantRules[i] {
+					t.Errorf("violation %d rule = %s, want %s", i, violation.Rule, tt.wantRules[i])
+				}
+			}
+			if report.Valid != (len(tt.wantRules) == 0) {
+				t.Errorf("Valid = %v", report.Valid)
+			}
+		})
+	}
+}
+
+func TestAddRemoveCard(t *testing.T) {
+	d := New("Test Deck")
+	d.AddCard("Creature-Squire", 2)
+	d.AddCard("Creature-Squire", 1)
+	d.AddSideboardCard("Creature-Knight", 1)
+
+	if d.Size() != 3 || d.SideboardSize() != 1 || len(d.Cards) != 1 {
+		t.Fatalf("unexpected deck after adding: %+v", d)
+	}
+
+	d.RemoveCard("Creature-Squire", 3)
+	if d.Size() != 0 || len(d.Cards) != 0 {
+		t.Errorf("expected empty main deck, got %+v", d.Cards)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	tests := []struct {
+		file    string
+		content string
+	}{
+		{"knights.yaml", "cards:\n  - card_id: Creature-Knight\n    count: 3\nsideboard:\n  - card_id: Creature-Wyrm\n    count: 1\n"},
+		{"knights.json", `{"cards": [{"card_id": "Creature-Knight", "count": 3}], "sideboard": [{"card_id": "Creature-Wyrm", "count": 1}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			path := filepath.Join(dir, tt.file)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			d, err := Load(path)
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			if d.Name != "knights" || d.Size() != 3 || d.SideboardSize() != 1 {
+				t.Errorf("unexpected deck: %+v", d)
+			}
+		})
+	}
+
+	t.Run("Invalid rules", func(t *testing.T) {
+		path := filepath.Join(dir, "rules.yaml")
+		if err := os.WriteFile(path, []byte("min_size: 70\nmax_size: 60\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadRules(path); err == nil {
+			t.Error("expected error when min size exceeds max size")
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package deck
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Rules are the deck construction limits a deck is validated against.
+// A zero limit disables that check.
+type Rules struct {
+	// MinSize and MaxSize bound the number of cards in the main deck
+	MinSize int `json:"min_size" yaml:"min_size"`
+	MaxSize int `json:"max_size" yaml:"max_size"`
+
+	// MaxSideboardSize bounds the number of cards in the sideboard
+	MaxSideboardSize int `json:"max_sideboard_size" yaml:"max_sideboard_size"`
+
+	// MaxCopies limits copies of any one card across the main deck and sideboard
+	MaxCopies int `json:"max_copies" yaml:"max_copies"`
+
+	// MaxLegendaryCopies limits copies of any one Legendary card
+	MaxLegendaryCopies int `json:"max_legendary_copies" yaml:"max_legendary_copies"`
+
+	// MaxLegendaryTotal limits the total number of Legendary cards in the main deck; the sideboard is not counted
+	MaxLegendaryTotal int `json:"max_legendary_total" yaml:"max_legendary_total"`
+
+	// Unlimited lists card names exempt from MaxCopies
+	Unlimited []string `json:"unlimited" yaml:"unlimited"`
+}
+
+// DefaultRules returns the standard constructed deck rules
+func DefaultRules() Rules {
+	return Rules{
+		MinSize:            40,
+		MaxSize:            60,
+		MaxSideboardSize:   10,
+		MaxCopies:          3,
+		MaxLegendaryCopies: 1,
+		MaxLegendaryTotal:  0,
+	}
+}
+
+// LoadRules reads deck construction rules from a YAML or JSON file.
+// Fields missing from the file keep their DefaultRules values.
+func LoadRules(path string) (Rules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Rules{}, fmt.Errorf("failed to read deck rules: %w", err)
+	}
+
+	rules := DefaultRules()
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &rules)
+	default:
+		err = yaml.Unmarshal(data, &rules)
+	}
+	if err != nil {
+		return Rules{}, fmt.Errorf("failed to parse deck rules %s: %w", path, err)
+	}
+
+	if err := rules.Validate(); err != nil {
+		return Rules{}, err
+	}
+	return rules, nil
+}
+
+// Validate checks that the rules are self-consistent
+func (r Rules) Validate() error {
+	if r.MinSize < 0 || r.MaxSize < 0 || r.MaxSideboardSize < 0 || r.MaxCopies < 0 ||
+		r.MaxLegendaryCopies < 0 || r.MaxLegendaryTotal < 0 {
+		return fmt.Errorf("deck rule limits cannot be negative")
+	}
+	if r.MaxSize > 0 && r.MinSize > r.MaxSize {
+		return fmt.Errorf("min deck size %d exceeds max deck size %d", r.MinSize, r.MaxSize)
+	}
+	return nil
+}
+
+// isUnlimited reports whether a card name is exempt from the copy limit
+func (r Rules) isUnlimited(name string) bool {
+	for _, unlimited := range r.Unlimited {
+		if strings.EqualFold(unlimited, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,141 @@
+package deck
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+// Violation rule names
+const (
+	RuleName           = "name"
+	RuleEntry          = "entry"
+	RuleUnknownCard    = "unknown-card"
+	RuleMinSize        = "min-size"
+	RuleMaxSize        = "max-size"
+	RuleSideboardSize  = "sideboard-size"
+	RuleCopyLimit      = "copy-limit"
+	RuleLegendaryCopy  = "legendary-copy-limit"
+	RuleLegendaryTotal = "legendary-total"
+)
+
+// CardSource resolves card IDs to cards; satisfied by store.Store
+type CardSource interface {
+	Load(id string) (card.Card, error)
+}
+
+// Violation is a single way a deck breaks the construction rules
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+	CardID  string `json:"card_id,omitempty"`
+}
+
+// Report is the result of validating a deck
+type Report struct {
+	Deck          string      `json:"deck"`
+	Size          int         `json:"size"`
+	SideboardSize int         `json:"sideboard_size"`
+	Valid         bool        `json:"valid"`
+	Violations    []Violation `json:"violations"`
+}
+
+// Validator checks decks against construction rules using a card source
+type Validator struct {
+	cards CardSource
+	rules Rules
+}
+
+// NewValidator creates a deck validator
+func NewValidator(cards CardSource, rules Rules) *Validator {
+	return &Validator{
+		cards: cards,
+		rules: rules,
+	}
+}
+
+// Validate reports every rule the deck violates
+func (v *Validator) Validate(d *Deck) *Report {
+	report := &Report{
+		Deck:          d.Name,
+		Size:          d.Size(),
+		SideboardSize: d.SideboardSize(),
+		Violations:    make([]Violation, 0),
+	}
+	add := func(rule, cardID, format string, args ...interface{}) {
+		report.Violations = append(report.Violations, Violation{
+			Rule:    rule,
+			Message: fmt.Sprintf(format, args...),
+			CardID:  cardID,
+		})
+	}
+
+	if d.Name == "" {
+		add(RuleName, "", "deck name cannot be empty")
+	}
+
+	for _, entry := range append(append([]Entry{}, d.Cards...), d.Sideboard...) {
+		if entry.CardID == "" {
+			add(RuleEntry, "", "deck entry is missing a card ID")
+		}
+		if entry.Count <= 0 {
+			add(RuleEntry, entry.CardID, "card %s has invalid count %d", entry.CardID, entry.Count)
+		}
+	}
+
+	if v.rules.MinSize > 0 && report.Size < v.rules.MinSize {
+		add(RuleMinSize, "", "deck has %d cards, minimum is %d", report.Size, v.rules.MinSize)
+	}
+	if v.rules.MaxSize > 0 && report.Size > v.rules.MaxSize {
+		add(RuleMaxSize, "", "deck has %d cards, maximum is %d", report.Size, v.rules.MaxSize)
+	}
+	if v.rules.MaxSideboardSize > 0 && report.SideboardSize > v.rules.MaxSideboardSize {
+		add(RuleSideboardSize, "", "sideboard has %d cards, maximum is %d", report.SideboardSize, v.rules.MaxSideboardSize)
+	}
+
+	copies := d.Copies()
+	mainCopies := make(map[string]int, len(d.Cards))
+	for _, entry := range d.Cards {
+		mainCopies[entry.CardID] += entry.Count
+	}
+	ids := make([]string, 0, len(copies))
+	for id := range copies {
+		if id != "" {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+
+	legendaryTotal := 0
+	for _, id := range ids {
+		count := copies[id]
+
+		c, err := v.cards.Load(id)
+		if err != nil {
+			add(RuleUnknownCard, id, "card %s not found", id)
+			continue
+		}
+		dto := c.ToDTO()
+
+		if card.Trait(dto.Trait) == card.TraitLegendary {
+			// Copy limits span the sideboard, the Legendary total only counts the main deck
+			legendaryTotal += mainCopies[id]
+			if v.rules.MaxLegendaryCopies > 0 && count > v.rules.MaxLegendaryCopies {
+				add(RuleLegendaryCopy, id, "%s is Legendary: %d copies, maximum is %d", dto.Name, count, v.rules.MaxLegendaryCopies)
+				continue
+			}
+		}
+
+		if v.rules.MaxCopies > 0 && count > v.rules.MaxCopies && !v.rules.isUnlimited(dto.Name) {
+			add(RuleCopyLimit, id, "%s: %d copies, maximum is %d", dto.Name, count, v.rules.MaxCopies)
+		}
+	}
+
+	if v.rules.MaxLegendaryTotal > 0 && legendaryTotal > v.rules.MaxLegendaryTotal {
+		add(RuleLegendaryTotal, "", "deck has %d Legendary cards, maximum is %d", legendaryTotal, v.rules.MaxLegendaryTotal)
+	}
+
+	report.Valid = len(report.Violations) == 0
+	return report
+}
@@ -0,0 +1,209 @@
+package art
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/jpeg" // register JPEG decoding
+	_ "image/png"  // register PNG decoding
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	_ "golang.org/x/image/webp" // register WebP decoding
+)
+
+// MetadataArtKey is the card metadata key holding a path to the card's art
+const MetadataArtKey = "art"
+
+// ErrArtNotFound is returned by an ArtSource that has no art for a card
+var ErrArtNotFound = errors.New("art not found")
+
+// ErrUnsafePath is returned for a metadata path that is absolute or leaves its directory
+var ErrUnsafePath = errors.New("path must stay inside its directory")
+
+// artExtensions lists the file extensions tried when looking up art by card name or ID
+var artExtensions = []string{".png", ".jpg", ".jpeg", ".webp"}
+
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// LocalSource resolves art from files on disk
+type LocalSource struct {
+	dir          string
+	maxImageSize int64
+}
+
+// NewLocalSource creates a source reading art from dir. A card's art is the file named
+// in its "art" metadata (relative to dir and confined to it), or else a PNG, JPEG or WebP
+// file in dir named after the card's ID or name, e.g. "mountain-bear.png".
+func NewLocalSource(dir string) *LocalSource {
+	return &LocalSource{dir: dir}
+}
+
+// WithMaxImageSize rejects art files larger than maxBytes; zero or less means no limit
+func (s *LocalSource) WithMaxImageSize(maxBytes int64) *LocalSource {
+	s.maxImageSize = maxBytes
+	return s
+}
+
+// GetArt implements ArtSource
+func (s *LocalSource) GetArt(data *card.CardDTO) (image.Image, error) {
+	path, err := s.resolve(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.maxImageSize > 0 {
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat art: %w", err)
+		}
+		if info.Size() > s.maxImageSize {
+			return nil, fmt.Errorf("art %s is %d bytes, over the %d byte limit", path, info.Size(), s.maxImageSize)
+		}
+	}
+	return decodeFile(path)
+}
+
+// Fingerprint returns a hash of the card's art file, or ErrArtNotFound when it has none
+func (s *LocalSource) Fingerprint(data *card.CardDTO) (string, error) {
+	path, err := s.resolve(data)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open art: %w", err)
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", fmt.Errorf("failed to read art: %w", err)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// resolve finds the art file of a card
+func (s *LocalSource) resolve(data *card.CardDTO) (string, error) {
+	if name := strings.TrimSpace(data.Metadata[MetadataArtKey]); name != "" {
+		path, err := LocalPath(s.dir, name)
+		if err != nil {
+			return "", fmt.Errorf("art for %s: %w", data.Name, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("art for %s: %w", data.Name, ErrArtNotFound)
+		}
+		return path, nil
+	}
+
+	if s.dir == "" {
+		return "", fmt.Errorf("art for %s: %w", data.Name, ErrArtNotFound)
+	}
+	for _, name := range []string{data.ID, Slug(data.Name)} {
+		if name == "" {
+			continue
+		}
+		for _, ext := range artExtensions {
+			path := filepath.Join(s.dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+	return "", fmt.Errorf("art for %s: %w", data.Name, ErrArtNotFound)
+}
+
+// LocalPath joins a path taken from card metadata to dir, rejecting absolute paths and
+// paths that leave dir, so metadata can only name files inside it
+func LocalPath(dir, name string) (string, error) {
+	if dir == "" || filepath.IsAbs(name) || !filepath.IsLocal(filepath.Clean(name)) {
+		return "", fmt.Errorf("%q: %w", name, ErrUnsafePath)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Slug converts a card name to the file name used for its art, e.g. "Mountain Bear" -> "mountain-bear"
+func Slug(name string) string {
+	return strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+}
+
+// decodeFile decodes a PNG, JPEG or WebP image
+func decodeFile(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open art: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode art %s: %w", path, err)
+	}
+	return img, nil
+}
+
+// localProcessor implements ArtProcessor with an ArtSource and a procedural fallback
+type localProcessor struct {
+	source     ArtSource
+	pipeline   *Pipeline
+	procedural bool
+}
+
+// NewLocalProcessor creates an art processor that reads art from the source and fits it with
+// the pipeline, or DefaultPipeline when nil. Cards without art get procedurally generated art
+// when procedural is set, and fail otherwise.
+func NewLocalProcessor(source ArtSource, pipeline *Pipeline, procedural bool) ArtProcessor {
+	if pipeline == nil {
+		pipeline = DefaultPipeline()
+	}
+	return &localProcessor{
+		source:     source,
+		pipeline:   pipeline,
+		procedural: procedural,
+	}
+}
+
+// ProcessArt implements ArtProcessor interface
+func (p *localProcessor) ProcessArt(data *card.CardDTO, bounds image.Rectangle) (image.Image, error) {
+	src, err := p.source.GetArt(data)
+	if errors.Is(err, ErrArtNotFound) && p.procedural {
+		return Procedural(data.Name, bounds), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	framing, err := FramingFromMetadata(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid art framing for %s: %w", data.Name, err)
+	}
+	return p.pipeline.Fit(src, bounds, framing), nil
+}
+
+// Fingerprint identifies the art ProcessArt produces for a card: the source art's hash and
+// the resampling algorithm, or a procedural marker for cards without art. It fails when
+// the source cannot fingerprint its art.
+func (p *localProcessor) Fingerprint(data *card.CardDTO) (string, error) {
+	fingerprinter, ok := p.source.(interface {
+		Fingerprint(data *card.CardDTO) (string, error)
+	})
+	if !ok {
+		return "", fmt.Errorf("art source %T cannot fingerprint art", p.source)
+	}
+
+	fingerprint, err := fingerprinter.Fingerprint(data)
+	if errors.Is(err, ErrArtNotFound) && p.procedural {
+		return "procedural", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return fingerprint + ":" + p.pipeline.Algorithm(), nil
+}
@@ -0,0 +1,179 @@
+package art
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+func writeImage(t *testing.T, path string, c color.Color) {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, 40, 30))
+	for y := 0; y < 30; y++ {
+		for x := 0; x < 40; x++ {
+			img.Set(x, y, c)
+		}
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if filepath.Ext(path) == ".jpg" {
+		err = jpeg.Encode(f, img, nil)
+	} else {
+		err = png.Encode(f, img)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLocalSource(t *testing.T) {
+	dir := t.TempDir()
+	red := color.RGBA{255, 0, 0, 255}
+	writeImage(t, filepath.Join(dir, "mountain-bear.png"), red)
+	writeImage(t, filepath.Join(dir, "Creature-Smite.jpg"), color.RGBA{0, 0, 255, 255})
+	writeImage(t, filepath.Join(dir, "custom.png"), color.RGBA{0, 255, 0, 255})
+
+	tests := []struct {
+		name    string
+		data    *card.CardDTO
+		wantErr error
+	}{
+		{"By name", &card.CardDTO{Name: "Mountain Bear"}, nil},
+		{"By ID", &card.CardDTO{ID: "Creature-Smite", Name: "Smite"}, nil},
+		{"By metadata", &card.CardDTO{Name: "Other", Metadata: map[string]string{"art": "custom.png"}}, nil},
+		{"Missing metadata file", &card.CardDTO{Name: "Mountain Bear", Metadata: map[string]string{"art": "gone.png"}}, ErrArtNotFound},
+		{"Metadata leaving the art directory", &card.CardDTO{Name: "Other", Metadata: map[string]string{"art": "../" + filepath.Base(dir) + "/custom.png"}}, ErrUnsafePath},
+		{"Absolute metadata path", &card.CardDTO{Name: "Other", Metadata: map[string]string{"art": filepath.Join(dir, "custom.png")}}, ErrUnsafePath},
+		{"System file", &card.CardDTO{Name: "Other", Metadata: map[string]string{"art": "/etc/passwd"}}, ErrUnsafePath},
+		{"Nested traversal", &card.CardDTO{Name: "Other", Metadata: map[string]string{"art": "sub/../../etc/passwd"}}, ErrUnsafePath},
+		{"No art", &card.CardDTO{Name: "Unknown"}, ErrArtNotFound},
+	}
+
+	source := NewLocalSource(dir)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, err := source.GetArt(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("GetArt() error = %v, want %v", err, tt.wantErr)
+			}
+			if err == nil && img.Bounds().Dx() != 40 {
+				t.Errorf("art width = %d, want 40", img.Bounds().Dx())
+			}
+		})
+	}
+}
+
+func TestLocalProcessor(t *testing.T) {
+	dir := t.TempDir()
+	writeImage(t, filepath.Join(dir, "mountain-bear.png"), color.RGBA{255, 0, 0, 255})
+	bounds := image.Rect(10, 20, 60, 60)
+
+	img, err := NewLocalProcessor(NewLocalSource(dir), nil, true).ProcessArt(&card.CardDTO{Name: "Mountain Bear"}, bounds)
+	if err != nil {
+		t.Fatalf("ProcessArt() error = %v", err)
+	}
+	if img.Bounds() != bounds {
+		t.Errorf("bounds = %v, want %v", img.Bounds(), bounds)
+	}
+	if r, _, _, _ := img.At(10, 20).RGBA(); r>>8 != 255 {
+		t.Errorf("art not copied to the bounds' origin")
+	}
+
+	if _, err := NewLocalProcessor(NewLocalSource(dir), nil, false).ProcessArt(&card.CardDTO{Name: "Unknown"}, bounds); !errors.Is(err, ErrArtNotFound) {
+		t.Errorf("ProcessArt() without fallback error = %v, want ErrArtNotFound", err)
+	}
+	if _, err := NewLocalProcessor(NewLocalSource(dir), nil, true).ProcessArt(&card.CardDTO{Name: "Unknown"}, bounds); err != nil {
+		t.Errorf("ProcessArt() with fallback error = %v", err)
+	}
+}
+
+func TestProcedural(t *testing.T) {
+	bounds := image.Rect(0, 0, 64, 48)
+	a := Procedural("Mountain Bear", bounds)
+	b := Procedural("Mountain Bear", bounds)
+	c := Procedural("Smite", bounds)
+
+	if string(a.Pix) != string(b.Pix) {
+		t.Error("same seed produced different art")
+	}
+	if string(a.Pix) == string(c.Pix) {
+		t.Error("different seeds produced identical art")
+	}
+	if a.At(0, 0) == a.At(63, 47) {
+		t.Error("art has no gradient")
+	}
+}
+
+func TestSlug(t *testing.T) {
+	tests := map[string]string{
+		"Mountain Bear":  "mountain-bear",
+		"No!":            "no",
+		"Win / Win":      "win-win",
+		"Gambler's Coin": "gambler-s-coin",
+	}
+	for name, want := range tests {
+		if got := Slug(name); got != want {
+			t.Errorf("Slug(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+func TestLocalSourceMaxImageSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "mountain-bear.png")
+	writeImage(t, path, color.RGBA{255, 0, 0, 255})
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := &card.CardDTO{Name: "Mountain Bear"}
+	if _, err := NewLocalSource(dir).WithMaxImageSize(info.Size()).GetArt(data); err != nil {
+		t.Errorf("GetArt() at the limit error = %v", err)
+	}
+	if _, err := NewLocalSource(dir).WithMaxImageSize(info.Size() - 1).GetArt(data); err == nil {
+		t.Error("GetArt() over the limit should fail")
+	}
+}
+
+func TestLocalProcessorFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	data := &card.CardDTO{Name: "Mountain Bear"}
+	fingerprint := func(procedural bool) (string, error) {
+		p := NewLocalProcessor(NewLocalSource(dir), nil, procedural).(*localProcessor)
+		return p.Fingerprint(data)
+	}
+
+	if got, err := fingerprint(true); err != nil || got != "procedural" {
+		t.Errorf("Fingerprint() without art = %q, %v, want procedural", got, err)
+	}
+	if _, err := fingerprint(false); !errors.Is(err, ErrArtNotFound) {
+		t.Errorf("Fingerprint() without art or fallback error = %v, want ErrArtNotFound", err)
+	}
+
+	path := filepath.Join(dir, "mountain-bear.png")
+	writeImage(t, path, color.RGBA{255, 0, 0, 255})
+	red, err := fingerprint(true)
+	if err != nil {
+		t.Fatalf("Fingerprint() error = %v", err)
+	}
+	if again, _ := fingerprint(true); again != red {
+		t.Errorf("Fingerprint() changed for the same art: %q, %q", red, again)
+	}
+
+	writeImage(t, path, color.RGBA{0, 0, 255, 255})
+	if blue, _ := fingerprint(true); blue == red {
+		t.Error("Fingerprint() did not change when the art file changed")
+	}
+}
@@ -0,0 +1,192 @@
+package art
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"golang.org/x/image/draw"
+)
+
+// Card metadata keys controlling how art is framed
+const (
+	MetadataFitKey   = "art_fit"   // cover or contain
+	MetadataFocusKey = "art_focus" // "x,y" focal point, each between 0 and 1
+	MetadataZoomKey  = "art_zoom"  // magnification around the focal point, 1 by default
+)
+
+// FitMode controls how art of a different aspect ratio fills its bounds
+type FitMode string
+
+const (
+	// FitCover fills the bounds, cropping the art around the focal point
+	FitCover FitMode = "cover"
+	// FitContain shows the whole art, leaving transparent bars
+	FitContain FitMode = "contain"
+)
+
+// Framing positions art within its bounds
+type Framing struct {
+	Fit    FitMode
+	FocusX float64 // focal point as a fraction of the art's width
+	FocusY float64 // focal point as a fraction of the art's height
+	Zoom   float64
+}
+
+// DefaultFraming covers the bounds centered on the art
+var DefaultFraming = Framing{Fit: FitCover, FocusX: 0.5, FocusY: 0.5, Zoom: 1}
+
+// FramingFromMetadata reads a card's framing, using DefaultFraming for unset keys
+func FramingFromMetadata(data *card.CardDTO) (Framing, error) {
+	framing := DefaultFraming
+	meta := data.Metadata
+
+	if fit := strings.ToLower(strings.TrimSpace(meta[MetadataFitKey])); fit != "" {
+		switch FitMode(fit) {
+		case FitCover, FitContain:
+			framing.Fit = FitMode(fit)
+		default:
+			return framing, fmt.Errorf("invalid %s %q: must be cover or contain", MetadataFitKey, fit)
+		}
+	}
+
+	if focus := strings.TrimSpace(meta[MetadataFocusKey]); focus != "" {
+		parts := strings.Split(focus, ",")
+		if len(parts) != 2 {
+			return framing, fmt.Errorf("invalid %s %q: must be x,y", MetadataFocusKey, focus)
+		}
+		x, errX := strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+		y, errY := strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		if errX != nil || errY != nil || x < 0 || x > 1 || y < 0 || y > 1 {
+			return framing, fmt.Errorf("invalid %s %q: coordinates must be between 0 and 1", MetadataFocusKey, focus)
+		}
+		framing.FocusX, framing.FocusY = x, y
+	}
+
+	if zoom := strings.TrimSpace(meta[MetadataZoomKey]); zoom != "" {
+		z, err := strconv.ParseFloat(zoom, 64)
+		if err != nil || z <= 0 {
+			return framing, fmt.Errorf("invalid %s %q: must be a positive number", MetadataZoomKey, zoom)
+		}
+		framing.Zoom = z
+	}
+	return framing, nil
+}
+
+// lanczos3 is the Lanczos resampling kernel with three lobes
+var lanczos3 = &draw.Kernel{
+	Support: 3,
+	At: func(t float64) float64 {
+		if t == 0 {
+			return 1
+		}
+		if t >= 3 {
+			return 0
+		}
+		x := math.Pi * t
+		return 3 * math.Sin(x) * math.Sin(x/3) / (x * x)
+	},
+}
+
+// ParseResizeAlgorithm returns the interpolator for a resize_algorithm name
+func ParseResizeAlgorithm(name string) (draw.Interpolator, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "lanczos", "lanczos3":
+		return lanczos3, nil
+	case "catmullrom", "catmull-rom", "bicubic":
+		return draw.CatmullRom, nil
+	case "bilinear", "linear":
+		return draw.BiLinear, nil
+	case "approxbilinear":
+		return draw.ApproxBiLinear, nil
+	case "nearest", "nearestneighbor":
+		return draw.NearestNeighbor, nil
+	default:
+		return nil, fmt.Errorf("unsupported resize algorithm: %s", name)
+	}
+}
+
+// Pipeline scales and crops art into its bounds
+type Pipeline struct {
+	algorithm    string
+	interpolator draw.Interpolator
+}
+
+// NewPipeline creates a pipeline resampling with the named algorithm
+func NewPipeline(algorithm string) (*Pipeline, error) {
+	interpolator, err := ParseResizeAlgorithm(algorithm)
+	if err != nil {
+		return nil, err
+	}
+	algorithm = strings.ToLower(strings.TrimSpace(algorithm))
+	if algorithm == "" {
+		algorithm = "lanczos"
+	}
+	return &Pipeline{algorithm: algorithm, interpolator: interpolator}, nil
+}
+
+// DefaultPipeline resamples with Lanczos
+func DefaultPipeline() *Pipeline {
+	return &Pipeline{algorithm: "lanczos", interpolator: lanczos3}
+}
+
+// Algorithm returns the name of the resampling algorithm
+func (p *Pipeline) Algorithm() string {
+	return p.algorithm
+}
+
+// Fit scales the art into the bounds. Cover crops around the focal point and contain
+// letterboxes; zoom magnifies around the focal point in either mode.
+func (p *Pipeline) Fit(src image.Image, bounds image.Rectangle, framing Framing) *image.RGBA {
+	dst := image.NewRGBA(bounds)
+	sb := src.Bounds()
+	if sb.Empty() || bounds.Empty() {
+		return dst
+	}
+
+	sw, sh := float64(sb.Dx()), float64(sb.Dy())
+	bw, bh := float64(bounds.Dx()), float64(bounds.Dy())
+	scale := math.Max(bw/sw, bh/sh)
+	if framing.Fit == FitContain {
+		scale = math.Min(bw/sw, bh/sh)
+	}
+	if framing.Zoom > 0 {
+		scale *= framing.Zoom
+	}
+
+	srcX0, srcX1, dstX0, dstX1 := fitAxis(sw, bw, scale, framing.FocusX)
+	srcY0, srcY1, dstY0, dstY1 := fitAxis(sh, bh, scale, framing.FocusY)
+
+	srcRect := image.Rect(
+		sb.Min.X+int(math.Round(srcX0)), sb.Min.Y+int(math.Round(srcY0)),
+		sb.Min.X+int(math.Round(srcX1)), sb.Min.Y+int(math.Round(srcY1)),
+	)
+	dstRect := image.Rect(
+		bounds.Min.X+int(math.Round(dstX0)), bounds.Min.Y+int(math.Round(dstY0)),
+		bounds.Min.X+int(math.Round(dstX1)), bounds.Min.Y+int(math.Round(dstY1)),
+	)
+	if srcRect.Empty() || dstRect.Empty() {
+		return dst
+	}
+	p.interpolator.Scale(dst, dstRect, src, srcRect, draw.Src, nil)
+	return dst
+}
+
+// fitAxis returns the source span shown and where it lands along one axis. When the
+// scaled art is larger than the bounds, a window centered on the focus (clamped to the
+// art) fills them; otherwise the whole axis is drawn centered.
+func fitAxis(srcLen, dstLen, scale, focus float64) (src0, src1, dst0, dst1 float64) {
+	visible := dstLen / scale
+	if visible >= srcLen {
+		drawn := srcLen * scale
+		offset := (dstLen - drawn) / 2
+		return 0, srcLen, offset, offset + drawn
+	}
+
+	start := focus*srcLen - visible/2
+	start = math.Max(0, math.Min(srcLen-visible, start))
+	return start, start + visible, 0, dstLen
+}
@@ -0,0 +1,101 @@
+package art
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+// halves is a 200x100 image, red on the left half and blue on the right
+func halves() *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, 200, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 200; x++ {
+			if x < 100 {
+				img.Set(x, y, color.RGBA{255, 0, 0, 255})
+			} else {
+				img.Set(x, y, color.RGBA{0, 0, 255, 255})
+			}
+		}
+	}
+	return img
+}
+
+func rgba(img image.Image, x, y int) (r, g, b, a uint8) {
+	c := color.RGBAModel.Convert(img.At(x, y)).(color.RGBA)
+	return c.R, c.G, c.B, c.A
+}
+
+func TestPipelineFit(t *testing.T) {
+	bounds := image.Rect(50, 50, 150, 150) // square bounds for 2:1 art
+
+	tests := []struct {
+		name    string
+		framing Framing
+		x, y    int
+		wantR   uint8
+		wantB   uint8
+		wantA   uint8
+	}{
+		{"Cover centered shows both halves", DefaultFraming, 55, 100, 255, 0, 255},
+		{"Cover centered right side", DefaultFraming, 145, 100, 0, 255, 255},
+		{"Cover focused left shows only red", Framing{Fit: FitCover, FocusX: 0, FocusY: 0.5, Zoom: 1}, 145, 100, 255, 0, 255},
+		{"Cover focused right shows only blue", Framing{Fit: FitCover, FocusX: 1, FocusY: 0.5, Zoom: 1}, 55, 100, 0, 255, 255},
+		{"Contain leaves transparent bars", Framing{Fit: FitContain, FocusX: 0.5, FocusY: 0.5, Zoom: 1}, 100, 55, 0, 0, 0},
+		{"Contain draws the art in the middle", Framing{Fit: FitContain, FocusX: 0.5, FocusY: 0.5, Zoom: 1}, 55, 100, 255, 0, 255},
+		{"Zoom into the left half", Framing{Fit: FitCover, FocusX: 0.25, FocusY: 0.5, Zoom: 2}, 145, 100, 255, 0, 255},
+	}
+
+	for _, algorithm := range []string{"lanczos", "catmullrom", "bilinear", "nearest"} {
+		pipeline, err := NewPipeline(algorithm)
+		if err != nil {
+			t.Fatalf("NewPipeline(%s) error = %v", algorithm, err)
+		}
+		for _, tt := range tests {
+			t.Run(algorithm+"/"+tt.name, func(t *testing.T) {
+				out := pipeline.Fit(halves(), bounds, tt.framing)
+				if out.Bounds() != bounds {
+					t.Fatalf("bounds = %v, want %v", out.Bounds(), bounds)
+				}
+				r, _, b, a := rgba(out, tt.x, tt.y)
+				if r != tt.wantR || b != tt.wantB || a != tt.wantA {
+					t.Errorf("pixel (%d,%d) = r%d b%d a%d, want r%d b%d a%d", tt.x, tt.y, r, b, a, tt.wantR, tt.wantB, tt.wantA)
+				}
+			})
+		}
+	}
+
+	if _, err := NewPipeline("sharpest"); err == nil {
+		t.Error("NewPipeline() with an unknown algorithm should fail")
+	}
+}
+
+func TestFramingFromMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		want     Framing
+		wantErr  bool
+	}{
+		{"Defaults", nil, DefaultFraming, false},
+		{"All keys", map[string]string{"art_fit": "Contain", "art_focus": "0.2, 0.8", "art_zoom": "1.5"}, Framing{Fit: FitContain, FocusX: 0.2, FocusY: 0.8, Zoom: 1.5}, false},
+		{"Bad fit", map[string]string{"art_fit": "stretch"}, Framing{}, true},
+		{"Focus out of range", map[string]string{"art_focus": "1.2,0"}, Framing{}, true},
+		{"Focus missing coordinate", map[string]string{"art_focus": "0.5"}, Framing{}, true},
+		{"Zero zoom", map[string]string{"art_zoom": "0"}, Framing{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FramingFromMetadata(&card.CardDTO{Metadata: tt.metadata})
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FramingFromMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("FramingFromMetadata() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
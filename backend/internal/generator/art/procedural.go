@@ -0,0 +1,104 @@
+package art
+
+import (
+	"hash/fnv"
+	"image"
+	"image/color"
+	"math"
+	"math/rand"
+)
+
+// pattern is a procedural overlay returning an intensity between 0 and 1 for a point in unit space
+type pattern func(x, y, scale, phase float64) float64
+
+var patterns = []pattern{
+	// Diagonal stripes
+	func(x, y, scale, phase float64) float64 {
+		return 0.5 + 0.5*math.Sin((x+y)*scale*math.Pi+phase)
+	},
+	// Concentric rings around the center
+	func(x, y, scale, phase float64) float64 {
+		return 0.5 + 0.5*math.Sin(math.Hypot(x-0.5, y-0.5)*scale*2*math.Pi+phase)
+	},
+	// Grid of soft dots
+	func(x, y, scale, phase float64) float64 {
+		return (0.5 + 0.5*math.Sin(x*scale*2*math.Pi+phase)) * (0.5 + 0.5*math.Sin(y*scale*2*math.Pi+phase))
+	},
+	// Horizontal waves
+	func(x, y, scale, phase float64) float64 {
+		return 0.5 + 0.5*math.Sin((y+0.08*math.Sin(x*scale*math.Pi+phase))*scale*2*math.Pi)
+	},
+}
+
+// patternStrength is how strongly the pattern lightens the gradient
+const patternStrength = 0.18
+
+// Procedural generates deterministic art for a card without any: a two-color gradient
+// with a pattern overlay, both chosen from a hash of the seed (the card name).
+func Procedural(seed string, bounds image.Rectangle) *image.RGBA {
+	h := fnv.New64a()
+	h.Write([]byte(seed))
+	rng := rand.New(rand.NewSource(int64(h.Sum64())))
+
+	hue := rng.Float64() * 360
+	from := hsv(hue, 0.45+rng.Float64()*0.25, 0.55+rng.Float64()*0.2)
+	to := hsv(math.Mod(hue+40+rng.Float64()*120, 360), 0.5+rng.Float64()*0.25, 0.25+rng.Float64()*0.2)
+	angle := rng.Float64() * 2 * math.Pi
+	overlay := patterns[rng.Intn(len(patterns))]
+	scale := 4 + rng.Float64()*8
+	phase := rng.Float64() * 2 * math.Pi
+
+	img := image.NewRGBA(bounds)
+	width, height := float64(bounds.Dx()), float64(bounds.Dy())
+	dx, dy := math.Cos(angle), math.Sin(angle)
+	// Project onto the gradient direction; normalize so the corners map to 0 and 1
+	span := math.Abs(dx) + math.Abs(dy)
+
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		y := (float64(py-bounds.Min.Y) + 0.5) / height
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			x := (float64(px-bounds.Min.X) + 0.5) / width
+
+			t := ((x-0.5)*dx+(y-0.5)*dy)/span + 0.5
+			light := patternStrength * overlay(x, y, scale, phase)
+			img.SetRGBA(px, py, color.RGBA{
+				R: blend(from[0], to[0], t, light),
+				G: blend(from[1], to[1], t, light),
+				B: blend(from[2], to[2], t, light),
+				A: 255,
+			})
+		}
+	}
+	return img
+}
+
+// blend interpolates a channel along the gradient and lightens it towards white
+func blend(from, to, t, light float64) uint8 {
+	v := from + (to-from)*t
+	v += (1 - v) * light
+	return uint8(math.Round(math.Max(0, math.Min(1, v)) * 255))
+}
+
+// hsv converts a hue in degrees, saturation and value to RGB channels between 0 and 1
+func hsv(h, s, v float64) [3]float64 {
+	c := v * s
+	x := c * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := v - c
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = c, x, 0
+	case h < 120:
+		r, g, b = x, c, 0
+	case h < 180:
+		r, g, b = 0, c, x
+	case h < 240:
+		r, g, b = 0, x, c
+	case h < 300:
+		r, g, b = x, 0, c
+	default:
+		r, g, b = c, 0, x
+	}
+	return [3]float64{r + m, g + m, b + m}
+}
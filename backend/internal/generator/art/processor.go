@@ -14,8 +14,8 @@ type ArtProcessor interface {
 
 // ArtSource represents where art can come from
 type ArtSource interface {
-	// GetArt retrieves art for a given card
-	GetArt(cardID string) (image.Image, error)
+	// GetArt retrieves art for a given card, returning ErrArtNotFound when it has none
+	GetArt(data *card.CardDTO) (image.Image, error)
 }
 
 // ArtProvider is a factory for creating art processors
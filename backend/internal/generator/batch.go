@@ -0,0 +1,164 @@
+package generator
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"runtime"
+	"sync"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
+)
+
+// BatchJob is one card to render and where it is written. The front goes to Writer when
+// set, or else to OutputPath; with neither, only the back is rendered.
+type BatchJob struct {
+	Card       *card.CardDTO
+	OutputPath string         // front image file
+	Writer     io.Writer      // receives the front encoded with Options; each job needs its own
+	Options    output.Options // encoding of the front written to Writer
+	BackPath   string         // back image; empty to render only the front
+}
+
+// BatchResult is the outcome of rendering one job
+type BatchResult struct {
+	Index      int    // position of the job in the batch
+	Name       string // card name
+	OutputPath string
+	BackPath   string
+	Err        error // nil when the card rendered
+}
+
+// Progress is reported after each job finishes
+type Progress struct {
+	Done   int // jobs finished, including failures
+	Failed int
+	Total  int
+	Last   BatchResult // the job that just finished
+}
+
+// ProgressFunc receives progress updates. Calls are serialized, so it does not need to be safe for concurrent use.
+type ProgressFunc func(Progress)
+
+// BatchRenderer renders many cards with a bounded pool of workers sharing one generator
+type BatchRenderer struct {
+	generator CardGenerator
+	workers   int
+	progress  ProgressFunc
+}
+
+// NewBatchRenderer creates a batch renderer using the given number of workers, or one per CPU when workers is not positive
+func NewBatchRenderer(generator CardGenerator, workers int) *BatchRenderer {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &BatchRenderer{
+		generator: generator,
+		workers:   workers,
+	}
+}
+
+// OnProgress sets the function called after each job finishes
+func (b *BatchRenderer) OnProgress(fn ProgressFunc) *BatchRenderer {
+	b.progress = fn
+	return b
+}
+
+// Render returns one result per job in input order. A card that fails to render
+// is reported in its result's Err; only cancellation of ctx fails the run, in
+// which case jobs that had not started are reported with the context's error.
+func (b *BatchRenderer) Render(ctx context.Context, jobs []BatchJob) ([]BatchResult, error) {
+	results := make([]BatchResult, len(jobs))
+	for i, job := range jobs {
+		results[i] = BatchResult{Index: i, OutputPath: job.OutputPath, BackPath: job.BackPath}
+		if job.Card != nil {
+			results[i].Name = job.Card.Name
+		}
+	}
+
+	var (
+		mu       sync.Mutex
+		progress = Progress{Total: len(jobs)}
+	)
+	finish := func(result BatchResult) {
+		mu.Lock()
+		defer mu.Unlock()
+		progress.Done++
+		if result.Err != nil {
+			progress.Failed++
+		}
+		progress.Last = result
+		if b.progress != nil {
+			b.progress(progress)
+		}
+	}
+
+	queue := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < b.workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range queue {
+				if err := ctx.Err(); err != nil {
+					results[i].Err = err
+					continue
+				}
+				results[i].Err = b.renderJob(jobs[i])
+				finish(results[i])
+			}
+		}()
+	}
+
+	next := 0
+feed:
+	for ; next < len(jobs); next++ {
+		select {
+		case queue <- next:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(queue)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		for i := next; i < len(jobs); i++ {
+			results[i].Err = err
+		}
+		return results, err
+	}
+	return results, nil
+}
+
+// renderJob renders the sides of a card that have an output
+func (b *BatchRenderer) renderJob(job BatchJob) error {
+	switch {
+	case job.Writer != nil:
+		if err := b.generator.RenderTo(job.Writer, job.Card, job.Options); err != nil {
+			return err
+		}
+	case job.OutputPath != "":
+		if err := b.generator.GenerateCard(job.Card, job.OutputPath); err != nil {
+			return err
+		}
+	}
+	if job.BackPath != "" {
+		if err := b.generator.GenerateBack(job.Card, job.BackPath); err != nil {
+			return fmt.Errorf("failed to render back: %w", err)
+		}
+	}
+	return nil
+}
+
+// Failed returns the results that did not render
+func Failed(results []BatchResult) []BatchResult {
+	var failed []BatchResult
+	for _, result := range results {
+		if result.Err != nil {
+			failed = append(failed, result)
+		}
+	}
+	return failed
+}
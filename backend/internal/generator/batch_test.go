@@ -0,0 +1,222 @@
+package generator
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
+)
+
+// fakeGenerator records concurrency and fails cards named "bad"
+type fakeGenerator struct {
+	running  atomic.Int32
+	peak     atomic.Int32
+	rendered sync.Map
+	backs    atomic.Int32
+	onRender func()
+}
+
+func (f *fakeGenerator) GenerateCard(data *card.CardDTO, outputPath string) error {
+	n := f.running.Add(1)
+	defer f.running.Add(-1)
+	for {
+		peak := f.peak.Load()
+		if n <= peak || f.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	if f.onRender != nil {
+		f.onRender()
+	}
+	time.Sleep(time.Millisecond)
+	if data.Name == "bad" {
+		return errors.New("render failed")
+	}
+	f.rendered.Store(outputPath, true)
+	return nil
+}
+
+func (f *fakeGenerator) GenerateCardWithOptions(data *card.CardDTO, outputPath string, opts output.Options) error {
+	return f.GenerateCard(data, outputPath)
+}
+
+func (f *fakeGenerator) Render(data *card.CardDTO) (image.Image, error) {
+	return image.NewRGBA(image.Rect(0, 0, 1, 1)), nil
+}
+
+func (f *fakeGenerator) RenderTo(w io.Writer, data *card.CardDTO, opts output.Options) error {
+	if err := f.GenerateCard(data, ""); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, data.Name)
+	return err
+}
+
+func (f *fakeGenerator) RenderBack(data *card.CardDTO) (image.Image, error) {
+	return image.NewRGBA(image.Rect(0, 0, 1, 1)), nil
+}
+
+func (f *fakeGenerator) GenerateBack(data *card.CardDTO, outputPath string) error {
+	f.backs.Add(1)
+	return nil
+}
+
+func (f *fakeGenerator) ValidateCard(data *card.CardDTO) error { return nil }
+func (f *fakeGenerator) Close() error                          { return nil }
+
+func batchJobs(names ...string) []BatchJob {
+	jobs := make([]BatchJob, 0, len(names))
+	for i, name := range names {
+		jobs = append(jobs, BatchJob{Card: &card.CardDTO{Name: name}, OutputPath: fmt.Sprintf("%d.png", i)})
+	}
+	return jobs
+}
+
+func TestBatchRenderer(t *testing.T) {
+	tests := []struct {
+		name       string
+		workers    int
+		cards      []string
+		wantFailed []int
+	}{
+		{"Empty batch", 2, nil, nil},
+		{"All render", 3, []string{"a", "b", "c", "d", "e", "f", "g"}, nil},
+		{"Failures are reported per card", 2, []string{"a", "bad", "c", "bad"}, []int{1, 3}},
+		{"Single worker", 1, []string{"a", "b", "bad"}, []int{2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := &fakeGenerator{}
+			var updates []Progress
+			renderer := NewBatchRenderer(gen, tt.workers).OnProgress(func(p Progress) {
+				updates = append(updates, p)
+			})
+
+			results, err := renderer.Render(context.Background(), batchJobs(tt.cards...))
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if len(results) != len(tt.cards) {
+				t.Fatalf("got %d results, want %d", len(results), len(tt.cards))
+			}
+			for i, result := range results {
+				if result.Index != i || result.Name != tt.cards[i] || result.OutputPath != fmt.Sprintf("%d.png", i) {
+					t.Errorf("result %d = %+v, out of order", i, result)
+				}
+			}
+
+			failed := Failed(results)
+			if len(failed) != len(tt.wantFailed) {
+				t.Fatalf("failed = %v, want indexes %v", failed, tt.wantFailed)
+			}
+			for i, result := range failed {
+				if result.Index != tt.wantFailed[i] {
+					t.Errorf("failed[%d].Index = %d, want %d", i, result.Index, tt.wantFailed[i])
+				}
+			}
+
+			if gen.backs.Load() != 0 {
+				t.Errorf("rendered %d backs without back paths", gen.backs.Load())
+			}
+			if peak := int(gen.peak.Load()); peak > tt.workers {
+				t.Errorf("peak concurrency = %d, want at most %d", peak, tt.workers)
+			}
+			if len(updates) != len(tt.cards) {
+				t.Fatalf("got %d progress updates, want %d", len(updates), len(tt.cards))
+			}
+			if len(updates) > 0 {
+				last := updates[len(updates)-1]
+				if last.Done != len(tt.cards) || last.Total != len(tt.cards) || last.Failed != len(tt.wantFailed) {
+					t.Errorf("final progress = %+v", last)
+				}
+			}
+		})
+	}
+}
+
+func TestBatchRendererCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	gen := &fakeGenerator{}
+	var calls atomic.Int32
+	gen.onRender = func() {
+		if calls.Add(1) == 2 {
+			cancel()
+		}
+	}
+
+	results, err := NewBatchRenderer(gen, 1).Render(ctx, batchJobs("a", "b", "c", "d", "e"))
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Render() error = %v, want context.Canceled", err)
+	}
+	if len(results) != 5 {
+		t.Fatalf("got %d results, want 5", len(results))
+	}
+	for _, result := range results[2:] {
+		if !errors.Is(result.Err, context.Canceled) {
+			t.Errorf("job %d error = %v, want context.Canceled", result.Index, result.Err)
+		}
+	}
+	if n := calls.Load(); n > 3 {
+		t.Errorf("rendered %d cards after cancellation, want at most 3", n)
+	}
+}
+
+func TestBatchRendererBacks(t *testing.T) {
+	gen := &fakeGenerator{}
+	jobs := []BatchJob{
+		{Card: &card.CardDTO{Name: "a"}, OutputPath: "a.png", BackPath: "a-back.png"},
+		{Card: &card.CardDTO{Name: "b"}, BackPath: "b-back.png"},
+		{Card: &card.CardDTO{Name: "bad"}, OutputPath: "bad.png", BackPath: "bad-back.png"},
+	}
+
+	results, err := NewBatchRenderer(gen, 2).Render(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if n := gen.backs.Load(); n != 2 {
+		t.Errorf("rendered %d backs, want 2; a failed front skips its back", n)
+	}
+	if _, ok := gen.rendered.Load("b.png"); ok {
+		t.Error("rendered a front for a back-only job")
+	}
+	if results[1].BackPath != "b-back.png" || results[1].Err != nil {
+		t.Errorf("back-only result = %+v", results[1])
+	}
+}
+
+func TestBatchRendererWriters(t *testing.T) {
+	gen := &fakeGenerator{}
+	names := []string{"a", "bad", "c"}
+	buffers := make([]bytes.Buffer, len(names))
+	jobs := make([]BatchJob, len(names))
+	for i, name := range names {
+		jobs[i] = BatchJob{Card: &card.CardDTO{Name: name}, Writer: &buffers[i]}
+	}
+
+	results, err := NewBatchRenderer(gen, 2).Render(context.Background(), jobs)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	for i, name := range names {
+		want := name
+		if name == "bad" {
+			want = ""
+			if results[i].Err == nil {
+				t.Errorf("job %d should have failed", i)
+			}
+		}
+		if got := buffers[i].String(); got != want {
+			t.Errorf("writer %d received %q, want %q", i, got, want)
+		}
+	}
+}
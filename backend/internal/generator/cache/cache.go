@@ -0,0 +1,206 @@
+// Package cache stores rendered card images on disk, keyed by a hash of everything that affects the render
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// fileExt is the extension of cached renders, which may be encoded in any output format
+const fileExt = ".img"
+
+// Cache is a size-limited directory of rendered images. When a write takes it over its
+// limit, the least recently used entries are removed. It is safe for concurrent use.
+type Cache struct {
+	dir     string
+	maxSize int64
+
+	mu   sync.Mutex
+	size int64
+}
+
+// New opens the cache directory, creating it if needed. A maxSize of zero or less means no limit.
+func New(dir string, maxSize int64) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	c := &Cache{dir: dir, maxSize: maxSize}
+
+	entries, err := c.entries()
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		c.size += e.size
+	}
+	return c, nil
+}
+
+// Key hashes the parts into a cache key; parts are length-prefixed so their boundaries matter
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, part := range parts {
+		fmt.Fprintf(h, "%d:%s", len(part), part)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Get returns the cached data for the key and marks it as recently used
+func (c *Cache) Get(key string) ([]byte, bool) {
+	path, err := c.path(key)
+	if err != nil {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	now := time.Now()
+	_ = os.Chtimes(path, now, now) // best effort; a stale time only makes eviction less accurate
+	return data, true
+}
+
+// Put stores data under the key, evicting least recently used entries when over the size limit
+func (c *Cache) Put(key string, data []byte) error {
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	// Write to a temporary file and rename, so readers never see a partial entry
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create cache entry: %w", err)
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to write cache entry: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var replaced int64
+	if info, err := os.Stat(path); err == nil {
+		replaced = info.Size()
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("failed to store cache entry: %w", err)
+	}
+	c.size += int64(len(data)) - replaced
+
+	if c.maxSize > 0 && c.size > c.maxSize {
+		return c.evict(path)
+	}
+	return nil
+}
+
+// Size returns the total size in bytes of the cached entries
+func (c *Cache) Size() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.size
+}
+
+// Clear removes every entry
+func (c *Cache) Clear() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove cache entry: %w", err)
+		}
+		c.size -= e.size
+	}
+	return nil
+}
+
+// path is where an entry is stored, sharded by the key's first two characters
+func (c *Cache) path(key string) (string, error) {
+	if len(key) < 3 || strings.ContainsAny(key, `/\.`) {
+		return "", fmt.Errorf("invalid cache key: %q", key)
+	}
+	return filepath.Join(c.dir, key[:2], key+fileExt), nil
+}
+
+// entry is a cached file
+type entry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// entries lists the cached files
+func (c *Cache) entries() ([]entry, error) {
+	var entries []entry
+	err := filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() || filepath.Ext(path) != fileExt {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry{path: path, size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to scan cache directory: %w", err)
+	}
+	return entries, nil
+}
+
+// evict removes the least recently used entries until the cache fits its limit, keeping the entry just written
+func (c *Cache) evict(keep string) error {
+	entries, err := c.entries()
+	if err != nil {
+		return err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].modTime.Before(entries[j].modTime)
+	})
+
+	// Recount from disk so entries written by other processes are accounted for
+	c.size = 0
+	for _, e := range entries {
+		c.size += e.size
+	}
+	for _, e := range entries {
+		if c.size <= c.maxSize {
+			break
+		}
+		if e.path == keep {
+			continue
+		}
+		if err := os.Remove(e.path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to evict cache entry: %w", err)
+		}
+		c.size -= e.size
+	}
+	return nil
+}
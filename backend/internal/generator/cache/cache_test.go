@@ -0,0 +1,115 @@
+package cache
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestCacheGetPut(t *testing.T) {
+	c, err := New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	key := Key("card", "fonts", "art")
+	if _, ok := c.Get(key); ok {
+		t.Fatal("Get() on an empty cache should miss")
+	}
+	if err := c.Put(key, []byte("image")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	data, ok := c.Get(key)
+	if !ok || !bytes.Equal(data, []byte("image")) {
+		t.Errorf("Get() = %q, %v, want image", data, ok)
+	}
+
+	if err := c.Put(key, []byte("new image")); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	if c.Size() != int64(len("new image")) {
+		t.Errorf("Size() after replacing = %d, want %d", c.Size(), len("new image"))
+	}
+
+	if err := c.Put("../escape", []byte("x")); err == nil {
+		t.Error("Put() with a path in the key should fail")
+	}
+}
+
+func TestKey(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b []string
+		same bool
+	}{
+		{"Same parts", []string{"a", "b"}, []string{"a", "b"}, true},
+		{"Different parts", []string{"a", "b"}, []string{"a", "c"}, false},
+		{"Boundaries matter", []string{"ab", "c"}, []string{"a", "bc"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Key(tt.a...) == Key(tt.b...); got != tt.same {
+				t.Errorf("Key(%v) == Key(%v) is %v, want %v", tt.a, tt.b, got, tt.same)
+			}
+		})
+	}
+}
+
+func TestCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	c, err := New(dir, 25)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	keys := []string{Key("a"), Key("b"), Key("c")}
+	past := time.Now().Add(-time.Hour)
+	for i, key := range keys {
+		if err := c.Put(key, bytes.Repeat([]byte{'x'}, 10)); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+		// Age the entries so their use order is unambiguous
+		path, _ := c.path(key)
+		when := past.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(path, when, when)
+
+		if i == 1 {
+			// Using the first entry makes the second the least recently used
+			if _, ok := c.Get(keys[0]); !ok {
+				t.Fatal("Get() missed a cached entry")
+			}
+		}
+	}
+
+	if _, ok := c.Get(keys[1]); ok {
+		t.Error("least recently used entry should have been evicted")
+	}
+	for _, key := range []string{keys[0], keys[2]} {
+		if _, ok := c.Get(key); !ok {
+			t.Errorf("entry %s should still be cached", key[:8])
+		}
+	}
+	if c.Size() > 25 {
+		t.Errorf("Size() = %d, over the 25 byte limit", c.Size())
+	}
+
+	// Reopening counts the entries already on disk
+	reopened, err := New(dir, 25)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	if reopened.Size() != 20 {
+		t.Errorf("reopened Size() = %d, want 20", reopened.Size())
+	}
+
+	if err := reopened.Clear(); err != nil {
+		t.Fatalf("Clear() error = %v", err)
+	}
+	matches, _ := filepath.Glob(filepath.Join(dir, "*", "*"+fileExt))
+	if len(matches) != 0 || reopened.Size() != 0 {
+		t.Errorf("Clear() left %d entries, size %d", len(matches), reopened.Size())
+	}
+}
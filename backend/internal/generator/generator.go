@@ -2,24 +2,47 @@
 package generator
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"image"
 	"image/draw"
-	"image/png"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/art"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/cache"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/base"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/factory"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/types"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/text"
 )
 
 // CardGenerator defines the interface for generating cards
 type CardGenerator interface {
-	// GenerateCard creates a card image from the provided data and saves it to the specified path
+	// GenerateCard creates a card image from the provided data and saves it to the specified path.
+	// The path's extension selects the format when it names one; otherwise the configured format is used.
 	GenerateCard(data *card.CardDTO, outputPath string) error
 
+	// GenerateCardWithOptions creates a card image encoded with the given options
+	GenerateCardWithOptions(data *card.CardDTO, outputPath string, opts output.Options) error
+
+	// Render draws the card at the frame's native size without encoding it
+	Render(data *card.CardDTO) (image.Image, error)
+
+	// RenderTo writes the card encoded with the given options to w
+	RenderTo(w io.Writer, data *card.CardDTO, opts output.Options) error
+
+	// GenerateBack creates the card's back image and saves it to the specified path
+	GenerateBack(data *card.CardDTO, outputPath string) error
+
+	// RenderBack returns the card's back without encoding it. Backs are shared between cards and must not be modified.
+	RenderBack(data *card.CardDTO) (image.Image, error)
+
 	// ValidateCard checks if the card data is valid for generation
 	ValidateCard(data *card.CardDTO) error
 
@@ -27,10 +50,22 @@ type CardGenerator interface {
 	Close() error
 }
 
+// TemplateVersion identifies the card frames and layout. Bump it when either changes so cached renders are not reused.
+const TemplateVersion = "1"
+
+// Fingerprinter is implemented by processors that can identify what they draw for a card.
+// Renders are only cached when both the text and art processors implement it.
+type Fingerprinter interface {
+	Fingerprint(data *card.CardDTO) (string, error)
+}
+
 // cardGenerator implements the CardGenerator interface
 type cardGenerator struct {
 	textProc text.TextProcessor
 	artProc  art.ArtProcessor
+	backs    *types.BackTemplate
+	cache    *cache.Cache
+	output   output.Options
 }
 
 // Configuration for the card generator
@@ -38,6 +73,10 @@ type Config struct {
 	OutputPath string             // Base path for output files
 	TextProc   text.TextProcessor // Custom text processor (optional)
 	ArtProc    art.ArtProcessor   // Custom art processor (optional)
+	ArtDir     string             // Directory searched for card art when ArtProc is unset
+	Cache      *cache.Cache       // Render cache (optional)
+	BackDir    string             // Directory holding set and global card backs
+	Output     output.Options     // Default format, size, quality and DPI of written images
 }
 
 // NewCardGenerator creates a new card generator with default processors
@@ -47,18 +86,27 @@ func NewCardGenerator() (CardGenerator, error) {
 		return nil, fmt.Errorf("failed to create text processor: %w", err)
 	}
 
-	artProc := art.NewPlaceholderProcessor()
+	artProc := art.NewLocalProcessor(art.NewLocalSource(""), nil, true)
 
 	return &cardGenerator{
 		textProc: textProc,
 		artProc:  artProc,
+		backs:    factory.NewBackTemplate(""),
 	}, nil
 }
 
 // NewCardGeneratorWithConfig creates a new card generator with custom configuration
 func NewCardGeneratorWithConfig(cfg *Config) (CardGenerator, error) {
+	if err := cfg.Output.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid output options: %w", err)
+	}
+
 	var err error
-	g := &cardGenerator{}
+	g := &cardGenerator{
+		backs:  factory.NewBackTemplate(cfg.BackDir),
+		cache:  cfg.Cache,
+		output: cfg.Output,
+	}
 
 	// Initialize text processor
 	if cfg.TextProc != nil {
@@ -74,7 +122,7 @@ func NewCardGeneratorWithConfig(cfg *Config) (CardGenerator, error) {
 	if cfg.ArtProc != nil {
 		g.artProc = cfg.ArtProc
 	} else {
-		g.artProc = art.NewPlaceholderProcessor()
+		g.artProc = art.NewLocalProcessor(art.NewLocalSource(cfg.ArtDir), nil, true)
 	}
 
 	return g, nil
@@ -118,21 +166,96 @@ func (g *cardGenerator) ValidateCard(data *card.CardDTO) error {
 }
 
 func (g *cardGenerator) GenerateCard(data *card.CardDTO, outputPath string) error {
+	return g.GenerateCardWithOptions(data, outputPath, g.output.ForPath(outputPath))
+}
+
+func (g *cardGenerator) GenerateCardWithOptions(data *card.CardDTO, outputPath string, opts output.Options) error {
+	// Encode in memory so a failed render leaves no partial file
+	var buf bytes.Buffer
+	if err := g.RenderTo(&buf, data, opts); err != nil {
+		return err
+	}
+	return writeFile(outputPath, buf.Bytes())
+}
+
+func (g *cardGenerator) Render(data *card.CardDTO) (image.Image, error) {
+	if err := g.ValidateCard(data); err != nil {
+		return nil, fmt.Errorf("invalid card data: %w", err)
+	}
+	return g.render(data)
+}
+
+func (g *cardGenerator) RenderTo(w io.Writer, data *card.CardDTO, opts output.Options) error {
 	// Validate card data
 	if err := g.ValidateCard(data); err != nil {
 		return fmt.Errorf("invalid card data: %w", err)
 	}
+	if err := opts.Validate(); err != nil {
+		return fmt.Errorf("invalid output options: %w", err)
+	}
+
+	// Reuse an earlier render when nothing that affects the image has changed
+	key, cacheable := g.cacheKey(data, opts)
+	if cacheable {
+		if cached, ok := g.cache.Get(key); ok {
+			return writeImage(w, cached)
+		}
+	}
+
+	img, err := g.render(data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := output.Encode(&buf, img, opts); err != nil {
+		return fmt.Errorf("failed to encode image: %w", err)
+	}
+
+	if cacheable {
+		// A cache write failure only costs a re-render next time
+		_ = g.cache.Put(key, buf.Bytes())
+	}
+	return writeImage(w, buf.Bytes())
+}
+
+func (g *cardGenerator) GenerateBack(data *card.CardDTO, outputPath string) error {
+	back, err := g.RenderBack(data)
+	if err != nil {
+		return err
+	}
+
+	var buf bytes.Buffer
+	if err := output.Encode(&buf, back, g.output.ForPath(outputPath)); err != nil {
+		return fmt.Errorf("failed to encode back: %w", err)
+	}
+	return writeFile(outputPath, buf.Bytes())
+}
+
+func (g *cardGenerator) RenderBack(data *card.CardDTO) (image.Image, error) {
+	if data == nil {
+		return nil, fmt.Errorf("card data cannot be nil")
+	}
 
+	back, err := g.backs.GetBack(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get back: %w", err)
+	}
+	return back, nil
+}
+
+// render draws the frame, art and text of a card
+func (g *cardGenerator) render(data *card.CardDTO) (*image.RGBA, error) {
 	// Get appropriate template for card type
-	template, err := factory.NewTemplate(data.Type)
+	template, err := factory.NewTemplateFor(data)
 	if err != nil {
-		return fmt.Errorf("failed to get template: %w", err)
+		return nil, fmt.Errorf("failed to get template: %w", err)
 	}
 
 	// Get base frame
 	frame, err := template.GetFrame(data)
 	if err != nil {
-		return fmt.Errorf("failed to get frame: %w", err)
+		return nil, fmt.Errorf("failed to get frame: %w", err)
 	}
 
 	// Create base image
@@ -144,37 +267,110 @@ func (g *cardGenerator) GenerateCard(data *card.CardDTO, outputPath string) erro
 	artBounds := template.GetArtBounds()
 	art, err := g.artProc.ProcessArt(data, artBounds)
 	if err != nil {
-		return fmt.Errorf("failed to process art: %w", err)
+		return nil, fmt.Errorf("failed to process art: %w", err)
 	}
-	draw.Draw(img, artBounds, art, image.Point{}, draw.Over)
+	draw.Draw(img, artBounds, art, art.Bounds().Min, draw.Over)
 
 	// Process and add text
 	textBounds := template.GetTextBounds(data)
-	if err := g.textProc.RenderText(img, data, textBounds); err != nil {
-		return fmt.Errorf("failed to render text: %w", err)
+	styled, isStyled := template.(base.StyledTemplate)
+	styledProc, canStyle := g.textProc.(text.StyledTextProcessor)
+	if isStyled && canStyle {
+		err = styledProc.RenderStyledText(img, data, textBounds, styled.GetTextStyles(data))
+	} else {
+		err = g.textProc.RenderText(img, data, textBounds)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to render text: %w", err)
 	}
 
-	// Ensure output directory exists
-	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	return img, nil
+}
+
+// renderFields are the card fields that affect its image. The ID only matters for
+// finding art, which the art fingerprint covers.
+type renderFields struct {
+	Type        card.CardType     `json:"type"`
+	Name        string            `json:"name"`
+	Cost        int               `json:"cost"`
+	Effect      string            `json:"effect"`
+	Keywords    []string          `json:"keywords"`
+	Attack      int               `json:"attack"`
+	Defense     int               `json:"defense"`
+	Trait       string            `json:"trait"`
+	IsEquipment bool              `json:"is_equipment"`
+	Metadata    map[string]string `json:"metadata"`
+}
+
+// cacheKey hashes the card's render fields, the template version, the fonts, the art and the output options.
+// It reports false when there is no cache or a processor cannot fingerprint its output.
+func (g *cardGenerator) cacheKey(data *card.CardDTO, opts output.Options) (string, bool) {
+	if g.cache == nil {
+		return "", false
+	}
+	textFP, ok := g.textProc.(Fingerprinter)
+	if !ok {
+		return "", false
+	}
+	artFP, ok := g.artProc.(Fingerprinter)
+	if !ok {
+		return "", false
 	}
 
-	// Save the image
-	f, err := os.Create(outputPath)
+	fields, err := json.Marshal(renderFields{
+		Type:        data.Type,
+		Name:        data.Name,
+		Cost:        data.Cost,
+		Effect:      data.Effect,
+		Keywords:    data.Keywords,
+		Attack:      data.Attack,
+		Defense:     data.Defense,
+		Trait:       data.Trait,
+		IsEquipment: data.IsEquipment,
+		Metadata:    data.Metadata,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return "", false
+	}
+	fonts, err := textFP.Fingerprint(data)
+	if err != nil {
+		return "", false
+	}
+	artwork, err := artFP.Fingerprint(data)
+	if err != nil {
+		return "", false
+	}
+	parts := []string{TemplateVersion, string(fields), fonts, artwork, opts.String()}
+
+	// Layouts are dropped in without a template version bump, so their files are part of the key
+	if name := strings.TrimSpace(data.Metadata[types.MetadataFrameKey]); name != "" {
+		template, err := factory.NewLayoutTemplate(name)
+		if err != nil {
+			return "", false
+		}
+		parts = append(parts, template.Layout().Fingerprint)
 	}
-	defer f.Close()
+	return cache.Key(parts...), true
+}
 
-	// Use png encoder with best compression
-	encoder := png.Encoder{
-		CompressionLevel: png.BestCompression,
+// writeImage writes an encoded image to w
+func writeImage(w io.Writer, data []byte) error {
+	if _, err := w.Write(data); err != nil {
+		return fmt.Errorf("failed to write image: %w", err)
 	}
+	return nil
+}
 
-	if err := encoder.Encode(f, img); err != nil {
-		return fmt.Errorf("failed to encode image: %w", err)
+// writeFile writes an encoded image, creating its directory
+func writeFile(outputPath string, data []byte) error {
+	// Ensure output directory exists
+	if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	if err := os.WriteFile(outputPath, data, 0644); err != nil {
+		return fmt.Errorf("failed to write output file: %w", err)
+	}
 	return nil
 }
 
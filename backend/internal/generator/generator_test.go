@@ -1,15 +1,24 @@
 package generator
 
 import (
+	"bytes"
 	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/jpeg"
 	"image/png"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
+	_ "golang.org/x/image/webp"
+
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/cache"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/mocks"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/text"
 )
 
@@ -143,3 +152,201 @@ func validateOutputFile(t *testing.T, path string) {
 			bounds.Dx(), bounds.Dy())
 	}
 }
+
+// countingArt draws solid art and counts renders; its fingerprint is the card's "art_version" metadata
+type countingArt struct {
+	renders int
+}
+
+func (c *countingArt) ProcessArt(data *card.CardDTO, bounds image.Rectangle) (image.Image, error) {
+	c.renders++
+	return image.NewUniform(color.RGBA{40, 80, 120, 255}), nil
+}
+
+func (c *countingArt) Fingerprint(data *card.CardDTO) (string, error) {
+	return data.Metadata["art_version"], nil
+}
+
+func TestGenerateCardCache(t *testing.T) {
+	renderCache, err := cache.New(t.TempDir(), 0)
+	if err != nil {
+		t.Fatalf("cache.New() error = %v", err)
+	}
+	artProc := &countingArt{}
+	generator, err := NewCardGeneratorWithConfig(&Config{ArtProc: artProc, Cache: renderCache})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+
+	base := card.CardDTO{
+		Type:     card.TypeSpell,
+		Name:     "Fire Bolt",
+		Cost:     1,
+		Effect:   "Deal 3 damage to any target.",
+		Metadata: map[string]string{"art_version": "1"},
+	}
+	changedEffect := base
+	changedEffect.Effect = "Deal 4 damage to any target."
+	changedArt := base
+	changedArt.Metadata = map[string]string{"art_version": "2"}
+	newID := base
+	newID.ID = "another-id"
+
+	tests := []struct {
+		name        string
+		card        card.CardDTO
+		wantRenders int
+	}{
+		{"First render", base, 1},
+		{"Unchanged card is cached", base, 1},
+		{"ID does not affect the image", newID, 1},
+		{"Changed effect re-renders", changedEffect, 2},
+		{"Changed art re-renders", changedArt, 3},
+		{"Another format re-renders", changedArt, 4},
+	}
+
+	dir := t.TempDir()
+	var first []byte
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ext := ".png"
+			if i == len(tests)-1 {
+				ext = ".jpg"
+			}
+			path := filepath.Join(dir, fmt.Sprintf("%d%s", i, ext))
+			data := tt.card
+			if err := generator.GenerateCard(&data, path); err != nil {
+				t.Fatalf("GenerateCard() error = %v", err)
+			}
+			if artProc.renders != tt.wantRenders {
+				t.Errorf("renders = %d, want %d", artProc.renders, tt.wantRenders)
+			}
+
+			written, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("failed to read output: %v", err)
+			}
+			if i == 0 {
+				first = written
+			} else if tt.wantRenders == 1 && !bytes.Equal(written, first) {
+				t.Error("cached output differs from the first render")
+			}
+		})
+	}
+}
+
+func TestGenerateCardOptions(t *testing.T) {
+	generator, err := NewCardGeneratorWithConfig(&Config{
+		ArtProc: &countingArt{},
+		Output:  output.Options{Format: output.FormatJPEG, Width: 150, DPI: 300},
+	})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	data := &card.CardDTO{Type: card.TypeSpell, Name: "Fire Bolt", Cost: 1, Effect: "Deal 3 damage to any target."}
+
+	tests := []struct {
+		name       string
+		path       string
+		opts       *output.Options // nil uses the configured options
+		wantFormat string
+		wantSize   image.Point
+	}{
+		{"Configured format and size", "card", nil, "jpeg", image.Pt(150, 210)},
+		{"Extension selects the format", "card.webp", nil, "webp", image.Pt(150, 210)},
+		{"Options per call", "card.out", &output.Options{Format: output.FormatPNG, Height: 105}, "png", image.Pt(75, 105)},
+	}
+
+	dir := t.TempDir()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(dir, tt.path)
+			if tt.opts != nil {
+				err = generator.GenerateCardWithOptions(data, path, *tt.opts)
+			} else {
+				err = generator.GenerateCard(data, path)
+			}
+			if err != nil {
+				t.Fatalf("GenerateCard() error = %v", err)
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				t.Fatalf("failed to open output: %v", err)
+			}
+			defer f.Close()
+			config, format, err := image.DecodeConfig(f)
+			if err != nil {
+				t.Fatalf("failed to decode output: %v", err)
+			}
+			if format != tt.wantFormat || config.Width != tt.wantSize.X || config.Height != tt.wantSize.Y {
+				t.Errorf("output = %s %dx%d, want %s %v", format, config.Width, config.Height, tt.wantFormat, tt.wantSize)
+			}
+		})
+	}
+
+	if _, err := NewCardGeneratorWithConfig(&Config{Output: output.Options{Format: "gif"}}); err == nil {
+		t.Error("NewCardGeneratorWithConfig() with an unsupported format should fail")
+	}
+}
+
+func TestRenderInMemory(t *testing.T) {
+	generator, err := NewCardGeneratorWithConfig(&Config{ArtProc: &countingArt{}})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	data := &card.CardDTO{Type: card.TypeSpell, Name: "Fire Bolt", Cost: 1, Effect: "Deal 3 damage to any target."}
+
+	img, err := generator.Render(data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if got := img.Bounds().Size(); got != image.Pt(1500, 2100) {
+		t.Errorf("Render() size = %v, want the frame's 1500x2100", got)
+	}
+
+	var buf bytes.Buffer
+	if err := generator.RenderTo(&buf, data, output.Options{Format: output.FormatJPEG, Width: 150}); err != nil {
+		t.Fatalf("RenderTo() error = %v", err)
+	}
+	config, format, err := image.DecodeConfig(&buf)
+	if err != nil {
+		t.Fatalf("failed to decode RenderTo() output: %v", err)
+	}
+	if format != "jpeg" || config.Width != 150 || config.Height != 210 {
+		t.Errorf("RenderTo() wrote %s %dx%d, want jpeg 150x210", format, config.Width, config.Height)
+	}
+
+	if _, err := generator.Render(&card.CardDTO{Type: card.TypeSpell}); err == nil {
+		t.Error("Render() of an invalid card should fail")
+	}
+	if err := generator.RenderTo(&bytes.Buffer{}, data, output.Options{Quality: 101}); err == nil {
+		t.Error("RenderTo() with invalid options should fail")
+	}
+}
+
+func TestRenderLayout(t *testing.T) {
+	generator, err := NewCardGeneratorWithConfig(&Config{ArtProc: &countingArt{}})
+	if err != nil {
+		t.Fatalf("Failed to create generator: %v", err)
+	}
+	data := &card.CardDTO{Type: card.TypeIncantation, Name: "Veil", Cost: 2, Effect: "Draw a card."}
+
+	plain, err := generator.Render(data)
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	data.Metadata = map[string]string{"frame": "SpecialIncantation"}
+	framed, err := generator.Render(data)
+	if err != nil {
+		t.Fatalf("Render() with a layout error = %v", err)
+	}
+	if bytes.Equal(plain.(*image.RGBA).Pix, framed.(*image.RGBA).Pix) {
+		t.Error("rendering with the SpecialIncantation layout should change the card")
+	}
+
+	data.Metadata["frame"] = "Missing"
+	if _, err := generator.Render(data); err == nil {
+		t.Error("Render() with an unknown layout should fail")
+	}
+}
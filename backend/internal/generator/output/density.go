@@ -0,0 +1,85 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"math"
+)
+
+// pngHeaderSize is the length of the PNG signature and IHDR chunk, after which pHYs is inserted
+const pngHeaderSize = 8 + 4 + 4 + 13 + 4
+
+// withPNGDensity inserts a pHYs chunk recording the resolution in pixels per metre
+func withPNGDensity(data []byte, dpi int) []byte {
+	if dpi <= 0 || len(data) < pngHeaderSize || !bytes.Equal(data[12:16], []byte("IHDR")) {
+		return data
+	}
+	ppm := uint32(math.Round(float64(dpi) / 0.0254))
+
+	chunk := make([]byte, 4+4+9+4)
+	binary.BigEndian.PutUint32(chunk, 9)
+	copy(chunk[4:], "pHYs")
+	binary.BigEndian.PutUint32(chunk[8:], ppm)
+	binary.BigEndian.PutUint32(chunk[12:], ppm)
+	chunk[16] = 1 // unit: metre
+	binary.BigEndian.PutUint32(chunk[17:], crc32.ChecksumIEEE(chunk[4:17]))
+
+	out := make([]byte, 0, len(data)+len(chunk))
+	out = append(out, data[:pngHeaderSize]...)
+	out = append(out, chunk...)
+	return append(out, data[pngHeaderSize:]...)
+}
+
+// withJFIFDensity inserts a JFIF APP0 segment recording the resolution in dots per inch after the start-of-image marker
+func withJFIFDensity(data []byte, dpi int) []byte {
+	if dpi <= 0 || len(data) < 2 || data[0] != 0xff || data[1] != 0xd8 {
+		return data
+	}
+	density := uint16(min(dpi, math.MaxUint16))
+
+	segment := []byte{
+		0xff, 0xe0, 0, 16, // APP0 and its length
+		'J', 'F', 'I', 'F', 0,
+		1, 1, // version 1.01
+		1, // units: dots per inch
+		byte(density >> 8), byte(density), byte(density >> 8), byte(density),
+		0, 0, // no thumbnail
+	}
+
+	out := make([]byte, 0, len(data)+len(segment))
+	out = append(out, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+// exifDensity builds a little-endian TIFF header holding only the X and Y resolution in dots per inch
+func exifDensity(dpi int) []byte {
+	const (
+		ifdOffset      = 8
+		entries        = 3
+		rationalOffset = ifdOffset + 2 + entries*12 + 4
+	)
+	le := binary.LittleEndian
+	data := make([]byte, rationalOffset+16)
+	copy(data, "II*\x00")
+	le.PutUint32(data[4:], ifdOffset)
+	le.PutUint16(data[ifdOffset:], entries)
+
+	entry := func(i int, tag, typ uint16, value uint32) {
+		e := data[ifdOffset+2+i*12:]
+		le.PutUint16(e, tag)
+		le.PutUint16(e[2:], typ)
+		le.PutUint32(e[4:], 1)
+		le.PutUint32(e[8:], value)
+	}
+	entry(0, 0x011a, 5, rationalOffset)   // XResolution, RATIONAL
+	entry(1, 0x011b, 5, rationalOffset+8) // YResolution, RATIONAL
+	entry(2, 0x0128, 3, 2)                // ResolutionUnit, SHORT: inches
+
+	for i := 0; i < 2; i++ {
+		le.PutUint32(data[rationalOffset+i*8:], uint32(dpi))
+		le.PutUint32(data[rationalOffset+i*8+4:], 1)
+	}
+	return data
+}
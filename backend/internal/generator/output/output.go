@@ -0,0 +1,224 @@
+// Package output encodes rendered cards in the configured image format, size and resolution
+package output
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path/filepath"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// Format is an output image format
+type Format string
+
+const (
+	FormatPNG  Format = "png"
+	FormatJPEG Format = "jpeg"
+	FormatWebP Format = "webp" // lossless
+)
+
+// ParseFormat parses a format name such as "png", "jpg" or "webp"
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "png":
+		return FormatPNG, nil
+	case "jpeg", "jpg":
+		return FormatJPEG, nil
+	case "webp":
+		return FormatWebP, nil
+	default:
+		return "", fmt.Errorf("unsupported output format: %s", name)
+	}
+}
+
+// FormatFromPath returns the format named by a file's extension
+func FormatFromPath(path string) (Format, bool) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", false
+	}
+	format, err := ParseFormat(ext)
+	return format, err == nil
+}
+
+// Extension returns the file extension of the format, including the dot
+func (f Format) Extension() string {
+	switch f {
+	case FormatJPEG:
+		return ".jpg"
+	case FormatWebP:
+		return ".webp"
+	default:
+		return ".png"
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJPEG:
+		return "image/jpeg"
+	case FormatWebP:
+		return "image/webp"
+	default:
+		return "image/png"
+	}
+}
+
+// Output limits keep a request from allocating an image too large to hold in memory
+const (
+	MaxDimension = 16384      // pixels per side
+	MaxPixels    = 50_000_000 // width times height, about 200 MB as RGBA
+	MaxDPI       = 2400
+)
+
+// Options controls how a card image is encoded. The zero value writes a PNG at the render's native size.
+type Options struct {
+	Format  Format // PNG when empty
+	Width   int    // output width in pixels; 0 follows Height's scale, or keeps the native width
+	Height  int    // output height in pixels; 0 follows Width's scale, or keeps the native height
+	Quality int    // JPEG quality from 1 to 100; 0 uses the encoder default. PNG and WebP are lossless.
+	DPI     int    // resolution recorded in the file; 0 records none
+}
+
+// Validate checks that the options can be encoded
+func (o Options) Validate() error {
+	if o.Format != "" {
+		if _, err := ParseFormat(string(o.Format)); err != nil {
+			return err
+		}
+	}
+	if o.Width < 0 || o.Height < 0 {
+		return fmt.Errorf("invalid output size: %dx%d", o.Width, o.Height)
+	}
+	if err := checkSize(o.Width, o.Height); err != nil {
+		return err
+	}
+	if o.Quality < 0 || o.Quality > 100 {
+		return fmt.Errorf("invalid quality: %d", o.Quality)
+	}
+	if o.DPI < 0 || o.DPI > MaxDPI {
+		return fmt.Errorf("invalid DPI: %d, must be between 0 and %d", o.DPI, MaxDPI)
+	}
+	return nil
+}
+
+// checkSize rejects output sizes over MaxDimension per side or MaxPixels in total
+func checkSize(width, height int) error {
+	if width > MaxDimension || height > MaxDimension {
+		return fmt.Errorf("output size %dx%d exceeds %d pixels per side", width, height, MaxDimension)
+	}
+	if int64(width)*int64(height) > MaxPixels {
+		return fmt.Errorf("output size %dx%d exceeds %d pixels", width, height, MaxPixels)
+	}
+	return nil
+}
+
+// ForPath returns the options with the format set by the path's extension, when it names one
+func (o Options) ForPath(path string) Options {
+	if format, ok := FormatFromPath(path); ok {
+		o.Format = format
+	}
+	return o
+}
+
+// format returns the format to encode, normalizing aliases such as "jpg"
+func (o Options) format() Format {
+	format, err := ParseFormat(string(o.Format))
+	if err != nil {
+		return FormatPNG
+	}
+	return format
+}
+
+// String identifies the options, e.g. for cache keys
+func (o Options) String() string {
+	return fmt.Sprintf("%s %dx%d q%d %ddpi", o.format(), o.Width, o.Height, o.Quality, o.DPI)
+}
+
+// Size returns the output size of an image with the given bounds
+func (o Options) Size(bounds image.Rectangle) (int, int) {
+	w, h := bounds.Dx(), bounds.Dy()
+	switch {
+	case o.Width > 0 && o.Height > 0:
+		return o.Width, o.Height
+	case o.Width > 0 && w > 0:
+		return o.Width, max(1, (h*o.Width+w/2)/w)
+	case o.Height > 0 && h > 0:
+		return max(1, (w*o.Height+h/2)/h), o.Height
+	}
+	return w, h
+}
+
+// Encode scales the image to the configured size and writes it in the configured format.
+// A size derived from a single dimension is checked against the output limits before scaling.
+func Encode(w io.Writer, img image.Image, opts Options) error {
+	if err := opts.Validate(); err != nil {
+		return err
+	}
+	if err := checkSize(opts.Size(img.Bounds())); err != nil {
+		return err
+	}
+	img = Scale(img, opts)
+
+	var (
+		buf  bytes.Buffer
+		data []byte
+	)
+	switch opts.format() {
+	case FormatJPEG:
+		if err := jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: quality(opts.Quality)}); err != nil {
+			return fmt.Errorf("failed to encode JPEG: %w", err)
+		}
+		data = withJFIFDensity(buf.Bytes(), opts.DPI)
+	case FormatWebP:
+		if err := encodeWebP(&buf, img, opts.DPI); err != nil {
+			return fmt.Errorf("failed to encode WebP: %w", err)
+		}
+		data = buf.Bytes()
+	default:
+		encoder := png.Encoder{CompressionLevel: png.BestCompression}
+		if err := encoder.Encode(&buf, img); err != nil {
+			return fmt.Errorf("failed to encode PNG: %w", err)
+		}
+		data = withPNGDensity(buf.Bytes(), opts.DPI)
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// Scale resizes the image to the output size with Catmull-Rom resampling; images already at that size are returned as is
+func Scale(img image.Image, opts Options) image.Image {
+	b := img.Bounds()
+	w, h := opts.Size(b)
+	if w == b.Dx() && h == b.Dy() {
+		return img
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	xdraw.CatmullRom.Scale(dst, dst.Bounds(), img, b, xdraw.Src, nil)
+	return dst
+}
+
+// flatten composites the image over white, since JPEG has no transparency
+func flatten(img image.Image) image.Image {
+	b := img.Bounds()
+	dst := image.NewRGBA(b)
+	draw.Draw(dst, b, image.White, image.Point{}, draw.Src)
+	draw.Draw(dst, b, img, b.Min, draw.Over)
+	return dst
+}
+
+// quality returns the JPEG quality to encode at
+func quality(q int) int {
+	if q == 0 {
+		return jpeg.DefaultQuality
+	}
+	return q
+}
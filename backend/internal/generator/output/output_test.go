@@ -0,0 +1,181 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"math/rand"
+	"testing"
+
+	"golang.org/x/image/webp"
+)
+
+// card draws a small card-like image: a flat frame, a gradient and a transparent corner
+func card(w, h int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			c := color.NRGBA{40, 30, 20, 255}
+			if x > w/8 && x < w-w/8 && y > h/8 && y < h/2 {
+				c = color.NRGBA{uint8(x * 255 / w), uint8(y * 255 / h), 128, 255}
+			}
+			if x < 2 && y < 2 {
+				c = color.NRGBA{}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+// noise draws random pixels, including random alpha
+func noise(w, h int) *image.NRGBA {
+	rng := rand.New(rand.NewSource(1))
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	rng.Read(img.Pix)
+	for i := 3; i < len(img.Pix); i += 4 {
+		if img.Pix[i] == 0 {
+			img.Pix[i-3], img.Pix[i-2], img.Pix[i-1] = 0, 0, 0 // transparent pixels carry no color
+		}
+	}
+	return img
+}
+
+func TestWebPLossless(t *testing.T) {
+	tests := []struct {
+		name string
+		img  *image.NRGBA
+	}{
+		{"Single pixel", noise(1, 1)},
+		{"Single column", noise(1, 37)},
+		{"Single row", card(53, 1)},
+		{"Flat", image.NewNRGBA(image.Rect(0, 0, 40, 40))},
+		{"Card", card(150, 210)},
+		{"Noise", noise(67, 45)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, tt.img, Options{Format: FormatWebP}); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			decoded, err := webp.Decode(&buf)
+			if err != nil {
+				t.Fatalf("webp.Decode() error = %v", err)
+			}
+			if decoded.Bounds() != tt.img.Bounds() {
+				t.Fatalf("decoded bounds = %v, want %v", decoded.Bounds(), tt.img.Bounds())
+			}
+			got := decoded.(*image.NRGBA)
+			if !bytes.Equal(got.Pix, tt.img.Pix) {
+				t.Error("decoded pixels differ from the original")
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	src := card(150, 210)
+
+	tests := []struct {
+		name     string
+		opts     Options
+		wantSize image.Point
+		decode   func(*bytes.Reader) (image.Image, error)
+		density  func(t *testing.T, data []byte) int
+	}{
+		{"PNG at native size", Options{}, image.Pt(150, 210), decodePNG, nil},
+		{"PNG resized with DPI", Options{Format: FormatPNG, Width: 75, Height: 105, DPI: 300}, image.Pt(75, 105), decodePNG, pngDensity},
+		{"JPEG keeps the aspect ratio", Options{Format: "jpg", Width: 100, Quality: 90, DPI: 150}, image.Pt(100, 140), decodeJPEG, jfifDensity},
+		{"WebP with DPI", Options{Format: FormatWebP, Height: 105, DPI: 600}, image.Pt(75, 105), decodeWebP, exifDensityOf},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := Encode(&buf, src, tt.opts); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+			img, err := tt.decode(bytes.NewReader(buf.Bytes()))
+			if err != nil {
+				t.Fatalf("decode error = %v", err)
+			}
+			if got := img.Bounds().Size(); got != tt.wantSize {
+				t.Errorf("size = %v, want %v", got, tt.wantSize)
+			}
+			if tt.density != nil {
+				if got := tt.density(t, buf.Bytes()); got != tt.opts.DPI {
+					t.Errorf("recorded DPI = %d, want %d", got, tt.opts.DPI)
+				}
+			}
+		})
+	}
+
+	invalid := []Options{
+		{Format: "gif"}, {Width: -1}, {Quality: 101}, {DPI: -3}, {DPI: MaxDPI + 1},
+		{Width: MaxDimension + 1}, {Width: 10000, Height: 10000},
+		{Width: 12000}, // the derived height exceeds MaxDimension
+	}
+	for _, opts := range invalid {
+		if err := Encode(&bytes.Buffer{}, src, opts); err == nil {
+			t.Errorf("Encode() with %+v should fail", opts)
+		}
+	}
+}
+
+func TestOptionsForPath(t *testing.T) {
+	opts := Options{Format: FormatWebP, Width: 10}
+	tests := []struct {
+		path string
+		want Format
+	}{
+		{"card.png", FormatPNG},
+		{"card.JPG", FormatJPEG},
+		{"card.jpeg", FormatJPEG},
+		{"card", FormatWebP},
+		{"card.tmp", FormatWebP},
+	}
+	for _, tt := range tests {
+		got := opts.ForPath(tt.path)
+		if got.Format != tt.want || got.Width != 10 {
+			t.Errorf("ForPath(%q) = %+v, want format %s", tt.path, got, tt.want)
+		}
+	}
+}
+
+func decodePNG(r *bytes.Reader) (image.Image, error)  { return png.Decode(r) }
+func decodeJPEG(r *bytes.Reader) (image.Image, error) { return jpeg.Decode(r) }
+func decodeWebP(r *bytes.Reader) (image.Image, error) { return webp.Decode(r) }
+
+// pngDensity reads the pHYs chunk, converting pixels per metre back to DPI
+func pngDensity(t *testing.T, data []byte) int {
+	i := bytes.Index(data, []byte("pHYs"))
+	if i < 0 || data[i+12] != 1 {
+		t.Fatal("no pHYs chunk in metres")
+	}
+	ppm := binary.BigEndian.Uint32(data[i+4:])
+	return int(float64(ppm)*0.0254 + 0.5)
+}
+
+// jfifDensity reads the density of the JFIF segment following the start-of-image marker
+func jfifDensity(t *testing.T, data []byte) int {
+	if !bytes.Equal(data[2:4], []byte{0xff, 0xe0}) || !bytes.Equal(data[6:11], []byte("JFIF\x00")) || data[13] != 1 {
+		t.Fatal("no JFIF segment in dots per inch")
+	}
+	return int(binary.BigEndian.Uint16(data[14:]))
+}
+
+// exifDensityOf reads the X resolution from the EXIF chunk
+func exifDensityOf(t *testing.T, data []byte) int {
+	i := bytes.Index(data, []byte("EXIF"))
+	if i < 0 || !bytes.Equal(data[12:16], []byte("VP8X")) || data[20]&vp8xEXIF == 0 {
+		t.Fatal("no EXIF chunk announced by VP8X")
+	}
+	tiff := data[i+8:]
+	offset := binary.LittleEndian.Uint32(tiff[8+2+8:]) // value of the first IFD entry
+	return int(binary.LittleEndian.Uint32(tiff[offset:]))
+}
@@ -0,0 +1,642 @@
+package output
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math/bits"
+	"sort"
+)
+
+// VP8L bitstream constants, see the WebP lossless bitstream specification
+const (
+	vp8lSignature = 0x2f
+	vp8lMaxSize   = 1 << 14
+
+	transformPredictor     = 0
+	transformSubtractGreen = 2
+
+	predictorBits = 4 // predictor modes are chosen per 16x16 block
+
+	numLiterals      = 256
+	numLengthCodes   = 24
+	numDistanceCodes = 40
+	maxLength        = 4096
+	maxDistance      = 1<<20 - distanceMapSize
+	distanceMapSize  = 120 // distance codes below this refer to nearby pixels
+
+	maxCodeLength           = 15
+	maxCodeLengthCodeLength = 7
+)
+
+// codeLengthCodeOrder is the order code length code lengths are written in
+var codeLengthCodeOrder = [19]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+
+// predictorModes are the candidate predictors tried for each block: L, T, Average2(L, T), Select and ClampAddSubtractFull
+var predictorModes = []uint32{1, 2, 7, 11, 12}
+
+// encodeVP8L writes the image as a lossless VP8L bitstream. Pixels are stored with the
+// subtract green and predictor transforms, LZ77 backward references and prefix codes.
+func encodeVP8L(w io.Writer, img image.Image) error {
+	b := img.Bounds()
+	width, height := b.Dx(), b.Dy()
+	if width < 1 || height < 1 || width > vp8lMaxSize || height > vp8lMaxSize {
+		return fmt.Errorf("image size %dx%d is outside WebP's 1 to %d pixel limit", width, height, vp8lMaxSize)
+	}
+	argb, hasAlpha := argbPixels(img)
+
+	bw := &bitWriter{}
+	bw.writeBits(vp8lSignature, 8)
+	bw.writeBits(uint32(width-1), 14)
+	bw.writeBits(uint32(height-1), 14)
+	bw.writeBits(boolBit(hasAlpha), 1)
+	bw.writeBits(0, 3) // version
+
+	// Transforms are listed in the order they are applied; the decoder undoes them in reverse
+	bw.writeBits(1, 1)
+	bw.writeBits(transformSubtractGreen, 2)
+	subtractGreen(argb)
+
+	bw.writeBits(1, 1)
+	bw.writeBits(transformPredictor, 2)
+	bw.writeBits(predictorBits-2, 3)
+	modes, tilesPerRow := choosePredictors(argb, width, height)
+	writeEntropyImage(bw, modes, tilesPerRow, false)
+	residuals := predict(argb, width, height, modes, tilesPerRow)
+
+	bw.writeBits(0, 1) // no more transforms
+	writeEntropyImage(bw, residuals, width, true)
+
+	_, err := w.Write(bw.bytes())
+	return err
+}
+
+// argbPixels returns the image's non-premultiplied pixels as packed ARGB, and whether any is not opaque
+func argbPixels(img image.Image) ([]uint32, bool) {
+	b := img.Bounds()
+	argb := make([]uint32, 0, b.Dx()*b.Dy())
+	hasAlpha := false
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			if c.A != 0xff {
+				hasAlpha = true
+			}
+			argb = append(argb, uint32(c.A)<<24|uint32(c.R)<<16|uint32(c.G)<<8|uint32(c.B))
+		}
+	}
+	return argb, hasAlpha
+}
+
+// subtractGreen subtracts each pixel's green from its red and blue
+func subtractGreen(argb []uint32) {
+	for i, p := range argb {
+		green := (p >> 8) & 0xff
+		redBlue := (p & 0x00ff00ff) + 0xff00ff00 - (green<<16 | green)
+		argb[i] = p&0xff00ff00 | redBlue&0x00ff00ff
+	}
+}
+
+// choosePredictors picks the predictor with the smallest residuals for each block. The
+// modes are returned as an image with one pixel per block, the mode in its green channel.
+func choosePredictors(argb []uint32, width, height int) ([]uint32, int) {
+	tilesPerRow := tiles(width)
+	modes := make([]uint32, tilesPerRow*tiles(height))
+	for ty := 0; ty < tiles(height); ty++ {
+		for tx := 0; tx < tilesPerRow; tx++ {
+			best, bestCost := predictorModes[0], -1
+			for _, mode := range predictorModes {
+				cost := 0
+				for y := ty << predictorBits; y < min(height, (ty+1)<<predictorBits); y++ {
+					for x := tx << predictorBits; x < min(width, (tx+1)<<predictorBits); x++ {
+						i := y*width + x
+						cost += residualCost(subPixels(argb[i], predictPixel(argb, i, width, x, y, mode)))
+					}
+				}
+				if bestCost < 0 || cost < bestCost {
+					best, bestCost = mode, cost
+				}
+			}
+			modes[ty*tilesPerRow+tx] = best << 8
+		}
+	}
+	return modes, tilesPerRow
+}
+
+// predict replaces each pixel with its difference from the prediction of its block's mode
+func predict(argb []uint32, width, height int, modes []uint32, tilesPerRow int) []uint32 {
+	residuals := make([]uint32, len(argb))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			i := y*width + x
+			mode := (modes[(y>>predictorBits)*tilesPerRow+x>>predictorBits] >> 8) & 0xf
+			residuals[i] = subPixels(argb[i], predictPixel(argb, i, width, x, y, mode))
+		}
+	}
+	return residuals
+}
+
+// predictPixel predicts pixel i from its left, top, top-left and top-right neighbours.
+// The top row always predicts from the left and the left column from the top.
+func predictPixel(argb []uint32, i, width, x, y int, mode uint32) uint32 {
+	switch {
+	case i == 0:
+		return 0xff000000
+	case y == 0:
+		return argb[i-1]
+	case x == 0:
+		return argb[i-width]
+	}
+	left, top, topLeft := argb[i-1], argb[i-width], argb[i-width-1]
+	switch mode {
+	case 1:
+		return left
+	case 2:
+		return top
+	case 7:
+		return average2(left, top)
+	case 11:
+		return selectPixel(left, top, topLeft)
+	case 12:
+		return clampAddSubtractFull(left, top, topLeft)
+	}
+	return 0xff000000
+}
+
+// average2 averages each channel of two pixels, rounding down
+func average2(a, b uint32) uint32 {
+	return (((a ^ b) & 0xfefefefe) >> 1) + (a & b)
+}
+
+// selectPixel returns whichever of left and top is closer to the gradient estimate left + top - topLeft
+func selectPixel(left, top, topLeft uint32) uint32 {
+	distLeft, distTop := 0, 0
+	for shift := 0; shift < 32; shift += 8 {
+		l, t, tl := int(left>>shift&0xff), int(top>>shift&0xff), int(topLeft>>shift&0xff)
+		distLeft += abs(t - tl)
+		distTop += abs(l - tl)
+	}
+	if distLeft < distTop {
+		return left
+	}
+	return top
+}
+
+// clampAddSubtractFull computes left + top - topLeft per channel, clamped to a byte
+func clampAddSubtractFull(left, top, topLeft uint32) uint32 {
+	var p uint32
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(left>>shift&0xff) + int(top>>shift&0xff) - int(topLeft>>shift&0xff)
+		p |= uint32(min(255, max(0, v))) << shift
+	}
+	return p
+}
+
+// subPixels subtracts two pixels channel by channel, modulo 256
+func subPixels(a, b uint32) uint32 {
+	alphaGreen := 0x00ff00ff + (a & 0xff00ff00) - (b & 0xff00ff00)
+	redBlue := 0xff00ff00 + (a & 0x00ff00ff) - (b & 0x00ff00ff)
+	return alphaGreen&0xff00ff00 | redBlue&0x00ff00ff
+}
+
+// residualCost estimates the cost of coding a residual as the sum of its channels' distances from zero
+func residualCost(p uint32) int {
+	cost := 0
+	for shift := 0; shift < 32; shift += 8 {
+		v := int(p >> shift & 0xff)
+		cost += min(v, 256-v)
+	}
+	return cost
+}
+
+// tiles returns the number of predictor blocks covering a length
+func tiles(n int) int {
+	return (n + 1<<predictorBits - 1) >> predictorBits
+}
+
+// token is a literal pixel, or a backward reference copying length pixels from distance pixels back
+type token struct {
+	pixel    uint32
+	length   int
+	distance int
+}
+
+// writeEntropyImage writes pixels coded with one group of prefix codes and no color cache.
+// The main image also signals that it has no meta prefix codes.
+func writeEntropyImage(bw *bitWriter, argb []uint32, width int, main bool) {
+	bw.writeBits(0, 1) // no color cache
+	if main {
+		bw.writeBits(0, 1) // one prefix code group for the whole image
+	}
+
+	tokens := backwardReferences(argb, width)
+	green := make([]int, numLiterals+numLengthCodes)
+	red := make([]int, numLiterals)
+	blue := make([]int, numLiterals)
+	alpha := make([]int, numLiterals)
+	dist := make([]int, numDistanceCodes)
+	for _, t := range tokens {
+		if t.length == 0 {
+			green[t.pixel>>8&0xff]++
+			red[t.pixel>>16&0xff]++
+			blue[t.pixel&0xff]++
+			alpha[t.pixel>>24]++
+			continue
+		}
+		code, _, _ := prefixEncode(t.length)
+		green[numLiterals+code]++
+		code, _, _ = prefixEncode(distanceCode(t.distance, width))
+		dist[code]++
+	}
+
+	greenCode := writePrefixCode(bw, green)
+	redCode := writePrefixCode(bw, red)
+	blueCode := writePrefixCode(bw, blue)
+	alphaCode := writePrefixCode(bw, alpha)
+	distCode := writePrefixCode(bw, dist)
+
+	for _, t := range tokens {
+		if t.length == 0 {
+			greenCode.write(bw, int(t.pixel>>8&0xff))
+			redCode.write(bw, int(t.pixel>>16&0xff))
+			blueCode.write(bw, int(t.pixel&0xff))
+			alphaCode.write(bw, int(t.pixel>>24))
+			continue
+		}
+		code, n, extra := prefixEncode(t.length)
+		greenCode.write(bw, numLiterals+code)
+		bw.writeBits(uint32(extra), uint(n))
+		code, n, extra = prefixEncode(distanceCode(t.distance, width))
+		distCode.write(bw, code)
+		bw.writeBits(uint32(extra), uint(n))
+	}
+}
+
+// backwardReferences finds repeated runs of pixels with hash chains, always trying the
+// pixels to the left and above first as they have the shortest distance codes
+func backwardReferences(argb []uint32, width int) []token {
+	const (
+		hashBits = 16
+		maxChain = 16
+		minMatch = 3
+	)
+	n := len(argb)
+	head := make([]int32, 1<<hashBits)
+	for i := range head {
+		head[i] = -1
+	}
+	prev := make([]int32, n)
+	hash := func(i int) uint32 {
+		return (argb[i]*0x1e35a7bd ^ argb[i+1]*0x9e3779b1) >> (32 - hashBits)
+	}
+	insert := func(i int) {
+		if i+1 < n {
+			h := hash(i)
+			prev[i] = head[h]
+			head[h] = int32(i)
+		}
+	}
+	matchLength := func(from, i int) int {
+		limit := min(maxLength, n-i)
+		l := 0
+		for l < limit && argb[from+l] == argb[i+l] {
+			l++
+		}
+		return l
+	}
+
+	tokens := make([]token, 0, n/4)
+	for i := 0; i < n; {
+		bestLength, bestDistance := 0, 0
+		for _, d := range [2]int{1, width} {
+			if d <= i {
+				if l := matchLength(i-d, i); l > bestLength {
+					bestLength, bestDistance = l, d
+				}
+			}
+		}
+		if i+1 < n && bestLength < maxLength {
+			for c, chain := head[hash(i)], 0; c >= 0 && chain < maxChain; c, chain = prev[c], chain+1 {
+				d := i - int(c)
+				if d > maxDistance {
+					break
+				}
+				if l := matchLength(int(c), i); l > bestLength {
+					bestLength, bestDistance = l, d
+				}
+			}
+		}
+
+		if bestLength >= minMatch {
+			tokens = append(tokens, token{length: bestLength, distance: bestDistance})
+			for j := i; j < i+bestLength; j++ {
+				insert(j)
+			}
+			i += bestLength
+			continue
+		}
+		tokens = append(tokens, token{pixel: argb[i]})
+		insert(i)
+		i++
+	}
+	return tokens
+}
+
+// distanceCode maps a backward reference distance to its code. The four nearest
+// neighbours have short codes; any other distance is offset past the neighbourhood map.
+func distanceCode(distance, width int) int {
+	switch distance {
+	case width:
+		return 1
+	case 1:
+		return 2
+	case width + 1:
+		return 3
+	case width - 1:
+		return 4
+	}
+	return distance + distanceMapSize
+}
+
+// prefixEncode splits a length or distance code into a prefix symbol and extra bits
+func prefixEncode(v int) (code, extraBits, extra int) {
+	v--
+	if v < 4 {
+		return v, 0, 0
+	}
+	highest := bits.Len(uint(v)) - 1
+	second := (v >> (highest - 1)) & 1
+	extraBits = highest - 1
+	return 2*highest + second, extraBits, v & (1<<extraBits - 1)
+}
+
+// prefixCode is a canonical prefix code, with the codes bit-reversed for writing least significant bit first
+type prefixCode struct {
+	lengths []uint8
+	codes   []uint16
+}
+
+// write writes a symbol's code
+func (c prefixCode) write(bw *bitWriter, symbol int) {
+	bw.writeBits(uint32(c.codes[symbol]), uint(c.lengths[symbol]))
+}
+
+// writePrefixCode writes the prefix code for a histogram of symbols and returns it. Up to two
+// symbols below 256 use the simple code; one symbol then takes no bits at all.
+func writePrefixCode(bw *bitWriter, histogram []int) prefixCode {
+	var symbols []int
+	for s, count := range histogram {
+		if count > 0 {
+			symbols = append(symbols, s)
+		}
+	}
+
+	if len(symbols) <= 2 && (len(symbols) == 0 || symbols[len(symbols)-1] < numLiterals) {
+		if len(symbols) == 0 {
+			symbols = []int{0}
+		}
+		code := prefixCode{lengths: make([]uint8, len(histogram)), codes: make([]uint16, len(histogram))}
+		bw.writeBits(1, 1) // simple code
+		bw.writeBits(uint32(len(symbols)-1), 1)
+		if symbols[0] < 2 {
+			bw.writeBits(0, 1)
+			bw.writeBits(uint32(symbols[0]), 1)
+		} else {
+			bw.writeBits(1, 1)
+			bw.writeBits(uint32(symbols[0]), 8)
+		}
+		if len(symbols) == 2 {
+			bw.writeBits(uint32(symbols[1]), 8)
+			code.lengths[symbols[0]], code.lengths[symbols[1]] = 1, 1
+			code.codes[symbols[1]] = 1
+		}
+		return code
+	}
+
+	lengths := huffmanLengths(histogram, maxCodeLength)
+	writeCodeLengths(bw, lengths)
+	return prefixCode{lengths: lengths, codes: canonicalCodes(lengths)}
+}
+
+// codeLengthToken is a code length symbol: a length from 0 to 15, or a repeat code with its extra bits
+type codeLengthToken struct {
+	symbol int
+	extra  int
+}
+
+// writeCodeLengths writes the lengths of a normal prefix code, run-length coded with the code length code
+func writeCodeLengths(bw *bitWriter, lengths []uint8) {
+	var tokens []codeLengthToken
+	for i := 0; i < len(lengths); {
+		v := lengths[i]
+		run := 1
+		for i+run < len(lengths) && lengths[i+run] == v {
+			run++
+		}
+		i += run
+
+		if v == 0 {
+			for run >= 11 {
+				k := min(run, 138)
+				tokens = append(tokens, codeLengthToken{18, k - 11})
+				run -= k
+			}
+			if run >= 3 {
+				tokens = append(tokens, codeLengthToken{17, run - 3})
+				run = 0
+			}
+		} else {
+			tokens = append(tokens, codeLengthToken{symbol: int(v)})
+			run--
+			for run >= 3 {
+				k := min(run, 6)
+				tokens = append(tokens, codeLengthToken{16, k - 3})
+				run -= k
+			}
+		}
+		for ; run > 0; run-- {
+			tokens = append(tokens, codeLengthToken{symbol: int(v)})
+		}
+	}
+
+	histogram := make([]int, len(codeLengthCodeOrder))
+	for _, t := range tokens {
+		histogram[t.symbol]++
+	}
+	codeLengths := huffmanLengths(histogram, maxCodeLengthCodeLength)
+	codes := canonicalCodes(codeLengths)
+
+	n := len(codeLengthCodeOrder)
+	for n > 4 && codeLengths[codeLengthCodeOrder[n-1]] == 0 {
+		n--
+	}
+	bw.writeBits(0, 1) // normal code
+	bw.writeBits(uint32(n-4), 4)
+	for _, symbol := range codeLengthCodeOrder[:n] {
+		bw.writeBits(uint32(codeLengths[symbol]), 3)
+	}
+	bw.writeBits(0, 1) // lengths are given for the whole alphabet
+
+	for _, t := range tokens {
+		bw.writeBits(uint32(codes[t.symbol]), uint(codeLengths[t.symbol]))
+		switch t.symbol {
+		case 16:
+			bw.writeBits(uint32(t.extra), 2)
+		case 17:
+			bw.writeBits(uint32(t.extra), 3)
+		case 18:
+			bw.writeBits(uint32(t.extra), 7)
+		}
+	}
+}
+
+// huffmanLengths returns Huffman code lengths no longer than limit. When the tree is too
+// deep, rare symbols are given larger counts until it fits. At least two symbols get a code,
+// so the tree is always complete.
+func huffmanLengths(histogram []int, limit int) []uint8 {
+	counts := make([]int, len(histogram))
+	used := 0
+	for s, count := range histogram {
+		if count > 0 {
+			counts[s] = count
+			used++
+		}
+	}
+	for s := 0; used < 2; s++ {
+		if counts[s] == 0 {
+			counts[s] = 1
+			used++
+		}
+	}
+
+	for floor := 1; ; floor *= 2 {
+		weights := make([]int, len(counts))
+		for s, count := range counts {
+			if count > 0 {
+				weights[s] = max(count, floor)
+			}
+		}
+		lengths := buildHuffman(weights)
+		longest := uint8(0)
+		for _, l := range lengths {
+			longest = max(longest, l)
+		}
+		if int(longest) <= limit {
+			return lengths
+		}
+	}
+}
+
+// buildHuffman computes Huffman code lengths for the symbols with a positive weight
+func buildHuffman(weights []int) []uint8 {
+	type node struct {
+		weight      int
+		left, right int // children, or -1 and the symbol for a leaf
+	}
+	var leaves []node
+	for s, w := range weights {
+		if w > 0 {
+			leaves = append(leaves, node{weight: w, left: -1, right: s})
+		}
+	}
+	sort.SliceStable(leaves, func(i, j int) bool { return leaves[i].weight < leaves[j].weight })
+
+	// Two queues: the sorted leaves and the internal nodes, which are created in weight order
+	nodes := append([]node(nil), leaves...)
+	internal := len(nodes)
+	nextLeaf, nextInternal := 0, internal
+	pop := func() int {
+		if nextLeaf < internal && (nextInternal >= len(nodes) || nodes[nextLeaf].weight <= nodes[nextInternal].weight) {
+			nextLeaf++
+			return nextLeaf - 1
+		}
+		nextInternal++
+		return nextInternal - 1
+	}
+	for i := 0; i < internal-1; i++ {
+		a, b := pop(), pop()
+		nodes = append(nodes, node{weight: nodes[a].weight + nodes[b].weight, left: a, right: b})
+	}
+
+	lengths := make([]uint8, len(weights))
+	type entry struct{ index, depth int }
+	stack := []entry{{len(nodes) - 1, 0}}
+	for len(stack) > 0 {
+		e := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		n := nodes[e.index]
+		if n.left < 0 {
+			lengths[n.right] = uint8(min(e.depth, 255))
+			continue
+		}
+		stack = append(stack, entry{n.left, e.depth + 1}, entry{n.right, e.depth + 1})
+	}
+	return lengths
+}
+
+// canonicalCodes assigns canonical codes to the lengths, shorter codes and lower symbols first
+func canonicalCodes(lengths []uint8) []uint16 {
+	var count [maxCodeLength + 1]int
+	for _, l := range lengths {
+		if l > 0 {
+			count[l]++
+		}
+	}
+	var next [maxCodeLength + 1]int
+	code := 0
+	for l := 1; l <= maxCodeLength; l++ {
+		code = (code + count[l-1]) << 1
+		next[l] = code
+	}
+
+	codes := make([]uint16, len(lengths))
+	for s, l := range lengths {
+		if l > 0 {
+			codes[s] = bits.Reverse16(uint16(next[l])) >> (16 - l)
+			next[l]++
+		}
+	}
+	return codes
+}
+
+// bitWriter packs values least significant bit first
+type bitWriter struct {
+	buf []byte
+	acc uint64
+	n   uint
+}
+
+// writeBits writes the low n bits of v, n at most 32
+func (b *bitWriter) writeBits(v uint32, n uint) {
+	b.acc |= uint64(v) << b.n
+	b.n += n
+	for b.n >= 8 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc >>= 8
+		b.n -= 8
+	}
+}
+
+// bytes returns the written bits, padding the last byte with zeros
+func (b *bitWriter) bytes() []byte {
+	if b.n > 0 {
+		b.buf = append(b.buf, byte(b.acc))
+		b.acc, b.n = 0, 0
+	}
+	return b.buf
+}
+
+func boolBit(b bool) uint32 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,62 @@
+package output
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"io"
+)
+
+// vp8xEXIF is the VP8X flag marking an EXIF chunk
+const vp8xEXIF = 1 << 3
+
+// encodeWebP writes a lossless WebP. A resolution is recorded in an EXIF chunk, which needs the extended (VP8X) layout.
+func encodeWebP(w io.Writer, img image.Image, dpi int) error {
+	var bitstream bytes.Buffer
+	if err := encodeVP8L(&bitstream, img); err != nil {
+		return err
+	}
+
+	var chunks bytes.Buffer
+	if dpi > 0 {
+		// The alpha flag is left unset: the VP8L bitstream carries its own alpha, and
+		// some decoders reject a VP8L image whose VP8X header announces a separate alpha chunk.
+		b := img.Bounds()
+		header := make([]byte, 10)
+		header[0] = vp8xEXIF
+		putUint24(header[4:], uint32(b.Dx()-1))
+		putUint24(header[7:], uint32(b.Dy()-1))
+		writeChunk(&chunks, "VP8X", header)
+		writeChunk(&chunks, "VP8L", bitstream.Bytes())
+		writeChunk(&chunks, "EXIF", exifDensity(dpi))
+	} else {
+		writeChunk(&chunks, "VP8L", bitstream.Bytes())
+	}
+
+	header := make([]byte, 12)
+	copy(header, "RIFF")
+	binary.LittleEndian.PutUint32(header[4:], uint32(4+chunks.Len()))
+	copy(header[8:], "WEBP")
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(chunks.Bytes())
+	return err
+}
+
+// writeChunk appends a RIFF chunk, padded to an even length
+func writeChunk(buf *bytes.Buffer, fourCC string, data []byte) {
+	var size [4]byte
+	binary.LittleEndian.PutUint32(size[:], uint32(len(data)))
+	buf.WriteString(fourCC)
+	buf.Write(size[:])
+	buf.Write(data)
+	if len(data)%2 == 1 {
+		buf.WriteByte(0)
+	}
+}
+
+// putUint24 writes a little-endian 24-bit value
+func putUint24(b []byte, v uint32) {
+	b[0], b[1], b[2] = byte(v), byte(v>>8), byte(v>>16)
+}
@@ -0,0 +1,156 @@
+// Package pdf lays out rendered cards on printable PDF sheets
+package pdf
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"strings"
+)
+
+// document writes PDF objects sequentially and records their offsets for the cross-reference table
+type document struct {
+	w       *bufio.Writer
+	written int64
+	offsets map[int]int64
+	nextID  int
+	err     error
+}
+
+// newDocument writes the PDF header; objects 1 and 2 are reserved for the catalog and page tree
+func newDocument(w io.Writer) *document {
+	d := &document{
+		w:       bufio.NewWriter(w),
+		offsets: make(map[int]int64),
+		nextID:  3,
+	}
+	// The binary comment marks the file as binary for transfer tools
+	d.printf("%%PDF-1.4\n%%\xe2\xe3\xcf\xd3\n")
+	return d
+}
+
+// catalogID and pagesID are the reserved object numbers of the catalog and page tree
+const (
+	catalogID = 1
+	pagesID   = 2
+)
+
+// reserve allocates an object number to be written later
+func (d *document) reserve() int {
+	id := d.nextID
+	d.nextID++
+	return id
+}
+
+// printf writes formatted output, remembering the first error
+func (d *document) printf(format string, args ...interface{}) {
+	if d.err != nil {
+		return
+	}
+	n, err := fmt.Fprintf(d.w, format, args...)
+	d.written += int64(n)
+	d.err = err
+}
+
+// write writes raw bytes, remembering the first error
+func (d *document) write(data []byte) {
+	if d.err != nil {
+		return
+	}
+	n, err := d.w.Write(data)
+	d.written += int64(n)
+	d.err = err
+}
+
+// object writes an object with a dictionary or other direct value
+func (d *document) object(id int, value string) {
+	d.offsets[id] = d.written
+	d.printf("%d 0 obj\n%s\nendobj\n", id, value)
+}
+
+// stream writes a stream object; dict holds the entries besides /Length
+func (d *document) stream(id int, dict string, data []byte) {
+	if dict != "" {
+		dict += " "
+	}
+	d.offsets[id] = d.written
+	d.printf("%d 0 obj\n<< %s/Length %d >>\nstream\n", id, dict, len(data))
+	d.write(data)
+	d.printf("\nendstream\nendobj\n")
+}
+
+// image writes an opaque RGB image XObject, compositing transparent pixels over white
+func (d *document) image(id int, img image.Image) {
+	b := img.Bounds()
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	row := make([]byte, b.Dx()*3)
+	rgba, _ := img.(*image.RGBA)
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		if rgba != nil {
+			// Premultiplied pixels over white only need the uncovered share of white added
+			pix := rgba.Pix[rgba.PixOffset(b.Min.X, y):]
+			for x := 0; x < b.Dx(); x++ {
+				white := 255 - pix[x*4+3]
+				row[x*3] = pix[x*4] + white
+				row[x*3+1] = pix[x*4+1] + white
+				row[x*3+2] = pix[x*4+2] + white
+			}
+			if _, err := zw.Write(row); err != nil && d.err == nil {
+				d.err = err
+			}
+			continue
+		}
+		for x := b.Min.X; x < b.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			i := (x - b.Min.X) * 3
+			row[i] = overWhite(c.R, c.A)
+			row[i+1] = overWhite(c.G, c.A)
+			row[i+2] = overWhite(c.B, c.A)
+		}
+		if _, err := zw.Write(row); err != nil && d.err == nil {
+			d.err = err
+		}
+	}
+	if err := zw.Close(); err != nil && d.err == nil {
+		d.err = err
+	}
+
+	d.stream(id, fmt.Sprintf("/Type /XObject /Subtype /Image /Width %d /Height %d /ColorSpace /DeviceRGB /BitsPerComponent 8 /Filter /FlateDecode",
+		b.Dx(), b.Dy()), buf.Bytes())
+}
+
+// overWhite blends a non-premultiplied channel over a white background
+func overWhite(v, a uint8) uint8 {
+	return uint8((int(v)*int(a) + 255*(255-int(a)) + 127) / 255)
+}
+
+// close writes the page tree, catalog, cross-reference table and trailer
+func (d *document) close(pages []int, mediaBox string) error {
+	kids := make([]string, len(pages))
+	for i, id := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", id)
+	}
+	d.object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox %s >>", strings.Join(kids, " "), len(pages), mediaBox))
+	d.object(catalogID, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+
+	xref := d.written
+	d.printf("xref\n0 %d\n0000000000 65535 f \n", d.nextID)
+	for id := 1; id < d.nextID; id++ {
+		if offset, ok := d.offsets[id]; ok {
+			d.printf("%010d 00000 n \n", offset)
+		} else {
+			d.printf("0000000000 65535 f \n") // reserved but never written
+		}
+	}
+	d.printf("trailer\n<< /Size %d /Root %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", d.nextID, catalogID, xref)
+
+	if d.err != nil {
+		return d.err
+	}
+	return d.w.Flush()
+}
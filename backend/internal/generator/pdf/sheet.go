@@ -0,0 +1,458 @@
+package pdf
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"image"
+	"image/draw"
+	"io"
+	"math"
+	"strings"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// PointsPerInch converts inches to PDF points
+const PointsPerInch = 72.0
+
+// PageSize is a sheet size in points
+type PageSize struct {
+	Name          string
+	Width, Height float64
+}
+
+var (
+	Letter = PageSize{Name: "letter", Width: 612, Height: 792}
+	A4     = PageSize{Name: "a4", Width: 595.28, Height: 841.89}
+)
+
+// ParsePageSize returns the page size for "letter" or "a4"
+func ParsePageSize(name string) (PageSize, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "letter":
+		return Letter, nil
+	case "a4":
+		return A4, nil
+	default:
+		return PageSize{}, fmt.Errorf("unsupported page size: %s", name)
+	}
+}
+
+// Duplex is how sheets are flipped when printing backs on the reverse side
+type Duplex string
+
+const (
+	DuplexNone      Duplex = "none"       // no back pages
+	DuplexLongEdge  Duplex = "long-edge"  // flipped left to right; back columns are mirrored
+	DuplexShortEdge Duplex = "short-edge" // flipped top to bottom; back rows are mirrored
+)
+
+// ParseDuplex returns the duplex mode for "none", "long-edge" or "short-edge"
+func ParseDuplex(name string) (Duplex, error) {
+	switch Duplex(strings.ToLower(strings.TrimSpace(name))) {
+	case "", DuplexNone:
+		return DuplexNone, nil
+	case DuplexLongEdge:
+		return DuplexLongEdge, nil
+	case DuplexShortEdge:
+		return DuplexShortEdge, nil
+	default:
+		return "", fmt.Errorf("unsupported duplex mode: %s", name)
+	}
+}
+
+// Layout places cards on a sheet. Lengths are in points.
+type Layout struct {
+	Page       PageSize
+	CardWidth  float64 // trim size of a card
+	CardHeight float64
+	Columns    int
+	Rows       int
+	Bleed      float64 // art extended past the trim edge; between cards it is limited to half the gutter
+	Gutter     float64 // space between the trim edges of neighbouring cards
+	CropMarks  bool
+	DPI        int // resolution card images are resampled to
+	Duplex     Duplex
+	// BackOffsetX and BackOffsetY shift back pages to correct a printer's front-to-back misregistration
+	BackOffsetX float64
+	BackOffsetY float64
+}
+
+// DefaultLayout is a 3x3 sheet of poker-size (2.5" x 3.5") cards on Letter with 1/8" bleed and crop marks at 300 DPI
+func DefaultLayout() Layout {
+	return Layout{
+		Page:       Letter,
+		CardWidth:  2.5 * PointsPerInch,
+		CardHeight: 3.5 * PointsPerInch,
+		Columns:    3,
+		Rows:       3,
+		Bleed:      0.125 * PointsPerInch,
+		CropMarks:  true,
+		DPI:        300,
+		Duplex:     DuplexNone,
+	}
+}
+
+// Validate checks the layout's sizes and that its grid, with bleed, fits the page
+func (l Layout) Validate() error {
+	if l.CardWidth <= 0 || l.CardHeight <= 0 {
+		return fmt.Errorf("card size must be positive")
+	}
+	if l.Columns <= 0 || l.Rows <= 0 {
+		return fmt.Errorf("columns and rows must be positive")
+	}
+	if l.Bleed < 0 || l.Gutter < 0 {
+		return fmt.Errorf("bleed and gutter cannot be negative")
+	}
+	if l.DPI <= 0 {
+		return fmt.Errorf("dpi must be positive")
+	}
+	if _, err := ParseDuplex(string(l.Duplex)); err != nil {
+		return err
+	}
+
+	width, height := l.gridSize()
+	if width+2*l.Bleed > l.Page.Width || height+2*l.Bleed > l.Page.Height {
+		return fmt.Errorf("%dx%d cards of %.1fx%.1fpt with %.1fpt bleed need %.1fx%.1fpt, larger than the %s page",
+			l.Columns, l.Rows, l.CardWidth, l.CardHeight, l.Bleed, width+2*l.Bleed, height+2*l.Bleed, l.Page.Name)
+	}
+	return nil
+}
+
+// CanPrint checks that the layout can print the sides: backs need a duplex mode to line up with the fronts
+func (l Layout) CanPrint(sides Sides) error {
+	if _, err := ParseSides(string(sides)); err != nil {
+		return err
+	}
+	if sides != SidesFront && l.Duplex == DuplexNone {
+		return fmt.Errorf("printing %s sides needs a duplex mode to align backs with fronts", sides)
+	}
+	return nil
+}
+
+// PerPage is the number of cards on a sheet
+func (l Layout) PerPage() int {
+	return l.Columns * l.Rows
+}
+
+// gridSize is the size of the cards' trim boxes and the gutters between them
+func (l Layout) gridSize() (float64, float64) {
+	return float64(l.Columns)*l.CardWidth + float64(l.Columns-1)*l.Gutter,
+		float64(l.Rows)*l.CardHeight + float64(l.Rows-1)*l.Gutter
+}
+
+// trimBox is the lower left corner of the card at a column and row, counted from the top left.
+// PDF coordinates start at the bottom left of the page.
+func (l Layout) trimBox(column, row int) (x, y float64) {
+	width, height := l.gridSize()
+	left := (l.Page.Width - width) / 2
+	top := (l.Page.Height + height) / 2
+	return left + float64(column)*(l.CardWidth+l.Gutter),
+		top - float64(row+1)*l.CardHeight - float64(row)*l.Gutter
+}
+
+// backSlot is the column and row on the back page behind a front slot
+func (l Layout) backSlot(column, row int) (int, int) {
+	switch l.Duplex {
+	case DuplexLongEdge:
+		return l.Columns - 1 - column, row
+	case DuplexShortEdge:
+		return column, l.Rows - 1 - row
+	default:
+		return column, row
+	}
+}
+
+// clipBox is the visible area of a card's bleed: full bleed at the sheet's outer edges and
+// at most half the gutter between cards, so neighbours do not cover each other
+func (l Layout) clipBox(column, row int, x, y float64) (float64, float64, float64, float64) {
+	inner := math.Min(l.Bleed, l.Gutter/2)
+	left, right, top, bottom := inner, inner, inner, inner
+	if column == 0 {
+		left = l.Bleed
+	}
+	if column == l.Columns-1 {
+		right = l.Bleed
+	}
+	if row == 0 {
+		top = l.Bleed
+	}
+	if row == l.Rows-1 {
+		bottom = l.Bleed
+	}
+	return x - left, y - bottom, l.CardWidth + left + right, l.CardHeight + bottom + top
+}
+
+// Crop marks start cropMarkOffset beyond the bleed, so they are not cut into the card,
+// and are at most cropMarkLength long
+const (
+	cropMarkOffset = 3.0
+	cropMarkLength = 18.0
+)
+
+// cropMarks draws a short line in the margin at every cut line
+func (l Layout) cropMarks() string {
+	width, height := l.gridSize()
+	left := (l.Page.Width - width) / 2
+	bottom := (l.Page.Height - height) / 2
+	right, top := left+width, bottom+height
+
+	var xs, ys []float64
+	for c := 0; c < l.Columns; c++ {
+		x, _ := l.trimBox(c, 0)
+		xs = appendUnique(xs, x, x+l.CardWidth)
+	}
+	for r := 0; r < l.Rows; r++ {
+		_, y := l.trimBox(0, r)
+		ys = appendUnique(ys, y, y+l.CardHeight)
+	}
+
+	var b strings.Builder
+	b.WriteString("q 0 G 0.5 w\n")
+	if length := math.Min(cropMarkLength, bottom-l.Bleed-cropMarkOffset); length > 0 {
+		for _, x := range xs {
+			start := bottom - l.Bleed - cropMarkOffset
+			fmt.Fprintf(&b, "%.2f %.2f m %.2f %.2f l S\n", x, start, x, start-length)
+			start = top + l.Bleed + cropMarkOffset
+			fmt.Fprintf(&b, "%.2f %.2f m %.2f %.2f l S\n", x, start, x, start+length)
+		}
+	}
+	if length := math.Min(cropMarkLength, left-l.Bleed-cropMarkOffset); length > 0 {
+		for _, y := range ys {
+			start := left - l.Bleed - cropMarkOffset
+			fmt.Fprintf(&b, "%.2f %.2f m %.2f %.2f l S\n", start, y, start-length, y)
+			start = right + l.Bleed + cropMarkOffset
+			fmt.Fprintf(&b, "%.2f %.2f m %.2f %.2f l S\n", start, y, start+length, y)
+		}
+	}
+	b.WriteString("Q\n")
+	return b.String()
+}
+
+// appendUnique appends values not already present, treating values within a hundredth of a point as equal
+func appendUnique(values []float64, add ...float64) []float64 {
+next:
+	for _, v := range add {
+		for _, existing := range values {
+			if math.Abs(existing-v) < 0.01 {
+				continue next
+			}
+		}
+		values = append(values, v)
+	}
+	return values
+}
+
+// Sides selects which sides of the cards a PDF holds
+type Sides string
+
+const (
+	SidesFront Sides = "front" // front pages only
+	SidesBoth  Sides = "both"  // each front page followed by the backs behind it, for duplex printing
+	SidesBack  Sides = "back"  // back pages only, aligned for printing on the reverse of front sheets
+)
+
+// ParseSides returns the sides for "front", "both" or "back"
+func ParseSides(name string) (Sides, error) {
+	switch Sides(strings.ToLower(strings.TrimSpace(name))) {
+	case "", SidesFront:
+		return SidesFront, nil
+	case SidesBoth:
+		return SidesBoth, nil
+	case SidesBack:
+		return SidesBack, nil
+	default:
+		return "", fmt.Errorf("unsupported sides: %s", name)
+	}
+}
+
+// SheetWriter streams cards onto PDF sheets, writing each page once it is full
+type SheetWriter struct {
+	layout Layout
+	sides  Sides
+	doc    *document
+	backs  map[[sha256.Size]byte]int // image objects of backs already written, by pixel hash
+	pages  []int
+	slots  []slot // cards on the current page
+	closed bool
+}
+
+// slot holds the image objects of a card's sides; zero when a side is not printed
+type slot struct {
+	front, back int
+}
+
+// NewSheetWriter starts a PDF on w holding the given sides. Backs are placed behind their
+// fronts as the layout's duplex mode flips the sheet, so printing backs needs a duplex mode.
+func NewSheetWriter(w io.Writer, layout Layout, sides Sides) (*SheetWriter, error) {
+	if err := layout.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid sheet layout: %w", err)
+	}
+	if err := layout.CanPrint(sides); err != nil {
+		return nil, err
+	}
+	return &SheetWriter{
+		layout: layout,
+		sides:  sides,
+		doc:    newDocument(w),
+		backs:  make(map[[sha256.Size]byte]int),
+	}, nil
+}
+
+// AddCard adds a rendered card to the next free slot. The back is only needed when backs
+// are printed and the front only when fronts are; identical backs are embedded once.
+func (s *SheetWriter) AddCard(front, back image.Image) error {
+	if s.closed {
+		return fmt.Errorf("sheet writer is closed")
+	}
+
+	var card slot
+	if s.sides != SidesBack {
+		if front == nil {
+			return fmt.Errorf("card front is missing")
+		}
+		card.front = s.doc.reserve()
+		s.doc.image(card.front, s.prepare(front))
+	}
+	if s.sides != SidesFront {
+		if back == nil {
+			return fmt.Errorf("card back is missing")
+		}
+		card.back = s.backImage(back)
+	}
+	s.slots = append(s.slots, card)
+
+	if len(s.slots) == s.layout.PerPage() {
+		s.flushPage()
+	}
+	return s.doc.err
+}
+
+// backImage writes a back unless an identical one was written before, returning its image object
+func (s *SheetWriter) backImage(back image.Image) int {
+	prepared := s.prepare(back)
+	key := sha256.Sum256(prepared.Pix)
+	if id, ok := s.backs[key]; ok {
+		return id
+	}
+	id := s.doc.reserve()
+	s.doc.image(id, prepared)
+	s.backs[key] = id
+	return id
+}
+
+// Close writes the last partial page and finishes the document
+func (s *SheetWriter) Close() error {
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	if len(s.slots) > 0 {
+		s.flushPage()
+	}
+	if len(s.pages) == 0 {
+		return fmt.Errorf("no cards were added")
+	}
+	return s.doc.close(s.pages, fmt.Sprintf("[0 0 %.2f %.2f]", s.layout.Page.Width, s.layout.Page.Height))
+}
+
+// flushPage writes the front page of the current cards and the page of backs behind them
+func (s *SheetWriter) flushPage() {
+	if s.sides != SidesBack {
+		var content strings.Builder
+		var images []resource
+		for i, card := range s.slots {
+			column, row := i%s.layout.Columns, i/s.layout.Columns
+			name := fmt.Sprintf("Im%d", i)
+			images = append(images, resource{name, card.front})
+			s.placeCard(&content, name, column, row, 0, 0)
+		}
+		if s.layout.CropMarks {
+			content.WriteString(s.layout.cropMarks())
+		}
+		s.pages = append(s.pages, s.writePage(content.String(), images))
+	}
+
+	if s.sides != SidesFront {
+		var content strings.Builder
+		var images []resource
+		names := make(map[int]string)
+		for i, card := range s.slots {
+			name, ok := names[card.back]
+			if !ok {
+				name = fmt.Sprintf("Back%d", len(names))
+				names[card.back] = name
+				images = append(images, resource{name, card.back})
+			}
+			column, row := s.layout.backSlot(i%s.layout.Columns, i/s.layout.Columns)
+			s.placeCard(&content, name, column, row, s.layout.BackOffsetX, s.layout.BackOffsetY)
+		}
+		s.pages = append(s.pages, s.writePage(content.String(), images))
+	}
+	s.slots = s.slots[:0]
+}
+
+// placeCard draws an image at a slot, clipped to the slot's visible bleed
+func (s *SheetWriter) placeCard(content *strings.Builder, name string, column, row int, dx, dy float64) {
+	l := s.layout
+	x, y := l.trimBox(column, row)
+	x, y = x+dx, y+dy
+	cx, cy, cw, ch := l.clipBox(column, row, x, y)
+	fmt.Fprintf(content, "q %.2f %.2f %.2f %.2f re W n %.2f 0 0 %.2f %.2f %.2f cm /%s Do Q\n",
+		cx, cy, cw, ch,
+		l.CardWidth+2*l.Bleed, l.CardHeight+2*l.Bleed, x-l.Bleed, y-l.Bleed, name)
+}
+
+// resource is an image XObject named in a page's content
+type resource struct {
+	name string
+	id   int
+}
+
+// writePage writes a page with its content stream and image resources, returning its object number
+func (s *SheetWriter) writePage(content string, images []resource) int {
+	contentID := s.doc.reserve()
+	s.doc.stream(contentID, "", []byte(content))
+
+	var resources strings.Builder
+	for _, image := range images {
+		fmt.Fprintf(&resources, "/%s %d 0 R ", image.name, image.id)
+	}
+	pageID := s.doc.reserve()
+	s.doc.object(pageID, fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources << /XObject << %s>> >> /Contents %d 0 R >>",
+		pagesID, resources.String(), contentID))
+	return pageID
+}
+
+// prepare extends the card by the bleed, repeating its edge pixels, and resamples it to the layout's DPI
+func (s *SheetWriter) prepare(img image.Image) *image.RGBA {
+	l := s.layout
+	src := img.Bounds()
+
+	// Bleed in source pixels, at the source's own scale
+	bleedX := int(math.Round(l.Bleed / l.CardWidth * float64(src.Dx())))
+	bleedY := int(math.Round(l.Bleed / l.CardHeight * float64(src.Dy())))
+	extended := image.NewRGBA(image.Rect(0, 0, src.Dx()+2*bleedX, src.Dy()+2*bleedY))
+	draw.Draw(extended, image.Rect(bleedX, bleedY, bleedX+src.Dx(), bleedY+src.Dy()), img, src.Min, draw.Src)
+	extendEdges(extended, bleedX, bleedY)
+
+	width := int(math.Round((l.CardWidth + 2*l.Bleed) / PointsPerInch * float64(l.DPI)))
+	height := int(math.Round((l.CardHeight + 2*l.Bleed) / PointsPerInch * float64(l.DPI)))
+	out := image.NewRGBA(image.Rect(0, 0, width, height))
+	xdraw.CatmullRom.Scale(out, out.Bounds(), extended, extended.Bounds(), xdraw.Src, nil)
+	return out
+}
+
+// extendEdges fills a margin of bx and by pixels around the image's centre by repeating the nearest inner pixel
+func extendEdges(img *image.RGBA, bx, by int) {
+	b := img.Bounds()
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		sy := min(max(y, by), b.Max.Y-by-1)
+		for x := b.Min.X; x < b.Max.X; x++ {
+			sx := min(max(x, bx), b.Max.X-bx-1)
+			if sx != x || sy != y {
+				img.SetRGBA(x, y, img.RGBAAt(sx, sy))
+			}
+		}
+	}
+}
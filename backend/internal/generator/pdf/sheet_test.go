@@ -0,0 +1,236 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func solid(w, h int, c color.Color) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// checkXref verifies that every object listed in the cross-reference table starts at its offset
+func checkXref(t *testing.T, data []byte) {
+	t.Helper()
+	i := bytes.LastIndex(data, []byte("startxref\n"))
+	if i < 0 {
+		t.Fatal("missing startxref")
+	}
+	offset, err := strconv.Atoi(strings.Fields(string(data[i+len("startxref\n"):]))[0])
+	if err != nil || !bytes.HasPrefix(data[offset:], []byte("xref\n")) {
+		t.Fatalf("startxref %d does not point at the xref table", offset)
+	}
+	lines := strings.Split(string(data[offset:]), "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for id := 1; id < count; id++ {
+		entry := lines[2+id]
+		if !strings.HasSuffix(entry, "n ") {
+			continue
+		}
+		at, _ := strconv.Atoi(entry[:10])
+		if want := fmt.Sprintf("%d 0 obj", id); !bytes.HasPrefix(data[at:], []byte(want)) {
+			t.Errorf("object %d is not at offset %d", id, at)
+		}
+	}
+}
+
+func TestLayoutValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*Layout)
+		wantErr bool
+	}{
+		{"Default fits Letter", func(l *Layout) {}, false},
+		{"Default fits A4", func(l *Layout) { l.Page = A4 }, false},
+		{"Large gutter overflows Letter", func(l *Layout) { l.Gutter = 18 }, true},
+		{"Four rows overflow", func(l *Layout) { l.Rows = 4 }, true},
+		{"Zero DPI", func(l *Layout) { l.DPI = 0 }, true},
+		{"Negative bleed", func(l *Layout) { l.Bleed = -1 }, true},
+		{"Unknown duplex", func(l *Layout) { l.Duplex = "sideways" }, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := DefaultLayout()
+			tt.modify(&layout)
+			if err := layout.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLayoutSlots(t *testing.T) {
+	layout := DefaultLayout()
+
+	// The grid is centred: 540x756pt of cards on 612x792pt
+	if x, y := layout.trimBox(0, 0); x != 36 || y != 522 {
+		t.Errorf("trimBox(0, 0) = %v, %v, want 36, 522", x, y)
+	}
+	if x, y := layout.trimBox(2, 2); x != 396 || y != 18 {
+		t.Errorf("trimBox(2, 2) = %v, %v, want 396, 18", x, y)
+	}
+
+	// Without a gutter, bleed only shows at the outer edges
+	if x, y, w, h := layout.clipBox(1, 1, 216, 270); x != 216 || y != 270 || w != 180 || h != 252 {
+		t.Errorf("inner clipBox = %v %v %v %v, want the trim box", x, y, w, h)
+	}
+	if x, y, w, h := layout.clipBox(0, 0, 36, 522); x != 27 || y != 522 || w != 189 || h != 261 {
+		t.Errorf("corner clipBox = %v %v %v %v, want bleed on the top and left", x, y, w, h)
+	}
+
+	tests := []struct {
+		duplex       Duplex
+		column, row  int
+		wantC, wantR int
+	}{
+		{DuplexLongEdge, 0, 0, 2, 0},
+		{DuplexLongEdge, 1, 2, 1, 2},
+		{DuplexShortEdge, 0, 0, 0, 2},
+		{DuplexShortEdge, 2, 1, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("%s %d,%d", tt.duplex, tt.column, tt.row), func(t *testing.T) {
+			layout.Duplex = tt.duplex
+			if c, r := layout.backSlot(tt.column, tt.row); c != tt.wantC || r != tt.wantR {
+				t.Errorf("backSlot() = %d, %d, want %d, %d", c, r, tt.wantC, tt.wantR)
+			}
+		})
+	}
+}
+
+func TestSheetWriter(t *testing.T) {
+	red := solid(50, 70, color.RGBA{200, 0, 0, 255})
+	black := solid(50, 70, color.Black)
+	blue := solid(50, 70, color.RGBA{0, 0, 200, 255})
+
+	tests := []struct {
+		name       string
+		cards      int
+		sides      Sides
+		backs      []image.Image // cycled through the cards
+		wantPages  int
+		wantImages int
+	}{
+		{"One partial page", 4, SidesFront, nil, 1, 4},
+		{"Full and partial page", 10, SidesFront, nil, 2, 10},
+		{"Both sides add a back page per sheet; a shared back is embedded once", 10, SidesBoth, []image.Image{black}, 4, 11},
+		{"Per-card backs", 4, SidesBoth, []image.Image{black, blue}, 2, 6},
+		{"Back-only sheets", 10, SidesBack, []image.Image{black}, 2, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			layout := DefaultLayout()
+			layout.DPI = 20 // keep the images small
+			if tt.sides != SidesFront {
+				layout.Duplex = DuplexLongEdge
+			}
+
+			var buf bytes.Buffer
+			sheets, err := NewSheetWriter(&buf, layout, tt.sides)
+			if err != nil {
+				t.Fatalf("NewSheetWriter() error = %v", err)
+			}
+			for i := 0; i < tt.cards; i++ {
+				var back image.Image
+				if len(tt.backs) > 0 {
+					back = tt.backs[i%len(tt.backs)]
+				}
+				if err := sheets.AddCard(red, back); err != nil {
+					t.Fatalf("AddCard() error = %v", err)
+				}
+			}
+			if err := sheets.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			data := buf.Bytes()
+			if !bytes.HasPrefix(data, []byte("%PDF-1.4")) || !bytes.HasSuffix(data, []byte("%%EOF\n")) {
+				t.Fatal("output is not framed as a PDF")
+			}
+			checkXref(t, data)
+
+			count := regexp.MustCompile(`/Count (\d+)`).FindSubmatch(data)
+			if count == nil || string(count[1]) != strconv.Itoa(tt.wantPages) {
+				t.Errorf("page count = %s, want %d", count, tt.wantPages)
+			}
+			if got := bytes.Count(data, []byte("/Type /Page ")); got != tt.wantPages {
+				t.Errorf("found %d page objects, want %d", got, tt.wantPages)
+			}
+			if got := bytes.Count(data, []byte("/Subtype /Image")); got != tt.wantImages {
+				t.Errorf("found %d images, want %d", got, tt.wantImages)
+			}
+		})
+	}
+
+	if _, err := NewSheetWriter(&bytes.Buffer{}, Layout{}, SidesFront); err == nil {
+		t.Error("NewSheetWriter() with an invalid layout should fail")
+	}
+	if _, err := NewSheetWriter(&bytes.Buffer{}, DefaultLayout(), SidesBoth); err == nil {
+		t.Error("NewSheetWriter() printing backs without a duplex mode should fail")
+	}
+	for _, sides := range []Sides{SidesBoth, SidesBack} {
+		if err := DefaultLayout().CanPrint(sides); err == nil {
+			t.Errorf("CanPrint(%s) without a duplex mode should fail", sides)
+		}
+	}
+	if err := DefaultLayout().CanPrint(SidesFront); err != nil {
+		t.Errorf("CanPrint(front) error = %v", err)
+	}
+
+	layout := DefaultLayout()
+	layout.Duplex = DuplexShortEdge
+	sheets, _ := NewSheetWriter(&bytes.Buffer{}, layout, SidesBoth)
+	if err := sheets.AddCard(red, nil); err == nil {
+		t.Error("AddCard() without a back should fail when printing backs")
+	}
+	sheets, _ = NewSheetWriter(&bytes.Buffer{}, DefaultLayout(), SidesFront)
+	if err := sheets.Close(); err == nil {
+		t.Error("Close() without cards should fail")
+	}
+}
+
+func TestPrepareBleed(t *testing.T) {
+	layout := DefaultLayout()
+	layout.DPI = 72 // one pixel per point
+	sheets, err := NewSheetWriter(&bytes.Buffer{}, layout, SidesFront)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A card with a red left half and a blue right half
+	src := image.NewRGBA(image.Rect(0, 0, 180, 252))
+	for y := 0; y < 252; y++ {
+		for x := 0; x < 180; x++ {
+			if x < 90 {
+				src.Set(x, y, color.RGBA{255, 0, 0, 255})
+			} else {
+				src.Set(x, y, color.RGBA{0, 0, 255, 255})
+			}
+		}
+	}
+
+	out := sheets.prepare(src)
+	if out.Bounds().Dx() != 198 || out.Bounds().Dy() != 270 {
+		t.Fatalf("prepared size = %v, want 198x270 with 9pt bleed", out.Bounds().Size())
+	}
+	if c := out.RGBAAt(1, 1); c.R != 255 || c.B != 0 {
+		t.Errorf("left bleed = %v, want the red edge repeated", c)
+	}
+	if c := out.RGBAAt(196, 268); c.B != 255 || c.R != 0 {
+		t.Errorf("right bleed = %v, want the blue edge repeated", c)
+	}
+}
@@ -4,11 +4,14 @@ package svg
 import (
 	"fmt"
 	"html/template"
+	"image"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/svg/metadata"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/svg/templates"
 )
@@ -39,6 +42,31 @@ func (g *svgGenerator) GenerateCard(data *card.CardDTO, outputPath string) error
 	return g.GenerateSVG(data, outputPath)
 }
 
+// GenerateCardWithOptions implements the CardGenerator interface; raster output options do not apply to SVG
+func (g *svgGenerator) GenerateCardWithOptions(data *card.CardDTO, outputPath string, opts output.Options) error {
+	return fmt.Errorf("output options are not supported by the SVG generator")
+}
+
+// Render implements the CardGenerator interface; SVG cards are not rasterized
+func (g *svgGenerator) Render(data *card.CardDTO) (image.Image, error) {
+	return nil, fmt.Errorf("raster rendering is not supported by the SVG generator")
+}
+
+// RenderTo implements the CardGenerator interface; raster output options do not apply to SVG
+func (g *svgGenerator) RenderTo(w io.Writer, data *card.CardDTO, opts output.Options) error {
+	return fmt.Errorf("output options are not supported by the SVG generator")
+}
+
+// RenderBack implements the CardGenerator interface; SVG card backs are not supported yet
+func (g *svgGenerator) RenderBack(data *card.CardDTO) (image.Image, error) {
+	return nil, fmt.Errorf("card backs are not supported by the SVG generator")
+}
+
+// GenerateBack implements the CardGenerator interface; SVG card backs are not supported yet
+func (g *svgGenerator) GenerateBack(data *card.CardDTO, outputPath string) error {
+	return fmt.Errorf("card backs are not supported by the SVG generator")
+}
+
 // ValidateCard implements the CardGenerator interface
 func (g *svgGenerator) ValidateCard(data *card.CardDTO) error {
 	if data == nil {
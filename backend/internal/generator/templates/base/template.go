@@ -4,6 +4,7 @@ package base
 import (
 	"fmt"
 	"image"
+	"image/color"
 	"image/png"
 	"os"
 	"path/filepath"
@@ -19,6 +20,23 @@ type Template interface {
 	GetArtBounds() image.Rectangle
 }
 
+// TextStyle is the font, size, color and alignment a template sets for one of its text bounds.
+// Zero fields keep the text renderer's defaults.
+type TextStyle struct {
+	Font          string
+	Size          float64
+	Color         color.Color
+	Align         string // left, center or right
+	VerticalAlign string // top, center or bottom
+	OneLine       bool
+}
+
+// StyledTemplate is a template that also styles its text, keyed like its text bounds
+type StyledTemplate interface {
+	Template
+	GetTextStyles(data *card.CardDTO) map[string]TextStyle
+}
+
 // BaseTemplate provides common template functionality
 type BaseTemplate struct {
 	framesPath string
@@ -53,6 +71,11 @@ func (b *BaseTemplate) GetArtBounds() image.Rectangle {
 	return b.artBounds
 }
 
+// TemplateDir is the directory holding the frame images and layout exports
+func TemplateDir() string {
+	return getTemplateDir()
+}
+
 // Helper functions
 func getTemplateDir() string {
 	// Get the current file's location
@@ -3,9 +3,13 @@ package factory
 
 import (
 	"fmt"
+	"log"
+	"strings"
+	"sync"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/base"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/layout"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/types"
 )
 
@@ -17,6 +21,11 @@ const (
 	FormatSVG OutputFormat = "svg"
 )
 
+var (
+	layoutsMutex sync.Mutex
+	layouts      map[string]*layout.Layout
+)
+
 // NewTemplate creates the appropriate template type (PNG format for backward compatibility)
 func NewTemplate(cardType card.CardType) (base.Template, error) {
 	return NewPNGTemplate(cardType)
@@ -48,3 +57,51 @@ func NewPNGTemplate(cardType card.CardType) (base.Template, error) {
 
 	return template, nil
 }
+
+// NewBackTemplate creates the card back template, reading set and global backs from dir
+func NewBackTemplate(dir string) *types.BackTemplate {
+	return types.NewBackTemplate(dir)
+}
+
+// NewTemplateFor creates the template a card is drawn with: the layout named in its "frame" metadata, or else its type's template
+func NewTemplateFor(data *card.CardDTO) (base.Template, error) {
+	if name := strings.TrimSpace(data.Metadata[types.MetadataFrameKey]); name != "" {
+		return NewLayoutTemplate(name)
+	}
+	return NewTemplate(data.Type)
+}
+
+// NewLayoutTemplate creates a template from the named layout export in the templates directory
+func NewLayoutTemplate(name string) (*types.LayoutTemplate, error) {
+	all, err := Layouts()
+	if err != nil {
+		return nil, err
+	}
+	l, ok := all[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown layout: %s", name)
+	}
+	return types.NewLayoutTemplate(l), nil
+}
+
+// Layouts returns the layout exports in the templates directory, keyed by name. They are read once,
+// so a layout dropped into the directory is available after a restart. Layouts that fail to load
+// or have no frame image are logged and left out; a directory that cannot be read is retried.
+func Layouts() (map[string]*layout.Layout, error) {
+	layoutsMutex.Lock()
+	defer layoutsMutex.Unlock()
+
+	if layouts != nil {
+		return layouts, nil
+	}
+
+	loaded, skipped, err := layout.LoadDir(base.TemplateDir())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load layouts: %w", err)
+	}
+	for _, err := range skipped {
+		log.Printf("Skipping layout: %v", err)
+	}
+	layouts = loaded
+	return layouts, nil
+}
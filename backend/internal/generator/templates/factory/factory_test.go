@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/types"
 )
 
 func TestTemplateFactory(t *testing.T) {
@@ -70,3 +71,37 @@ func TestTemplateFactory(t *testing.T) {
 		})
 	}
 }
+
+func TestNewTemplateFor(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       *card.CardDTO
+		wantLayout string
+		wantErr    bool
+	}{
+		{"Type template", &card.CardDTO{Type: card.TypeSpell}, "", false},
+		{"Layout from metadata", &card.CardDTO{Type: card.TypeIncantation, Metadata: map[string]string{"frame": "SpecialIncantation"}}, "SpecialIncantation", false},
+		{"Unknown layout", &card.CardDTO{Type: card.TypeSpell, Metadata: map[string]string{"frame": "Missing"}}, "", true},
+		{"Layout without a frame", &card.CardDTO{Type: card.TypeCreature, Metadata: map[string]string{"frame": "SampleCreature"}}, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			template, err := NewTemplateFor(tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewTemplateFor() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+
+			got := ""
+			if lt, ok := template.(*types.LayoutTemplate); ok {
+				got = lt.Layout().Name
+			}
+			if got != tt.wantLayout {
+				t.Errorf("layout = %q, want %q", got, tt.wantLayout)
+			}
+		})
+	}
+}
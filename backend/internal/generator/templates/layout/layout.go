@@ -0,0 +1,232 @@
+// templates/layout/layout.go
+package layout
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ArtName is the name of the image node marking a layout's art region
+const ArtName = "Art"
+
+// Layout is a card frame exported from CardConjurer: its size and margins, its text boxes and its image regions
+type Layout struct {
+	Name    string // file name without extension, e.g. "BaseAnthem"
+	Width   int
+	Height  int
+	Corners int
+	MarginX int
+	MarginY int
+	Frame   string             // path of the PNG with the same base name, empty when there is none
+	Text    map[string]TextBox // by node name, e.g. "Title" or "Rules"
+	Images  []Region
+	Art     image.Rectangle // region of the image node named "Art", empty when the layout has none
+
+	// Fingerprint hashes the layout and frame files, so a change to either changes it
+	Fingerprint string
+}
+
+// TextBox is a text node of a layout
+type TextBox struct {
+	Name            string
+	Tags            []string
+	Rect            image.Rectangle
+	Font            string
+	Size            float64
+	Color           color.Color // nil when the layout does not set one
+	Align           string      // left, center or right; empty means left
+	VerticalAlign   string      // top, center or bottom; empty means top
+	OneLine         bool
+	Rotation        float64 // degrees clockwise
+	LineHeightScale float64
+}
+
+// Region is an image node of a layout
+type Region struct {
+	Name string
+	Rect image.Rectangle
+}
+
+// document is the JSON exported by CardConjurer
+type document struct {
+	Name    string  `json:"name"`
+	Width   float64 `json:"width"`
+	Height  float64 `json:"height"`
+	Corners float64 `json:"corners"`
+	MarginX float64 `json:"marginX"`
+	MarginY float64 `json:"marginY"`
+	Data    node    `json:"data"`
+}
+
+// node is a group, image or text node of a layout; groups hold the other nodes
+type node struct {
+	Type            string   `json:"type"`
+	Name            string   `json:"name"`
+	Tags            []string `json:"tags"`
+	X               float64  `json:"x"`
+	Y               float64  `json:"y"`
+	Width           float64  `json:"width"`
+	Height          float64  `json:"height"`
+	Font            string   `json:"font"`
+	Size            float64  `json:"size"`
+	Color           string   `json:"color"`
+	Align           string   `json:"align"`
+	VerticalAlign   string   `json:"verticalAlign"`
+	OneLine         bool     `json:"oneLine"`
+	Rotation        float64  `json:"rotation"`
+	LineHeightScale float64  `json:"lineHeightScale"`
+	Children        []node   `json:"children"`
+}
+
+// Parse reads a CardConjurer layout export. The name, frame and fingerprint are left for Load to fill in.
+func Parse(r io.Reader) (*Layout, error) {
+	var doc document
+	if err := json.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode layout: %w", err)
+	}
+	if doc.Width <= 0 || doc.Height <= 0 {
+		return nil, fmt.Errorf("layout has no size")
+	}
+
+	l := &Layout{
+		Name:    doc.Name,
+		Width:   round(doc.Width),
+		Height:  round(doc.Height),
+		Corners: round(doc.Corners),
+		MarginX: round(doc.MarginX),
+		MarginY: round(doc.MarginY),
+		Text:    make(map[string]TextBox),
+	}
+	if err := l.add(doc.Data); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+// add collects the text boxes and image regions of a node and its children
+func (l *Layout) add(n node) error {
+	rect := image.Rect(round(n.X), round(n.Y), round(n.X+n.Width), round(n.Y+n.Height))
+
+	switch n.Type {
+	case "text":
+		if _, exists := l.Text[n.Name]; exists || rect.Empty() {
+			break
+		}
+		c, err := parseColor(n.Color)
+		if err != nil {
+			return fmt.Errorf("invalid color of text %q: %w", n.Name, err)
+		}
+		l.Text[n.Name] = TextBox{
+			Name:            n.Name,
+			Tags:            n.Tags,
+			Rect:            rect,
+			Font:            n.Font,
+			Size:            n.Size,
+			Color:           c,
+			Align:           n.Align,
+			VerticalAlign:   n.VerticalAlign,
+			OneLine:         n.OneLine,
+			Rotation:        n.Rotation,
+			LineHeightScale: n.LineHeightScale,
+		}
+	case "image":
+		l.Images = append(l.Images, Region{Name: n.Name, Rect: rect})
+		if strings.EqualFold(n.Name, ArtName) && l.Art.Empty() {
+			l.Art = rect
+		}
+	}
+
+	for _, child := range n.Children {
+		if err := l.add(child); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Load reads a layout file, pairing it with the PNG frame of the same base name when there is one
+func Load(path string) (*Layout, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read layout: %w", err)
+	}
+	l, err := Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse layout %s: %w", path, err)
+	}
+	l.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+
+	h := sha256.New()
+	h.Write(data)
+	frame := strings.TrimSuffix(path, filepath.Ext(path)) + ".png"
+	frameData, err := os.ReadFile(frame)
+	switch {
+	case err == nil:
+		l.Frame = frame
+		h.Write(frameData)
+	case !os.IsNotExist(err):
+		return nil, fmt.Errorf("failed to read frame of layout %s: %w", l.Name, err)
+	}
+	l.Fingerprint = hex.EncodeToString(h.Sum(nil))
+	return l, nil
+}
+
+// LoadDir loads every layout file in dir that has a frame image, keyed by name. A file that
+// fails to load or has no frame is skipped and reported in the returned per-file errors;
+// the error is only set when the directory cannot be listed.
+func LoadDir(dir string) (map[string]*Layout, []error, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list layouts: %w", err)
+	}
+
+	layouts := make(map[string]*Layout, len(paths))
+	var skipped []error
+	for _, path := range paths {
+		l, err := Load(path)
+		if err != nil {
+			skipped = append(skipped, err)
+			continue
+		}
+		if l.Frame == "" {
+			skipped = append(skipped, fmt.Errorf("layout %s has no frame image %s.png", l.Name, l.Name))
+			continue
+		}
+		layouts[l.Name] = l
+	}
+	return layouts, skipped, nil
+}
+
+// parseColor parses a #rgb or #rrggbb color; an empty string is no color
+func parseColor(s string) (color.Color, error) {
+	if s == "" {
+		return nil, nil
+	}
+	hex := strings.TrimPrefix(s, "#")
+	if len(hex) == 3 {
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+	if len(hex) != 6 || !strings.HasPrefix(s, "#") {
+		return nil, fmt.Errorf("unsupported color %q", s)
+	}
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported color %q", s)
+	}
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 255}, nil
+}
+
+func round(v float64) int {
+	return int(math.Round(v))
+}
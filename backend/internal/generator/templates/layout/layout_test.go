@@ -0,0 +1,171 @@
+package layout
+
+import (
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadDir(t *testing.T) {
+	layouts, skipped, err := LoadDir(filepath.Join("..", "images"))
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+
+	// SampleCreature and unnamed have no frame image
+	if len(skipped) != 2 {
+		t.Errorf("skipped %d layouts, want 2: %v", len(skipped), skipped)
+	}
+	for _, name := range []string{"SampleCreature", "unnamed"} {
+		if _, ok := layouts[name]; ok {
+			t.Errorf("layout %s without a frame was loaded", name)
+		}
+	}
+
+	for _, name := range []string{"BaseAnthem", "BaseArtifact", "BaseIncantation", "SpecialCreature", "SpecialCreatureWithStats", "SpecialIncantation", "Sample Title"} {
+		t.Run(name, func(t *testing.T) {
+			l, ok := layouts[name]
+			if !ok {
+				t.Fatalf("layout %s not loaded", name)
+			}
+			if l.Width != 1500 || l.Height != 2100 || l.MarginX != 66 || l.MarginY != 60 {
+				t.Errorf("size = %dx%d, margins %d,%d", l.Width, l.Height, l.MarginX, l.MarginY)
+			}
+			if l.Frame == "" {
+				t.Error("Frame is empty")
+			}
+			if l.Fingerprint == "" {
+				t.Error("Fingerprint is empty")
+			}
+
+			title := l.Text["Title"]
+			if title.Rect != image.Rect(125, 90, 1375, 170) || title.Font != "quango" || title.Size != 80 || !title.OneLine {
+				t.Errorf("Title = %+v", title)
+			}
+			if rules := l.Text["Rules"]; rules.Rect != image.Rect(160, 1300, 1340, 1800) || rules.Font != "montserrat" {
+				t.Errorf("Rules = %+v", rules)
+			}
+			if right := l.Text["Right Sidebar"]; right.Rotation != 90 {
+				t.Errorf("Right Sidebar rotation = %v, want 90", right.Rotation)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		json    string
+		wantErr bool
+		check   func(t *testing.T, l *Layout)
+	}{
+		{
+			name: "Nested groups with art",
+			json: `{"width": 750, "height": 1050, "data": {"type": "group", "children": [
+				{"type": "group", "children": [{"type": "image", "name": "art", "x": 50, "y": 100.4, "width": 650, "height": 400}]},
+				{"type": "text", "name": "Title", "x": 40, "y": 40, "width": 670, "height": 40, "color": "#f80", "align": "center"},
+				{"type": "text", "name": "Title", "x": 0, "y": 0, "width": 10, "height": 10},
+				{"type": "text", "name": "Empty", "x": 0, "y": 0}
+			]}}`,
+			check: func(t *testing.T, l *Layout) {
+				if l.Art != image.Rect(50, 100, 700, 500) {
+					t.Errorf("Art = %v", l.Art)
+				}
+				title := l.Text["Title"]
+				if title.Rect != image.Rect(40, 40, 710, 80) || title.Align != "center" {
+					t.Errorf("Title = %+v, want the first title node", title)
+				}
+				if title.Color != (color.NRGBA{0xff, 0x88, 0x00, 0xff}) {
+					t.Errorf("Title color = %v", title.Color)
+				}
+				if _, ok := l.Text["Empty"]; ok {
+					t.Error("text node without a size should be skipped")
+				}
+			},
+		},
+		{
+			name: "No art region",
+			json: `{"width": 1500, "height": 2100, "data": {"type": "group", "children": [{"type": "image", "name": "Frame", "width": 1500, "height": 2100}]}}`,
+			check: func(t *testing.T, l *Layout) {
+				if !l.Art.Empty() || len(l.Images) != 1 {
+					t.Errorf("Art = %v, Images = %v", l.Art, l.Images)
+				}
+			},
+		},
+		{"No size", `{"data": {"type": "group"}}`, true, nil},
+		{"Invalid color", `{"width": 1, "height": 1, "data": {"type": "text", "name": "T", "width": 1, "height": 1, "color": "red"}}`, true, nil},
+		{"Not JSON", `<svg/>`, true, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l, err := Parse(strings.NewReader(tt.json))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.check != nil {
+				tt.check(t, l)
+			}
+		})
+	}
+}
+
+func TestLoadFingerprint(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "Custom.json")
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("Custom.json", `{"width": 10, "height": 10, "data": {"type": "group"}}`)
+	first, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if first.Name != "Custom" || first.Frame != "" {
+		t.Errorf("Name = %q, Frame = %q", first.Name, first.Frame)
+	}
+
+	write("Custom.png", "frame")
+	second, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+	if second.Frame != filepath.Join(dir, "Custom.png") {
+		t.Errorf("Frame = %q", second.Frame)
+	}
+	if second.Fingerprint == first.Fingerprint {
+		t.Error("adding a frame should change the fingerprint")
+	}
+}
+
+func TestLoadDirSkipsBadFiles(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, data string) {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("Good.json", `{"width": 10, "height": 10, "data": {"type": "group"}}`)
+	write("Good.png", "frame")
+	write("Broken.json", `{"width": 10,`)
+	write("Broken.png", "frame")
+	write("Frameless.json", `{"width": 10, "height": 10, "data": {"type": "group"}}`)
+
+	layouts, skipped, err := LoadDir(dir)
+	if err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+	if len(layouts) != 1 || layouts["Good"] == nil {
+		t.Errorf("layouts = %v, want only Good", layouts)
+	}
+	if len(skipped) != 2 {
+		t.Errorf("skipped = %v, want Broken and Frameless", skipped)
+	}
+}
@@ -0,0 +1,170 @@
+package types
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/fogleman/gg"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/art"
+)
+
+const (
+	// MetadataBackKey is the card metadata key holding a path to the card's back image
+	MetadataBackKey = "back"
+	// MetadataSetKey is the card metadata key naming the card's set
+	MetadataSetKey = "set"
+	// DefaultBackName is the file name of the global back in the backs directory
+	DefaultBackName = "default"
+)
+
+// BackSize is the size backs are drawn at, matching the card frames
+var BackSize = image.Rect(0, 0, 1500, 2100)
+
+// backExtensions lists the file extensions tried when looking up a back by name
+var backExtensions = []string{".png", ".jpg", ".jpeg", ".webp"}
+
+// BackTemplate resolves the back of a card. Backs are shared between cards and must not be modified.
+type BackTemplate struct {
+	dir   string
+	cache sync.Map // path or set name -> image.Image
+}
+
+// NewBackTemplate creates a back template reading backs from dir. A card's back is the file
+// named in its "back" metadata (relative to dir and confined to it), or else the back of its
+// set, e.g. "core-set.png" for set "Core Set", or else "default.png". Without any of these
+// a back is drawn with colors chosen from the set name.
+func NewBackTemplate(dir string) *BackTemplate {
+	return &BackTemplate{dir: dir}
+}
+
+// GetBack returns the back of a card, scaled to BackSize
+func (t *BackTemplate) GetBack(data *card.CardDTO) (image.Image, error) {
+	path, err := t.resolve(data)
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		return t.drawn(strings.TrimSpace(data.Metadata[MetadataSetKey])), nil
+	}
+
+	if cached, ok := t.cache.Load(path); ok {
+		return cached.(image.Image), nil
+	}
+	img, err := loadBack(path)
+	if err != nil {
+		return nil, err
+	}
+	t.cache.Store(path, img)
+	return img, nil
+}
+
+// resolve finds the back file of a card; an empty path means the drawn default
+func (t *BackTemplate) resolve(data *card.CardDTO) (string, error) {
+	if name := strings.TrimSpace(data.Metadata[MetadataBackKey]); name != "" {
+		path, err := art.LocalPath(t.dir, name)
+		if err != nil {
+			return "", fmt.Errorf("back for %s: %w", data.Name, err)
+		}
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("back for %s not found: %w", data.Name, err)
+		}
+		return path, nil
+	}
+
+	if t.dir == "" {
+		return "", nil
+	}
+	names := []string{DefaultBackName}
+	if set := art.Slug(data.Metadata[MetadataSetKey]); set != "" {
+		names = []string{set, DefaultBackName}
+	}
+	for _, name := range names {
+		for _, ext := range backExtensions {
+			path := filepath.Join(t.dir, name+ext)
+			if _, err := os.Stat(path); err == nil {
+				return path, nil
+			}
+		}
+	}
+	return "", nil
+}
+
+// loadBack decodes a back image and fits it to BackSize
+func loadBack(path string) (image.Image, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open back: %w", err)
+	}
+	defer f.Close()
+
+	img, _, err := image.Decode(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode back %s: %w", path, err)
+	}
+	if img.Bounds() == BackSize {
+		return img, nil
+	}
+	return art.DefaultPipeline().Fit(img, BackSize, art.DefaultFraming), nil
+}
+
+// drawn returns the drawn back of a set, or the global back for an empty set name
+func (t *BackTemplate) drawn(set string) image.Image {
+	key := "drawn:" + set
+	if cached, ok := t.cache.Load(key); ok {
+		return cached.(image.Image)
+	}
+	img := DrawBack(set)
+	t.cache.Store(key, img)
+	return img
+}
+
+// DrawBack draws a back: a gradient chosen from the set name inside a gold border with a central emblem
+func DrawBack(set string) *image.RGBA {
+	seed := "card back"
+	if set != "" {
+		seed += ":" + set
+	}
+	img := art.Procedural(seed, BackSize)
+
+	dc := gg.NewContextForRGBA(img)
+	w, h := float64(BackSize.Dx()), float64(BackSize.Dy())
+	gold := color.RGBA{212, 175, 55, 255}
+
+	// Darken the edge so the border stands out, then draw a double border
+	dc.SetColor(color.RGBA{0, 0, 0, 140})
+	dc.SetLineWidth(120)
+	dc.DrawRectangle(0, 0, w, h)
+	dc.Stroke()
+
+	dc.SetColor(gold)
+	dc.SetLineWidth(12)
+	dc.DrawRoundedRectangle(70, 70, w-140, h-140, 40)
+	dc.Stroke()
+	dc.SetLineWidth(4)
+	dc.DrawRoundedRectangle(100, 100, w-200, h-200, 28)
+	dc.Stroke()
+
+	// A diamond emblem in a ring at the centre
+	cx, cy := w/2, h/2
+	dc.SetLineWidth(10)
+	dc.DrawCircle(cx, cy, 260)
+	dc.Stroke()
+	dc.MoveTo(cx, cy-200)
+	dc.LineTo(cx+140, cy)
+	dc.LineTo(cx, cy+200)
+	dc.LineTo(cx-140, cy)
+	dc.ClosePath()
+	dc.SetColor(color.RGBA{0, 0, 0, 90})
+	dc.FillPreserve()
+	dc.SetColor(gold)
+	dc.Stroke()
+
+	return img
+}
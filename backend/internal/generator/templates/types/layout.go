@@ -0,0 +1,97 @@
+package types
+
+import (
+	"fmt"
+	"image"
+	"image/png"
+	"os"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/base"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/layout"
+)
+
+// MetadataFrameKey is the card metadata key naming the layout a card is drawn with, e.g. "SpecialIncantation"
+const MetadataFrameKey = "frame"
+
+// layoutBounds maps the names of CardConjurer text nodes to the text bounds they fill
+var layoutBounds = map[string]string{
+	"Title":          "name",
+	"Symbols":        "cost",
+	"Type":           "type",
+	"Rules":          "effect",
+	"Stats (Left)":   "attack",
+	"Stats (Right)":  "defense",
+	"Collector Info": "collector",
+}
+
+// LayoutTemplate draws cards with the frame, text boxes and art region of a loaded layout
+type LayoutTemplate struct {
+	layout *layout.Layout
+}
+
+func NewLayoutTemplate(l *layout.Layout) *LayoutTemplate {
+	return &LayoutTemplate{layout: l}
+}
+
+// Layout returns the layout the template draws with
+func (t *LayoutTemplate) Layout() *layout.Layout {
+	return t.layout
+}
+
+// GetFrame implements the Template interface
+func (t *LayoutTemplate) GetFrame(data *card.CardDTO) (image.Image, error) {
+	if t.layout.Frame == "" {
+		return nil, fmt.Errorf("layout %s has no frame image", t.layout.Name)
+	}
+
+	f, err := os.Open(t.layout.Frame)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open frame: %w", err)
+	}
+	defer f.Close()
+
+	return png.Decode(f)
+}
+
+// GetTextBounds implements the Template interface. Without a symbols box the cost shares the title's bounds.
+func (t *LayoutTemplate) GetTextBounds(data *card.CardDTO) map[string]image.Rectangle {
+	bounds := make(map[string]image.Rectangle)
+	for node, key := range layoutBounds {
+		if box, ok := t.layout.Text[node]; ok {
+			bounds[key] = box.Rect
+		}
+	}
+	if _, ok := bounds["cost"]; !ok {
+		if name, ok := bounds["name"]; ok {
+			bounds["cost"] = name
+		}
+	}
+	return bounds
+}
+
+// GetTextStyles implements the StyledTemplate interface
+func (t *LayoutTemplate) GetTextStyles(data *card.CardDTO) map[string]base.TextStyle {
+	styles := make(map[string]base.TextStyle)
+	for node, key := range layoutBounds {
+		if box, ok := t.layout.Text[node]; ok {
+			styles[key] = base.TextStyle{
+				Font:          box.Font,
+				Size:          box.Size,
+				Color:         box.Color,
+				Align:         box.Align,
+				VerticalAlign: box.VerticalAlign,
+				OneLine:       box.OneLine,
+			}
+		}
+	}
+	return styles
+}
+
+// GetArtBounds implements the Template interface, falling back to the default art bounds when the layout has no art region
+func (t *LayoutTemplate) GetArtBounds() image.Rectangle {
+	if t.layout.Art.Empty() {
+		return base.GetDefaultArtBounds()
+	}
+	return t.layout.Art
+}
@@ -1,10 +1,16 @@
 package types
 
 import (
+	"image"
+	"image/color"
+	"image/png"
+	"os"
 	"path/filepath"
 	"testing"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/base"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/layout"
 )
 
 func TestCreatureTemplate(t *testing.T) {
@@ -132,3 +138,126 @@ func TestTemplateDirectory(t *testing.T) {
 		t.Logf("Full path: %s", fullPath)
 	}
 }
+
+func TestBackTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeBack := func(name string, c color.Color) {
+		img := image.NewRGBA(image.Rect(0, 0, 150, 210))
+		for i := 0; i < len(img.Pix); i += 4 {
+			r, g, b, a := c.RGBA()
+			img.Pix[i], img.Pix[i+1], img.Pix[i+2], img.Pix[i+3] = uint8(r>>8), uint8(g>>8), uint8(b>>8), uint8(a>>8)
+		}
+		f, err := os.Create(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		if err := png.Encode(f, img); err != nil {
+			t.Fatal(err)
+		}
+	}
+	red := color.RGBA{255, 0, 0, 255}
+	green := color.RGBA{0, 255, 0, 255}
+	blue := color.RGBA{0, 0, 255, 255}
+	writeBack("core-set.png", red)
+	writeBack("default.png", green)
+	writeBack("custom.png", blue)
+
+	tests := []struct {
+		name     string
+		dir      string
+		metadata map[string]string
+		want     color.Color // centre pixel; nil for a drawn back
+		wantErr  bool
+	}{
+		{"Set back", dir, map[string]string{"set": "Core Set"}, red, false},
+		{"Default back for other sets", dir, map[string]string{"set": "Expansion"}, green, false},
+		{"Default back without a set", dir, nil, green, false},
+		{"Back named in metadata", dir, map[string]string{"set": "Core Set", "back": "custom.png"}, blue, false},
+		{"Missing metadata back", dir, map[string]string{"back": "missing.png"}, nil, true},
+		{"Metadata back leaving the directory", dir, map[string]string{"back": "../" + filepath.Base(dir) + "/custom.png"}, nil, true},
+		{"Absolute metadata back", dir, map[string]string{"back": filepath.Join(dir, "custom.png")}, nil, true},
+		{"System file as back", dir, map[string]string{"back": "/etc/passwd"}, nil, true},
+		{"Drawn back without a directory", "", map[string]string{"set": "Core Set"}, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backs := NewBackTemplate(tt.dir)
+			data := &card.CardDTO{Name: "Test Card", Metadata: tt.metadata}
+
+			img, err := backs.GetBack(data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBack() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if img.Bounds() != BackSize {
+				t.Errorf("back bounds = %v, want %v", img.Bounds(), BackSize)
+			}
+			if tt.want != nil {
+				r, g, b, _ := img.At(BackSize.Dx()/2, BackSize.Dy()/2).RGBA()
+				wr, wg, wb, _ := tt.want.RGBA()
+				if r>>8 != wr>>8 || g>>8 != wg>>8 || b>>8 != wb>>8 {
+					t.Errorf("back centre = %v, want %v", img.At(BackSize.Dx()/2, BackSize.Dy()/2), tt.want)
+				}
+			}
+
+			again, err := backs.GetBack(data)
+			if err != nil || again != img {
+				t.Error("GetBack() should return the cached back for the same card")
+			}
+		})
+	}
+}
+
+func TestLayoutTemplate(t *testing.T) {
+	l := &layout.Layout{
+		Name: "Custom",
+		Text: map[string]layout.TextBox{
+			"Title":        {Rect: image.Rect(10, 10, 200, 40), Font: "quango", Size: 30},
+			"Rules":        {Rect: image.Rect(10, 300, 200, 400), Align: "center"},
+			"Stats (Left)": {Rect: image.Rect(10, 450, 40, 480)},
+			"Sidebar":      {Rect: image.Rect(0, 0, 5, 5)},
+		},
+	}
+	template := NewLayoutTemplate(l)
+	data := &card.CardDTO{Type: card.TypeCreature, Name: "Test"}
+
+	bounds := template.GetTextBounds(data)
+	want := map[string]image.Rectangle{
+		"name":   image.Rect(10, 10, 200, 40),
+		"cost":   image.Rect(10, 10, 200, 40),
+		"effect": image.Rect(10, 300, 200, 400),
+		"attack": image.Rect(10, 450, 40, 480),
+	}
+	if len(bounds) != len(want) {
+		t.Errorf("GetTextBounds() = %v, want %v", bounds, want)
+	}
+	for key, rect := range want {
+		if bounds[key] != rect {
+			t.Errorf("bounds[%s] = %v, want %v", key, bounds[key], rect)
+		}
+	}
+
+	styles := template.GetTextStyles(data)
+	if styles["name"].Font != "quango" || styles["name"].Size != 30 || styles["effect"].Align != "center" {
+		t.Errorf("GetTextStyles() = %+v", styles)
+	}
+	if _, ok := styles["cost"]; ok {
+		t.Error("a cost sharing the title's bounds should keep its default style")
+	}
+
+	if got := template.GetArtBounds(); got != base.GetDefaultArtBounds() {
+		t.Errorf("GetArtBounds() = %v, want the default art bounds", got)
+	}
+	l.Art = image.Rect(20, 50, 180, 250)
+	if got := template.GetArtBounds(); got != l.Art {
+		t.Errorf("GetArtBounds() = %v, want %v", got, l.Art)
+	}
+
+	if _, err := template.GetFrame(data); err == nil {
+		t.Error("GetFrame() without a frame image should fail")
+	}
+}
@@ -1,25 +1,69 @@
 package manager
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"sync"
+
+	"github.com/golang/freetype/truetype"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goitalic"
+	"golang.org/x/image/font/gofont/goregular"
 )
 
+// embeddedFonts are used when a font file is missing, so text renders without an assets directory
+var embeddedFonts = map[string][]byte{
+	"regular": goregular.TTF,
+	"bold":    gobold.TTF,
+	"italic":  goitalic.TTF,
+}
+
+// parsedEmbedded caches parsed embedded fonts across font managers
+var parsedEmbedded sync.Map
+
 type FontManager struct {
 	fontPaths   map[string]string
 	fontCache   sync.Map
 	defaultFont string
 }
 
+// fontsDir holds the TrueType fonts text is rendered with
+var fontsDir = filepath.Join("assets", "fonts")
+
 func NewFontManager() (*FontManager, error) {
-	return &FontManager{
+	fm := &FontManager{
 		fontPaths: map[string]string{
-			"regular": filepath.Join("assets", "fonts", "regular.ttf"),
-			"bold":    filepath.Join("assets", "fonts", "bold.ttf"),
-			"italic":  filepath.Join("assets", "fonts", "italic.ttf"),
+			"regular": filepath.Join(fontsDir, "regular.ttf"),
+			"bold":    filepath.Join(fontsDir, "bold.ttf"),
+			"italic":  filepath.Join(fontsDir, "italic.ttf"),
 		},
 		defaultFont: "regular",
-	}, nil
+	}
+
+	// Any other font in the directory is available by its lowercased file name, e.g. "quango" for Quango.ttf
+	paths, err := filepath.Glob(filepath.Join(fontsDir, "*.ttf"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list fonts: %w", err)
+	}
+	for _, path := range paths {
+		name := strings.ToLower(strings.TrimSuffix(filepath.Base(path), filepath.Ext(path)))
+		if _, exists := fm.fontPaths[name]; !exists {
+			fm.fontPaths[name] = path
+		}
+	}
+	return fm, nil
+}
+
+// HasFont reports whether a font of that name is configured
+func (fm *FontManager) HasFont(name string) bool {
+	_, exists := fm.fontPaths[name]
+	return exists
 }
 
 func (fm *FontManager) GetFontPath(name string) string {
@@ -28,3 +72,86 @@ func (fm *FontManager) GetFontPath(name string) string {
 	}
 	return fm.fontPaths[fm.defaultFont]
 }
+
+// SetFontPath points a font name at a TrueType file
+func (fm *FontManager) SetFontPath(name, path string) {
+	fm.fontPaths[name] = path
+	fm.fontCache.Delete(name)
+}
+
+// Face returns a new face of the named font at the given size. Faces are not safe
+// for concurrent use, so each caller gets its own.
+func (fm *FontManager) Face(name string, size float64) (font.Face, error) {
+	f, err := fm.font(name)
+	if err != nil {
+		return nil, err
+	}
+	return truetype.NewFace(f, &truetype.Options{Size: size}), nil
+}
+
+// Fingerprint hashes every configured font, so a change to any font file changes it.
+// Missing files count as their embedded fallback.
+func (fm *FontManager) Fingerprint() (string, error) {
+	names := make([]string, 0, len(fm.fontPaths))
+	for name := range fm.fontPaths {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		data, err := os.ReadFile(fm.fontPaths[name])
+		if os.IsNotExist(err) {
+			fmt.Fprintf(h, "%s:embedded\n", name)
+			continue
+		}
+		if err != nil {
+			return "", fmt.Errorf("failed to read font %s: %w", name, err)
+		}
+		sum := sha256.Sum256(data)
+		fmt.Fprintf(h, "%s:%x\n", name, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// font loads and caches the named font, falling back to the embedded Go fonts when its file is missing
+func (fm *FontManager) font(name string) (*truetype.Font, error) {
+	if _, exists := fm.fontPaths[name]; !exists {
+		name = fm.defaultFont
+	}
+	if cached, ok := fm.fontCache.Load(name); ok {
+		return cached.(*truetype.Font), nil
+	}
+
+	data, err := os.ReadFile(fm.GetFontPath(name))
+	if os.IsNotExist(err) {
+		return embeddedFont(name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read font %s: %w", name, err)
+	}
+
+	f, err := truetype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse font %s: %w", name, err)
+	}
+	fm.fontCache.Store(name, f)
+	return f, nil
+}
+
+// embeddedFont parses an embedded Go font once per process
+func embeddedFont(name string) (*truetype.Font, error) {
+	if cached, ok := parsedEmbedded.Load(name); ok {
+		return cached.(*truetype.Font), nil
+	}
+	data, exists := embeddedFonts[name]
+	if !exists {
+		data = embeddedFonts["regular"]
+	}
+	f, err := truetype.Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse embedded font %s: %w", name, err)
+	}
+	parsedEmbedded.Store(name, f)
+	return f, nil
+}
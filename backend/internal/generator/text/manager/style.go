@@ -8,11 +8,13 @@ import (
 
 type StyleManager struct {
 	defaultStyles map[types.CardElement]Style
+	overrides     map[types.CardElement]Style
 }
 
 type Style struct {
 	FontName    string
 	Size        float64
+	MinSize     float64 // smallest size tried when fitting text to its bounds
 	Color       color.Color
 	Alignment   gg.Align
 	LineSpacing float64
@@ -27,7 +29,30 @@ func NewStyleManager() *StyleManager {
 			types.ElementTitle: {
 				FontName:    "bold",
 				Size:        72,
-				Color:       color.Black,
+				MinSize:     40,
+				Color:       color.White,
+				Alignment:   gg.AlignLeft,
+				LineSpacing: 1.0,
+				AnchorX:     0.0,
+				AnchorY:     0.5,
+				Wrap:        false,
+			},
+			types.ElementCost: {
+				FontName:    "bold",
+				Size:        72,
+				MinSize:     40,
+				Color:       color.White,
+				Alignment:   gg.AlignRight,
+				LineSpacing: 1.0,
+				AnchorX:     1.0,
+				AnchorY:     0.5,
+				Wrap:        false,
+			},
+			types.ElementType: {
+				FontName:    "bold",
+				Size:        56,
+				MinSize:     32,
+				Color:       color.White,
 				Alignment:   gg.AlignCenter,
 				LineSpacing: 1.0,
 				AnchorX:     0.5,
@@ -37,21 +62,63 @@ func NewStyleManager() *StyleManager {
 			types.ElementEffect: {
 				FontName:    "regular",
 				Size:        48,
-				Color:       color.Black,
+				MinSize:     24,
+				Color:       color.White,
 				Alignment:   gg.AlignLeft,
 				LineSpacing: 1.5,
 				AnchorX:     0.0,
 				AnchorY:     0.0,
 				Wrap:        true,
 			},
-			// Add other element styles...
+			types.ElementAttack: {
+				FontName:    "bold",
+				Size:        64,
+				MinSize:     32,
+				Color:       color.White,
+				Alignment:   gg.AlignCenter,
+				LineSpacing: 1.0,
+				AnchorX:     0.5,
+				AnchorY:     0.5,
+				Wrap:        false,
+			},
+			types.ElementDefense: {
+				FontName:    "bold",
+				Size:        64,
+				MinSize:     32,
+				Color:       color.White,
+				Alignment:   gg.AlignCenter,
+				LineSpacing: 1.0,
+				AnchorX:     0.5,
+				AnchorY:     0.5,
+				Wrap:        false,
+			},
+			types.ElementCollector: {
+				FontName:    "regular",
+				Size:        32,
+				MinSize:     20,
+				Color:       color.White,
+				Alignment:   gg.AlignLeft,
+				LineSpacing: 1.0,
+				AnchorX:     0.0,
+				AnchorY:     0.5,
+				Wrap:        false,
+			},
 		},
+		overrides: make(map[types.CardElement]Style),
 	}
 }
 
 func (sm *StyleManager) GetStyle(element types.CardElement) Style {
+	if style, exists := sm.overrides[element]; exists {
+		return style
+	}
 	if style, exists := sm.defaultStyles[element]; exists {
 		return style
 	}
 	return sm.defaultStyles[types.ElementEffect] // Default fallback
 }
+
+// SetStyle overrides the style of an element
+func (sm *StyleManager) SetStyle(element types.CardElement, style Style) {
+	sm.overrides[element] = style
+}
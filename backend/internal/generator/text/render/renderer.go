@@ -11,6 +11,9 @@ import (
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/text/types"
 )
 
+// fitStep is how much the font size shrinks per attempt when fitting text
+const fitStep = 2
+
 type Renderer struct {
 	context   *gg.Context
 	fontMgr   *manager.FontManager
@@ -18,15 +21,27 @@ type Renderer struct {
 	styleMgr  *manager.StyleManager
 }
 
+// NewRenderer creates a renderer for the image. An *image.RGBA is drawn on in place;
+// other images are copied, and the result is available from GetImage.
 func NewRenderer(img image.Image) (*Renderer, error) {
-	bounds := img.Bounds()
-	dc := gg.NewContext(bounds.Dx(), bounds.Dy())
-	dc.DrawImage(img, 0, 0)
-
 	fontMgr, err := manager.NewFontManager()
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize font manager: %w", err)
 	}
+	return NewRendererWithFonts(img, fontMgr), nil
+}
+
+// NewRendererWithFonts is NewRenderer drawing with a shared font manager, so fonts are parsed once
+func NewRendererWithFonts(img image.Image, fontMgr *manager.FontManager) *Renderer {
+	bounds := img.Bounds()
+
+	var dc *gg.Context
+	if rgba, ok := img.(*image.RGBA); ok && bounds.Min == (image.Point{}) {
+		dc = gg.NewContextForRGBA(rgba)
+	} else {
+		dc = gg.NewContext(bounds.Dx(), bounds.Dy())
+		dc.DrawImage(img, -bounds.Min.X, -bounds.Min.Y)
+	}
 
 	layoutMgr := manager.NewLayoutManager(bounds)
 	styleMgr := manager.NewStyleManager()
@@ -36,51 +51,102 @@ func NewRenderer(img image.Image) (*Renderer, error) {
 		fontMgr:   fontMgr,
 		layoutMgr: layoutMgr,
 		styleMgr:  styleMgr,
-	}, nil
+	}
+}
+
+// SetBounds places an element, overriding the default layout
+func (r *Renderer) SetBounds(element types.CardElement, bounds image.Rectangle) {
+	r.layoutMgr.SetCustomBounds(element, bounds)
+}
+
+// Style returns the style used for an element
+func (r *Renderer) Style(element types.CardElement) manager.Style {
+	return r.styleMgr.GetStyle(element)
+}
+
+// SetStyle overrides the style used for an element
+func (r *Renderer) SetStyle(element types.CardElement, style manager.Style) {
+	r.styleMgr.SetStyle(element, style)
+}
+
+// HasFont reports whether the named font is available to elements
+func (r *Renderer) HasFont(name string) bool {
+	return r.fontMgr.HasFont(name)
 }
 
 // RenderElement renders a single card element
 func (r *Renderer) RenderElement(element types.CardElement, text string) error {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+
 	// Get configurations from managers
 	config := r.layoutMgr.GetTextConfiguration(element)
+	if config.Bounds.Empty() {
+		return fmt.Errorf("no bounds for element %s", element)
+	}
 	style := r.styleMgr.GetStyle(element)
-	fontPath := r.fontMgr.GetFontPath(style.FontName)
 
-	// Calculate best font size that fits the bounds
-	bestSize := r.fitTextSize(text, config.Bounds, style, fontPath)
-
-	// Load font face
-	if err := r.context.LoadFontFace(fontPath, bestSize); err != nil {
-		return fmt.Errorf("failed to load font: %w", err)
+	// Calculate best font size that fits the bounds and load its face
+	size, err := r.fitTextSize(text, config.Bounds, style)
+	if err != nil {
+		return err
 	}
 
 	// Set text color
 	r.context.SetColor(style.Color)
 
 	// Draw text based on configuration
-	if style.SingleLine {
+	if !style.Wrap {
 		return r.renderSingleLine(text, config.Bounds, style)
 	}
-	return r.renderMultiLine(text, config.Bounds, style)
+	return r.renderMultiLine(text, config.Bounds, size, style)
 }
 
-func (r *Renderer) fitTextSize(text string, bounds image.Rectangle, style manager.Style, fontPath string) float64 {
-	for size := style.Size; size >= style.MinFontSize; size-- {
-		if err := r.context.LoadFontFace(fontPath, size); err != nil {
-			continue
+// MeasureElement returns the width of the text at the size it would be rendered with
+func (r *Renderer) MeasureElement(element types.CardElement, text string) (float64, error) {
+	config := r.layoutMgr.GetTextConfiguration(element)
+	style := r.styleMgr.GetStyle(element)
+	if _, err := r.fitTextSize(text, config.Bounds, style); err != nil {
+		return 0, err
+	}
+	width, _ := r.context.MeasureString(text)
+	return width, nil
+}
+
+// fitTextSize loads the largest font size, down to the style's minimum, at which the text fits the bounds
+func (r *Renderer) fitTextSize(text string, bounds image.Rectangle, style manager.Style) (float64, error) {
+	minSize := style.MinSize
+	if minSize <= 0 || minSize > style.Size {
+		minSize = style.Size
+	}
+
+	for size := style.Size; size >= minSize; size -= fitStep {
+		if err := r.loadFace(style.FontName, size); err != nil {
+			return 0, err
 		}
 
-		if style.SingleLine {
+		if !style.Wrap {
 			if r.fitsInSingleLine(text, bounds) {
-				return size
+				return size, nil
 			}
 		} else {
-			if r.fitsInMultiLine(text, bounds, style.LineSpacing) {
-				return size
+			if r.fitsInMultiLine(text, bounds, size, style.LineSpacing) {
+				return size, nil
 			}
 		}
 	}
-	return style.MinFontSize
+	return minSize, r.loadFace(style.FontName, minSize)
+}
+
+// loadFace sets the drawing font
+func (r *Renderer) loadFace(name string, size float64) error {
+	face, err := r.fontMgr.Face(name, size)
+	if err != nil {
+		return fmt.Errorf("failed to load font: %w", err)
+	}
+	r.context.SetFontFace(face)
+	return nil
 }
 
 func (r *Renderer) fitsInSingleLine(text string, bounds image.Rectangle) bool {
@@ -88,11 +154,14 @@ func (r *Renderer) fitsInSingleLine(text string, bounds image.Rectangle) bool {
 	return int(width) <= bounds.Dx() && int(height) <= bounds.Dy()
 }
 
-func (r *Renderer) fitsInMultiLine(text string, bounds image.Rectangle, lineSpacing float64) bool {
+func (r *Renderer) fitsInMultiLine(text string, bounds image.Rectangle, size, lineSpacing float64) bool {
 	lines := r.measureWrappedText(text, float64(bounds.Dx()))
-	fontSize := r.context.FontSize()
-	totalHeight := float64(len(lines)) * fontSize * lineSpacing
-	return int(totalHeight) <= bounds.Dy()
+	for _, line := range lines {
+		if width, _ := r.context.MeasureString(line); width > float64(bounds.Dx()) {
+			return false // a single word is wider than the bounds
+		}
+	}
+	return int(textHeight(len(lines), size, lineSpacing)) <= bounds.Dy()
 }
 
 func (r *Renderer) renderSingleLine(text string, bounds image.Rectangle, style manager.Style) error {
@@ -104,41 +173,55 @@ func (r *Renderer) renderSingleLine(text string, bounds image.Rectangle, style m
 	return nil
 }
 
-func (r *Renderer) renderMultiLine(text string, bounds image.Rectangle, style manager.Style) error {
-	r.context.DrawStringWrapped(
-		text,
-		float64(bounds.Min.X),
-		float64(bounds.Min.Y),
-		style.AnchorX,
-		style.AnchorY,
-		float64(bounds.Dx()),
-		style.LineSpacing,
-		style.Alignment,
-	)
+// renderMultiLine draws wrapped text centered vertically in the bounds
+func (r *Renderer) renderMultiLine(text string, bounds image.Rectangle, size float64, style manager.Style) error {
+	lines := r.measureWrappedText(text, float64(bounds.Dx()))
+	lineHeight := size * style.LineSpacing
+
+	y := float64(bounds.Min.Y) + (float64(bounds.Dy())-textHeight(len(lines), size, style.LineSpacing))/2 + size
+	for _, line := range lines {
+		if line != "" {
+			width, _ := r.context.MeasureString(line)
+			r.context.DrawString(line, r.calculateX(bounds, width, style.Alignment), y)
+		}
+		y += lineHeight
+	}
 	return nil
 }
 
+// measureWrappedText wraps each paragraph of the text to the width; blank paragraphs become empty lines
 func (r *Renderer) measureWrappedText(text string, maxWidth float64) []string {
 	var lines []string
-	words := strings.Fields(text)
-	if len(words) == 0 {
-		return lines
-	}
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
 
-	currentLine := words[0]
-	for _, word := range words[1:] {
-		width, _ := r.context.MeasureString(currentLine + " " + word)
-		if width <= maxWidth {
-			currentLine += " " + word
-		} else {
-			lines = append(lines, currentLine)
-			currentLine = word
+		currentLine := words[0]
+		for _, word := range words[1:] {
+			width, _ := r.context.MeasureString(currentLine + " " + word)
+			if width <= maxWidth {
+				currentLine += " " + word
+			} else {
+				lines = append(lines, currentLine)
+				currentLine = word
+			}
 		}
+		lines = append(lines, currentLine)
 	}
-	lines = append(lines, currentLine)
 	return lines
 }
 
+// textHeight is the height of a block of lines, without spacing after the last line
+func textHeight(lines int, size, lineSpacing float64) float64 {
+	if lines == 0 {
+		return 0
+	}
+	return float64(lines-1)*size*lineSpacing + size
+}
+
 func (r *Renderer) calculateX(bounds image.Rectangle, width float64, align gg.Align) float64 {
 	switch align {
 	case gg.AlignCenter:
@@ -1,9 +1,18 @@
 package text
 
 import (
+	"fmt"
 	"image"
+	"strconv"
+	"strings"
+
+	"github.com/fogleman/gg"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/base"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/text/manager"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/text/render"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/text/types"
 )
 
 // TextProcessor defines how card text should be rendered
@@ -12,18 +21,206 @@ type TextProcessor interface {
 	RenderText(img *image.RGBA, data *card.CardDTO, bounds map[string]image.Rectangle) error
 }
 
-// basicTextProcessor implements TextProcessor with basic text rendering
-type basicTextProcessor struct{}
+// StyledTextProcessor renders text with the styles a template sets for its text bounds
+type StyledTextProcessor interface {
+	RenderStyledText(img *image.RGBA, data *card.CardDTO, bounds map[string]image.Rectangle, styles map[string]base.TextStyle) error
+}
+
+// boundElements maps template text bound names to the elements rendered in them, in drawing order
+var boundElements = []struct {
+	name    string
+	element types.CardElement
+}{
+	{"cost", types.ElementCost},
+	{"name", types.ElementTitle},
+	{"type", types.ElementType},
+	{"effect", types.ElementEffect},
+	{"attack", types.ElementAttack},
+	{"defense", types.ElementDefense},
+	{"collector", types.ElementCollector},
+}
+
+// costPadding separates the name from a cost drawn in the same bounds
+const costPadding = 24
+
+// basicTextProcessor implements TextProcessor with the gg-based text renderer.
+// Its fonts are loaded and fingerprinted once, when it is created.
+type basicTextProcessor struct {
+	fontMgr     *manager.FontManager
+	fingerprint string
+}
 
 // NewTextProcessor creates a new basic text processor
 func NewTextProcessor() (TextProcessor, error) {
-	return &basicTextProcessor{}, nil
+	fontMgr, err := manager.NewFontManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize font manager: %w", err)
+	}
+	fingerprint, err := fontMgr.Fingerprint()
+	if err != nil {
+		return nil, err
+	}
+	return &basicTextProcessor{fontMgr: fontMgr, fingerprint: fingerprint}, nil
 }
 
-// RenderText implements TextProcessor interface
+// RenderText draws every element that has bounds, fitting and wrapping text to them.
+// The effect's font size is scaled by its complexity tier.
 func (t *basicTextProcessor) RenderText(img *image.RGBA, data *card.CardDTO, bounds map[string]image.Rectangle) error {
-	// For now, this is a placeholder implementation
-	// In a real implementation, this would render the card's text fields
-	// using a font library like freetype or golang.org/x/image/font
+	return t.RenderStyledText(img, data, bounds, nil)
+}
+
+// RenderStyledText renders text like RenderText, first applying the template's styles over the defaults
+func (t *basicTextProcessor) RenderStyledText(img *image.RGBA, data *card.CardDTO, bounds map[string]image.Rectangle, styles map[string]base.TextStyle) error {
+	renderer := render.NewRendererWithFonts(img, t.fontMgr)
+
+	texts := ElementTexts(data)
+	for _, be := range boundElements {
+		if rect, ok := bounds[be.name]; ok {
+			renderer.SetBounds(be.element, rect)
+		}
+		if style, ok := styles[be.name]; ok {
+			applyStyle(renderer, be.element, style)
+		}
+	}
+
+	effectStyle := renderer.Style(types.ElementEffect)
+	tier := EffectFontTier(data)
+	effectStyle.Size *= tier.Scale
+	effectStyle.MinSize *= tier.Scale
+	renderer.SetStyle(types.ElementEffect, effectStyle)
+
+	// A cost sharing the name's bounds is drawn at the right, so the name must stop short of it
+	if nameRect, ok := bounds["name"]; ok {
+		if costRect, ok := bounds["cost"]; ok && costRect.Overlaps(nameRect) && texts[types.ElementCost] != "" {
+			width, err := renderer.MeasureElement(types.ElementCost, texts[types.ElementCost])
+			if err != nil {
+				return fmt.Errorf("failed to measure cost: %w", err)
+			}
+			nameRect.Max.X = min(nameRect.Max.X, costRect.Max.X-int(width)-costPadding)
+			renderer.SetBounds(types.ElementTitle, nameRect)
+		}
+	}
+
+	for _, be := range boundElements {
+		if _, ok := bounds[be.name]; !ok {
+			continue
+		}
+		if err := renderer.RenderElement(be.element, texts[be.element]); err != nil {
+			return fmt.Errorf("failed to render %s: %w", be.name, err)
+		}
+	}
 	return nil
 }
+
+// applyStyle overrides an element's style with the fields a template sets. A font the renderer
+// does not have keeps the element's font; the minimum size scales with the size.
+func applyStyle(renderer *render.Renderer, element types.CardElement, s base.TextStyle) {
+	style := renderer.Style(element)
+	if font := strings.ToLower(s.Font); font != "" && renderer.HasFont(font) {
+		style.FontName = font
+	}
+	if s.Size > 0 {
+		style.MinSize *= s.Size / style.Size
+		style.Size = s.Size
+	}
+	if s.Color != nil {
+		style.Color = s.Color
+	}
+	switch s.Align {
+	case "left":
+		style.Alignment, style.AnchorX = gg.AlignLeft, 0
+	case "center":
+		style.Alignment, style.AnchorX = gg.AlignCenter, 0.5
+	case "right":
+		style.Alignment, style.AnchorX = gg.AlignRight, 1
+	}
+	switch s.VerticalAlign {
+	case "top":
+		style.AnchorY = 0
+	case "center":
+		style.AnchorY = 0.5
+	case "bottom":
+		style.AnchorY = 1
+	}
+	if s.OneLine {
+		style.Wrap = false
+	}
+	renderer.SetStyle(element, style)
+}
+
+// Fingerprint identifies the fonts text is rendered with; it is the same for every card
+func (t *basicTextProcessor) Fingerprint(data *card.CardDTO) (string, error) {
+	return t.fingerprint, nil
+}
+
+// ElementTexts returns the text drawn for each element of a card; empty text is not drawn
+func ElementTexts(data *card.CardDTO) map[types.CardElement]string {
+	texts := map[types.CardElement]string{
+		types.ElementTitle:     data.Name,
+		types.ElementCost:      costText(data.Cost),
+		types.ElementType:      typeLine(data),
+		types.ElementEffect:    effectText(data),
+		types.ElementCollector: collectorLine(data),
+	}
+	if data.Type == card.TypeCreature {
+		texts[types.ElementAttack] = strconv.Itoa(data.Attack)
+		texts[types.ElementDefense] = strconv.Itoa(data.Defense)
+	}
+	return texts
+}
+
+// costText formats a cost, with -1 shown as X
+func costText(cost int) string {
+	if cost == -1 {
+		return "X"
+	}
+	return strconv.Itoa(cost)
+}
+
+// typeLine is the card type followed by its trait, e.g. "Creature — Dragon"
+func typeLine(data *card.CardDTO) string {
+	line := string(data.Type)
+	if data.IsEquipment {
+		line += " — Equipment"
+	}
+	if data.Trait != "" {
+		line += " — " + data.Trait
+	}
+	return line
+}
+
+// effectText prefixes the effect with keywords it does not already mention
+func effectText(data *card.CardDTO) string {
+	var missing []string
+	upper := strings.ToUpper(data.Effect)
+	for _, keyword := range data.Keywords {
+		if keyword != "" && !strings.Contains(upper, strings.ToUpper(keyword)) {
+			missing = append(missing, strings.ToUpper(keyword))
+		}
+	}
+	if len(missing) == 0 {
+		return data.Effect
+	}
+	return strings.Join(missing, ", ") + "\n" + data.Effect
+}
+
+// collectorLine joins the set, number, rarity and artist metadata, e.g. "CORE #12 · rare · Illus. Ana"
+func collectorLine(data *card.CardDTO) string {
+	meta := data.Metadata
+	var parts []string
+
+	set := strings.TrimSpace(meta["set"])
+	if number := strings.TrimSpace(meta["number"]); number != "" {
+		set = strings.TrimSpace(set + " #" + number)
+	}
+	if set != "" {
+		parts = append(parts, set)
+	}
+	if rarity := strings.TrimSpace(meta["rarity"]); rarity != "" {
+		parts = append(parts, rarity)
+	}
+	if artist := strings.TrimSpace(meta["artist"]); artist != "" {
+		parts = append(parts, "Illus. "+artist)
+	}
+	return strings.Join(parts, " · ")
+}
@@ -0,0 +1,109 @@
+package text
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/text/types"
+)
+
+func TestElementTexts(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    *card.CardDTO
+		element types.CardElement
+		want    string
+	}{
+		{"X cost", &card.CardDTO{Cost: -1}, types.ElementCost, "X"},
+		{"Type line with trait", &card.CardDTO{Type: card.TypeCreature, Trait: "Dragon"}, types.ElementType, "Creature — Dragon"},
+		{"Equipment", &card.CardDTO{Type: card.TypeArtifact, IsEquipment: true}, types.ElementType, "Artifact — Equipment"},
+		{"Creature stats", &card.CardDTO{Type: card.TypeCreature, Attack: 3, Defense: 5}, types.ElementDefense, "5"},
+		{"No stats for spells", &card.CardDTO{Type: card.TypeSpell}, types.ElementAttack, ""},
+		{"Missing keywords prefixed", &card.CardDTO{Effect: "Draw a card.", Keywords: []string{"haste", "Draw"}}, types.ElementEffect, "HASTE\nDraw a card."},
+		{"Collector line", &card.CardDTO{Metadata: map[string]string{"set": "CORE", "number": "12", "artist": "Ana"}}, types.ElementCollector, "CORE #12 · Illus. Ana"},
+		{"No collector metadata", &card.CardDTO{}, types.ElementCollector, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ElementTexts(tt.data)[tt.element]; got != tt.want {
+				t.Errorf("text = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderText(t *testing.T) {
+	bounds := map[string]image.Rectangle{
+		"name":    image.Rect(20, 20, 580, 80),
+		"cost":    image.Rect(20, 20, 580, 80),
+		"effect":  image.Rect(20, 120, 580, 320),
+		"type":    image.Rect(20, 340, 580, 390),
+		"attack":  image.Rect(20, 400, 80, 450),
+		"defense": image.Rect(520, 400, 580, 450),
+	}
+	data := &card.CardDTO{
+		Type:    card.TypeCreature,
+		Name:    "A Remarkably Long Creature Name That Must Shrink",
+		Cost:    7,
+		Effect:  "When this creature enters play, draw two cards. If you control another creature, gain 3 life and each opponent loses 3 life.",
+		Attack:  4,
+		Defense: 6,
+	}
+
+	processor, err := NewTextProcessor()
+	if err != nil {
+		t.Fatalf("NewTextProcessor() error = %v", err)
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 600, 480))
+	draw.Draw(img, img.Bounds(), image.NewUniform(color.Black), image.Point{}, draw.Src)
+	if err := processor.RenderText(img, data, bounds); err != nil {
+		t.Fatalf("RenderText() error = %v", err)
+	}
+
+	for name, rect := range bounds {
+		if !hasInk(img, rect) {
+			t.Errorf("nothing drawn in %s bounds", name)
+		}
+	}
+	if hasInk(img, image.Rect(0, 90, 600, 115)) {
+		t.Error("text drawn outside its bounds")
+	}
+}
+
+// hasInk reports whether any pixel in the rectangle is not black
+func hasInk(img *image.RGBA, rect image.Rectangle) bool {
+	for y := rect.Min.Y; y < rect.Max.Y; y++ {
+		for x := rect.Min.X; x < rect.Max.X; x++ {
+			if r, g, b, _ := img.At(x, y).RGBA(); r|g|b != 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestFingerprint(t *testing.T) {
+	processor, err := NewTextProcessor()
+	if err != nil {
+		t.Fatalf("NewTextProcessor() error = %v", err)
+	}
+	fingerprinter := processor.(interface {
+		Fingerprint(data *card.CardDTO) (string, error)
+	})
+
+	first, err := fingerprinter.Fingerprint(&card.CardDTO{Name: "Squire"})
+	if err != nil {
+		t.Fatalf("Fingerprint() error = %v", err)
+	}
+	second, err := fingerprinter.Fingerprint(&card.CardDTO{Name: "Smite"})
+	if err != nil {
+		t.Fatalf("Fingerprint() error = %v", err)
+	}
+	if first == "" || first != second {
+		t.Errorf("Fingerprint() = %q and %q, want the same non-empty font fingerprint for every card", first, second)
+	}
+}
@@ -0,0 +1,36 @@
+package text
+
+import (
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/complexity"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+// FontTier is a size step for effect text, chosen from the effect's complexity
+type FontTier struct {
+	Name     string
+	Scale    float64 // multiplier applied to the effect style's font size
+	MaxScore int     // highest complexity score rendered at this tier; 0 means no limit
+	MaxWords int     // highest word count rendered at this tier; 0 means no limit
+}
+
+// FontTiers are ordered from largest to smallest text
+var FontTiers = []FontTier{
+	{Name: "normal", Scale: 1.0, MaxScore: 6, MaxWords: 25},
+	{Name: "small", Scale: 0.85, MaxScore: 10, MaxWords: 45},
+	{Name: "tiny", Scale: 0.7},
+}
+
+// SelectFontTier returns the largest tier whose limits the effect's complexity fits within
+func SelectFontTier(c complexity.Complexity) FontTier {
+	for _, tier := range FontTiers {
+		if (tier.MaxScore == 0 || c.Score <= tier.MaxScore) && (tier.MaxWords == 0 || c.Words <= tier.MaxWords) {
+			return tier
+		}
+	}
+	return FontTiers[len(FontTiers)-1]
+}
+
+// EffectFontTier selects the font tier for a card's effect text
+func EffectFontTier(data *card.CardDTO) FontTier {
+	return SelectFontTier(complexity.Analyze(data.Effect))
+}
@@ -0,0 +1,29 @@
+package text
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+func TestEffectFontTier(t *testing.T) {
+	tests := []struct {
+		name   string
+		effect string
+		want   string
+	}{
+		{"Short effect", "Draw 2 cards.", "normal"},
+		{"Conditional effect", "If you control a Goblin, you may draw 2 cards. When a creature dies, gain 1 life.", "small"},
+		{"Long effect", strings.Repeat("Gain 1 life. ", 20), "tiny"},
+		{"Empty effect", "", "normal"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EffectFontTier(&card.CardDTO{Effect: tt.effect}); got.Name != tt.want {
+				t.Errorf("EffectFontTier() = %s, want %s", got.Name, tt.want)
+			}
+		})
+	}
+}
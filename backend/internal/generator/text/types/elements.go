@@ -11,6 +11,10 @@ const (
 	ElementKeyword CardElement = "keyword"
 	ElementType    CardElement = "type"
 	ElementSubtype CardElement = "subtype"
+
+	ElementAttack    CardElement = "attack"
+	ElementDefense   CardElement = "defense"
+	ElementCollector CardElement = "collector"
 )
 
 // ElementAttributes defines properties for each card element
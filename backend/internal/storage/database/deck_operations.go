@@ -0,0 +1,157 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/deck"
+	"github.com/jackc/pgx/v5"
+)
+
+// SaveDeck stores a deck, replacing any deck with the same ID, and returns its ID
+func (s *PostgresStore) SaveDeck(d *deck.Deck) (string, error) {
+	if d.Name == "" {
+		return "", fmt.Errorf("invalid deck: name cannot be empty")
+	}
+	if d.ID == "" {
+		d.ID = fmt.Sprintf("Deck-%s", d.Name)
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	d.UpdatedAt = time.Now()
+
+	ctx := context.Background()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, `
+		INSERT INTO decks (id, name, description, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (id) DO UPDATE
+		SET name = EXCLUDED.name, description = EXCLUDED.description, updated_at = EXCLUDED.updated_at`,
+		d.ID, d.Name, d.Description, d.CreatedAt, d.UpdatedAt,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to save deck: %w", err)
+	}
+
+	if _, err = tx.Exec(ctx, `DELETE FROM deck_cards WHERE deck_id = $1`, d.ID); err != nil {
+		return "", fmt.Errorf("failed to delete deck cards: %w", err)
+	}
+	if err = insertDeckEntries(ctx, tx, d.ID, d.Cards, false); err != nil {
+		return "", err
+	}
+	if err = insertDeckEntries(ctx, tx, d.ID, d.Sideboard, true); err != nil {
+		return "", err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return "", fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return d.ID, nil
+}
+
+// insertDeckEntries stores the entries of a deck or its sideboard in order
+func insertDeckEntries(ctx context.Context, tx pgx.Tx, deckID string, entries []deck.Entry, sideboard bool) error {
+	for i, entry := range entries {
+		_, err := tx.Exec(ctx,
+			`INSERT INTO deck_cards (deck_id, position, card_id, count, sideboard) VALUES ($1, $2, $3, $4, $5)`,
+			deckID, i, entry.CardID, entry.Count, sideboard,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save deck card %s: %w", entry.CardID, err)
+		}
+	}
+	return nil
+}
+
+// LoadDeck retrieves a deck by its ID
+func (s *PostgresStore) LoadDeck(id string) (*deck.Deck, error) {
+	ctx := context.Background()
+	d := &deck.Deck{ID: id, Cards: make([]deck.Entry, 0)}
+
+	err := s.pool.QueryRow(ctx,
+		`SELECT name, COALESCE(description, ''), created_at, updated_at FROM decks WHERE id = $1`, id,
+	).Scan(&d.Name, &d.Description, &d.CreatedAt, &d.UpdatedAt)
+	if err == pgx.ErrNoRows {
+		return nil, fmt.Errorf("deck not found: %s", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deck: %w", err)
+	}
+
+	rows, err := s.pool.Query(ctx,
+		`SELECT card_id, count, sideboard FROM deck_cards WHERE deck_id = $1 ORDER BY sideboard, position`, id,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query deck cards: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry deck.Entry
+		var sideboard bool
+		if err := rows.Scan(&entry.CardID, &entry.Count, &sideboard); err != nil {
+			return nil, fmt.Errorf("failed to scan deck card: %w", err)
+		}
+		if sideboard {
+			d.Sideboard = append(d.Sideboard, entry)
+		} else {
+			d.Cards = append(d.Cards, entry)
+		}
+	}
+	return d, rows.Err()
+}
+
+// ListDecks returns all stored decks
+func (s *PostgresStore) ListDecks() ([]*deck.Deck, error) {
+	rows, err := s.pool.Query(context.Background(), `SELECT id FROM decks ORDER BY name, id`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query decks: %w", err)
+	}
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan deck ID: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list decks: %w", err)
+	}
+
+	decks := make([]*deck.Deck, 0, len(ids))
+	for _, id := range ids {
+		d, err := s.LoadDeck(id)
+		if err != nil {
+			return nil, err
+		}
+		decks = append(decks, d)
+	}
+	return decks, nil
+}
+
+// DeleteDeck removes a deck and its cards by its ID
+func (s *PostgresStore) DeleteDeck(id string) error {
+	commandTag, err := s.pool.Exec(context.Background(), `DELETE FROM decks WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete deck: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("deck not found: %s", id)
+	}
+	return nil
+}
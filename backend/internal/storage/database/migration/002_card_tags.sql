@@ -0,0 +1,17 @@
+-- Card Tags Table (generated and manual tags)
+CREATE TABLE card_tags (
+    id SERIAL PRIMARY KEY,
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    tag VARCHAR(100) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 0,
+    source VARCHAR(10) NOT NULL CHECK (source IN ('auto', 'manual')),
+    rule VARCHAR(100),
+    rule_version VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (card_id, tag, source)
+);
+
+-- Indexes for tag lookups and queries
+CREATE INDEX idx_card_tags_card_id ON card_tags(card_id);
+CREATE INDEX idx_card_tags_tag ON card_tags(tag);
@@ -0,0 +1,18 @@
+-- Decks Table
+CREATE TABLE decks (
+    id VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Deck Cards Table (main deck and sideboard entries, in deck order)
+CREATE TABLE deck_cards (
+    deck_id VARCHAR(100) NOT NULL REFERENCES decks(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    card_id VARCHAR(100) NOT NULL,
+    count INTEGER NOT NULL CHECK (count > 0),
+    sideboard BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (deck_id, sideboard, position)
+);
@@ -116,11 +116,46 @@ CREATE TABLE IF NOT EXISTS card_set_cards (
     PRIMARY KEY (set_id, card_id)
 );
 
+-- Card Tags Table (generated and manual tags)
+CREATE TABLE IF NOT EXISTS card_tags (
+    id SERIAL PRIMARY KEY,
+    card_id INTEGER NOT NULL REFERENCES cards(id) ON DELETE CASCADE,
+    tag VARCHAR(100) NOT NULL,
+    category VARCHAR(50) NOT NULL,
+    weight INTEGER NOT NULL DEFAULT 0,
+    source VARCHAR(10) NOT NULL CHECK (source IN ('auto', 'manual')),
+    rule VARCHAR(100),
+    rule_version VARCHAR(64),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (card_id, tag, source)
+);
+
+-- Decks Table
+CREATE TABLE IF NOT EXISTS decks (
+    id VARCHAR(100) PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Deck Cards Table (main deck and sideboard entries, in deck order)
+CREATE TABLE IF NOT EXISTS deck_cards (
+    deck_id VARCHAR(100) NOT NULL REFERENCES decks(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    card_id VARCHAR(100) NOT NULL,
+    count INTEGER NOT NULL CHECK (count > 0),
+    sideboard BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (deck_id, sideboard, position)
+);
+
 -- Indexes for performance
 CREATE INDEX IF NOT EXISTS idx_cards_type_id ON cards(type_id);
 CREATE INDEX IF NOT EXISTS idx_card_keywords_card_id ON card_keywords(card_id);
 CREATE INDEX IF NOT EXISTS idx_card_metadata_card_id ON card_metadata(card_id);
 CREATE INDEX IF NOT EXISTS idx_card_set_cards_set_id ON card_set_cards(set_id);
+CREATE INDEX IF NOT EXISTS idx_card_tags_card_id ON card_tags(card_id);
+CREATE INDEX IF NOT EXISTS idx_card_tags_tag ON card_tags(tag);
 
 -- Trigger function to update the updated_at timestamp
 CREATE OR REPLACE FUNCTION update_updated_at_column()
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 )
@@ -566,7 +567,7 @@ func parseCardID(id string) (int, error) {
 	// Try to parse as integer
 	cardID, err = stringToInt(id)
 	if err != nil {
-		return 0, fmt.Errorf("invalid card ID: %s", id)
+		return 0, fmt.Errorf("invalid card ID: %s: %w", id, store.ErrInvalidKey)
 	}
 
 	return cardID, nil
@@ -613,6 +614,7 @@ func (s *PostgresStore) deleteCard(id string) error {
 		"anthem_cards",
 		"card_images",
 		"card_set_cards",
+		"card_tags",
 	}
 
 	for _, table := range tables {
@@ -0,0 +1,246 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+	"github.com/jackc/pgx/v5"
+)
+
+// ReplaceTags replaces every tag of the given source stored for a card
+func (s *PostgresStore) ReplaceTags(id string, source types.TagSource, tags []types.CardTag) error {
+	cardID, err := parseCardID(id)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	_, err = tx.Exec(ctx, `DELETE FROM card_tags WHERE card_id = $1 AND source = $2`, cardID, source)
+	if err != nil {
+		return fmt.Errorf("failed to delete card tags: %w", err)
+	}
+
+	for _, tag := range tags {
+		tag.Source = source
+		if err = insertTag(ctx, tx, cardID, tag); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// AddTag stores a tag, replacing any tag with the same name and source
+func (s *PostgresStore) AddTag(tag types.CardTag) error {
+	cardID, err := parseCardID(tag.CardID)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			tx.Rollback(ctx)
+		}
+	}()
+
+	if err = insertTag(ctx, tx, cardID, tag); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
+
+// insertTag upserts a single tag row
+func insertTag(ctx context.Context, tx pgx.Tx, cardID int, tag types.CardTag) error {
+	if tag.Name == "" {
+		return fmt.Errorf("invalid tag: name cannot be empty")
+	}
+	if tag.CreatedAt.IsZero() {
+		tag.CreatedAt = time.Now()
+	}
+
+	_, err := tx.Exec(
+		ctx,
+		`INSERT INTO card_tags (card_id, tag, category, weight, source, rule, rule_version, created_at)
+		VALUES ($1, $2, $3, $4, $5, NULLIF($6, ''), NULLIF($7, ''), $8)
+		ON CONFLICT (card_id, tag, source) DO UPDATE
+		SET category = EXCLUDED.category, weight = EXCLUDED.weight,
+			rule = EXCLUDED.rule, rule_version = EXCLUDED.rule_version`,
+		cardID, tag.Name, tag.Category, tag.Weight, tag.Source, tag.Rule, tag.RuleVersion, tag.CreatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to insert tag %s: %w", tag.Name, err)
+	}
+	return nil
+}
+
+// RemoveTag deletes a card's tag with the given name and source
+func (s *PostgresStore) RemoveTag(id, name string, source types.TagSource) error {
+	cardID, err := parseCardID(id)
+	if err != nil {
+		return err
+	}
+
+	commandTag, err := s.pool.Exec(
+		context.Background(),
+		`DELETE FROM card_tags WHERE card_id = $1 AND tag = $2 AND source = $3`,
+		cardID, name, source,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+	if commandTag.RowsAffected() == 0 {
+		return fmt.Errorf("tag not found: %s on card %s", name, id)
+	}
+	return nil
+}
+
+// ListTags returns every tag stored for a card; keys that are not card IDs have no tags
+func (s *PostgresStore) ListTags(id string) ([]types.CardTag, error) {
+	cardID, err := parseCardID(id)
+	if errors.Is(err, store.ErrInvalidKey) {
+		return []types.CardTag{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.pool.Query(
+		context.Background(),
+		`SELECT tag, category, weight, source, COALESCE(rule, ''), COALESCE(rule_version, ''), created_at
+		FROM card_tags WHERE card_id = $1
+		ORDER BY source, tag`,
+		cardID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query card tags: %w", err)
+	}
+	defer rows.Close()
+
+	tags := make([]types.CardTag, 0)
+	for rows.Next() {
+		tag := types.CardTag{CardID: id}
+		var category, source string
+		if err := rows.Scan(&tag.Name, &category, &tag.Weight, &source, &tag.Rule, &tag.RuleVersion, &tag.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan card tag: %w", err)
+		}
+		tag.Category = types.TagCategory(category)
+		tag.Source = types.TagSource(source)
+		tags = append(tags, tag)
+	}
+	return tags, rows.Err()
+}
+
+// FindCards returns the sorted IDs of cards whose tags satisfy the query
+func (s *PostgresStore) FindCards(query types.TagQuery) ([]string, error) {
+	args := []interface{}{string(query.Source)}
+	placeholder := func(terms []string) string {
+		args = append(args, nonNil(terms))
+		return fmt.Sprintf("$%d", len(args))
+	}
+
+	// Each tag (with its expansions) in All must match on its own; Any and None match any of their terms
+	having := []string{"COUNT(*) > 0"}
+	for _, name := range query.All {
+		having = append(having, fmt.Sprintf("COUNT(*) FILTER (WHERE tag = ANY(%s)) > 0", placeholder(query.Terms(name))))
+	}
+	if len(query.Any) > 0 {
+		having = append(having, fmt.Sprintf("COUNT(*) FILTER (WHERE tag = ANY(%s)) > 0", placeholder(queryTerms(query, query.Any))))
+	}
+	if len(query.None) > 0 {
+		having = append(having, fmt.Sprintf("COUNT(*) FILTER (WHERE tag = ANY(%s)) = 0", placeholder(queryTerms(query, query.None))))
+	}
+
+	rows, err := s.pool.Query(
+		context.Background(),
+		`SELECT card_id FROM card_tags
+		WHERE $1 = '' OR source = $1
+		GROUP BY card_id
+		HAVING `+strings.Join(having, " AND ")+`
+		ORDER BY card_id`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cards by tag: %w", err)
+	}
+	defer rows.Close()
+
+	ids := make([]string, 0)
+	for rows.Next() {
+		var cardID int
+		if err := rows.Scan(&cardID); err != nil {
+			return nil, fmt.Errorf("failed to scan card id: %w", err)
+		}
+		ids = append(ids, fmt.Sprintf("%d", cardID))
+	}
+	return ids, rows.Err()
+}
+
+// queryTerms flattens the queried names and their expansions into one list
+func queryTerms(query types.TagQuery, names []string) []string {
+	var terms []string
+	for _, name := range names {
+		terms = append(terms, query.Terms(name)...)
+	}
+	return terms
+}
+
+// RuleVersions returns the rule version of each card's auto tags
+func (s *PostgresStore) RuleVersions() (map[string]string, error) {
+	rows, err := s.pool.Query(
+		context.Background(),
+		`SELECT card_id, MAX(COALESCE(rule_version, '')) FROM card_tags
+		WHERE source = $1
+		GROUP BY card_id`,
+		types.TagSourceAuto,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query rule versions: %w", err)
+	}
+	defer rows.Close()
+
+	versions := make(map[string]string)
+	for rows.Next() {
+		var cardID int
+		var version string
+		if err := rows.Scan(&cardID, &version); err != nil {
+			return nil, fmt.Errorf("failed to scan rule version: %w", err)
+		}
+		versions[fmt.Sprintf("%d", cardID)] = version
+	}
+	return versions, rows.Err()
+}
+
+// nonNil turns a nil slice into an empty one so it encodes as an empty array
+func nonNil(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
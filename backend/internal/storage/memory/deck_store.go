@@ -0,0 +1,81 @@
+package memory
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/deck"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// DeckStore implements store.DeckStore interface with in-memory storage
+type DeckStore struct {
+	decks map[string]*deck.Deck
+	mutex sync.RWMutex
+}
+
+// NewDeckStore creates a new memory-based deck store
+func NewDeckStore() store.DeckStore {
+	return &DeckStore{
+		decks: make(map[string]*deck.Deck),
+	}
+}
+
+// generateDeckID creates an identifier for a deck that has none
+func generateDeckID(d *deck.Deck) string {
+	return fmt.Sprintf("Deck-%s", d.Name)
+}
+
+func (s *DeckStore) SaveDeck(d *deck.Deck) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if d.Name == "" {
+		return "", fmt.Errorf("invalid deck: name cannot be empty")
+	}
+
+	if d.ID == "" {
+		d.ID = generateDeckID(d)
+	}
+	if d.CreatedAt.IsZero() {
+		d.CreatedAt = time.Now()
+	}
+	d.UpdatedAt = time.Now()
+
+	s.decks[d.ID] = d
+	return d.ID, nil
+}
+
+func (s *DeckStore) LoadDeck(id string) (*deck.Deck, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	d, exists := s.decks[id]
+	if !exists {
+		return nil, fmt.Errorf("deck not found: %s", id)
+	}
+	return d, nil
+}
+
+func (s *DeckStore) ListDecks() ([]*deck.Deck, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	decks := make([]*deck.Deck, 0, len(s.decks))
+	for _, d := range s.decks {
+		decks = append(decks, d)
+	}
+	return decks, nil
+}
+
+func (s *DeckStore) DeleteDeck(id string) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if _, exists := s.decks[id]; !exists {
+		return fmt.Errorf("deck not found: %s", id)
+	}
+	delete(s.decks, id)
+	return nil
+}
@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// TagStore implements store.TagStore interface with in-memory storage
+type TagStore struct {
+	tags  map[string][]types.CardTag
+	mutex sync.RWMutex
+}
+
+// NewTagStore creates a new memory-based tag store
+func NewTagStore() store.TagStore {
+	return &TagStore{
+		tags: make(map[string][]types.CardTag),
+	}
+}
+
+func (s *TagStore) ReplaceTags(cardID string, source types.TagSource, tags []types.CardTag) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	kept := make([]types.CardTag, 0, len(s.tags[cardID])+len(tags))
+	for _, tag := range s.tags[cardID] {
+		if tag.Source != source {
+			kept = append(kept, tag)
+		}
+	}
+
+	now := time.Now()
+	for _, tag := range tags {
+		if tag.Name == "" {
+			return fmt.Errorf("invalid tag: name cannot be empty")
+		}
+		tag.CardID, tag.Source = cardID, source
+		if tag.CreatedAt.IsZero() {
+			tag.CreatedAt = now
+		}
+		kept = append(kept, tag)
+	}
+
+	s.tags[cardID] = kept
+	return nil
+}
+
+func (s *TagStore) AddTag(tag types.CardTag) error {
+	if tag.CardID == "" || tag.Name == "" {
+		return fmt.Errorf("invalid tag: card ID and name are required")
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if tag.CreatedAt.IsZero() {
+		tag.CreatedAt = time.Now()
+	}
+	for i, existing := range s.tags[tag.CardID] {
+		if existing.Name == tag.Name && existing.Source == tag.Source {
+			s.tags[tag.CardID][i] = tag
+			return nil
+		}
+	}
+	s.tags[tag.CardID] = append(s.tags[tag.CardID], tag)
+	return nil
+}
+
+func (s *TagStore) RemoveTag(cardID, name string, source types.TagSource) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for i, tag := range s.tags[cardID] {
+		if tag.Name == name && tag.Source == source {
+			s.tags[cardID] = append(s.tags[cardID][:i], s.tags[cardID][i+1:]...)
+			return nil
+		}
+	}
+	return fmt.Errorf("tag not found: %s on card %s", name, cardID)
+}
+
+func (s *TagStore) ListTags(cardID string) ([]types.CardTag, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	tags := make([]types.CardTag, len(s.tags[cardID]))
+	copy(tags, s.tags[cardID])
+	return tags, nil
+}
+
+func (s *TagStore) FindCards(query types.TagQuery) ([]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	ids := make([]string, 0)
+	for cardID, tags := range s.tags {
+		names := make(map[string]bool, len(tags))
+		for _, tag := range tags {
+			if query.Source == "" || tag.Source == query.Source {
+				names[tag.Name] = true
+			}
+		}
+		if len(names) > 0 && query.Matches(names) {
+			ids = append(ids, cardID)
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+func (s *TagStore) RuleVersions() (map[string]string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	versions := make(map[string]string)
+	for cardID, tags := range s.tags {
+		for _, tag := range tags {
+			if tag.Source == types.TagSourceAuto {
+				versions[cardID] = tag.RuleVersion
+				break
+			}
+		}
+	}
+	return versions, nil
+}
@@ -1,9 +1,17 @@
 package store
 
 import (
+	"errors"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/deck"
 )
 
+// ErrInvalidKey is returned by a store for a card key it cannot hold, such as a card name
+// where the store keys cards by numeric ID
+var ErrInvalidKey = errors.New("invalid card key")
+
 // Store defines the interface for card storage operations
 type Store interface {
 	// Save stores a card and returns its ID
@@ -21,3 +29,39 @@ type Store interface {
 	// Close cleans up any resources
 	Close() error
 }
+
+// DeckStore defines the interface for deck storage operations
+type DeckStore interface {
+	// SaveDeck stores a deck and returns its ID
+	SaveDeck(d *deck.Deck) (string, error)
+
+	// LoadDeck retrieves a deck by its ID
+	LoadDeck(id string) (*deck.Deck, error)
+
+	// ListDecks returns all stored decks
+	ListDecks() ([]*deck.Deck, error)
+
+	// DeleteDeck removes a deck by its ID
+	DeleteDeck(id string) error
+}
+
+// TagStore defines the interface for card tag storage operations
+type TagStore interface {
+	// ReplaceTags replaces every tag of the given source stored for a card
+	ReplaceTags(cardID string, source types.TagSource, tags []types.CardTag) error
+
+	// AddTag stores a tag, replacing any tag with the same name and source
+	AddTag(tag types.CardTag) error
+
+	// RemoveTag deletes a card's tag with the given name and source
+	RemoveTag(cardID, name string, source types.TagSource) error
+
+	// ListTags returns every tag stored for a card; a key the store cannot hold has none
+	ListTags(cardID string) ([]types.CardTag, error)
+
+	// FindCards returns the sorted IDs of cards whose tags satisfy the query
+	FindCards(query types.TagQuery) ([]string, error)
+
+	// RuleVersions returns the rule version of each card's auto tags
+	RuleVersions() (map[string]string, error)
+}
@@ -4,9 +4,15 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/llm"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/art"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/cache"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/parser"
 	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/database"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/memory"
 	"github.com/ControlYourPotatoes/card-generator/backend/pkg/config"
 	"github.com/ControlYourPotatoes/card-generator/backend/pkg/di"
@@ -43,7 +49,32 @@ func NewApplication(env string) (*Application, error) {
 
 	// Register card generator
 	if err := container.RegisterSingleton("cardGenerator", func() (generator.CardGenerator, error) {
-		return generator.NewCardGenerator()
+		artCfg := cfg.Generator.ArtProcessing
+		pipeline, err := art.NewPipeline(artCfg.ResizeAlgorithm)
+		if err != nil {
+			return nil, err
+		}
+		source := art.NewLocalSource(artCfg.ArtDirectory).WithMaxImageSize(artCfg.MaxImageSize)
+
+		var renderCache *cache.Cache
+		if cfg.Generator.EnableCaching {
+			renderCache, err = cache.New(cfg.Generator.CacheDirectory, cfg.Generator.CacheMaxSize)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		opts, err := outputOptions(cfg.Generator)
+		if err != nil {
+			return nil, err
+		}
+
+		return generator.NewCardGeneratorWithConfig(&generator.Config{
+			ArtProc: art.NewLocalProcessor(source, pipeline, artCfg.EnablePlaceholder),
+			Cache:   renderCache,
+			BackDir: cfg.Generator.BacksPath,
+			Output:  opts,
+		})
 	}); err != nil {
 		return nil, fmt.Errorf("failed to register card generator: %w", err)
 	}
@@ -63,24 +94,74 @@ func NewApplication(env string) (*Application, error) {
 func registerStorage(container di.Container, cfg *config.Config) error {
 	switch cfg.Storage.Type {
 	case "memory":
-		return container.RegisterSingleton("cardStore", func() store.Store {
-			return memory.New()
-		})
+		return registerMemoryStorage(container)
 	case "file":
 		// TODO: Implement file storage
-		return container.RegisterSingleton("cardStore", func() store.Store {
-			return memory.New() // Fallback to memory for now
-		})
+		return registerMemoryStorage(container) // Fallback to memory for now
 	case "database":
-		// TODO: Implement database storage
-		return container.RegisterSingleton("cardStore", func() store.Store {
-			return memory.New() // Fallback to memory for now
-		})
+		return registerDatabaseStorage(container, cfg)
 	default:
 		return fmt.Errorf("unsupported storage type: %s", cfg.Storage.Type)
 	}
 }
 
+// registerMemoryStorage registers in-memory card, deck and tag stores
+func registerMemoryStorage(container di.Container) error {
+	if err := container.RegisterSingleton("cardStore", func() store.Store {
+		return memory.New()
+	}); err != nil {
+		return err
+	}
+	if err := container.RegisterSingleton("deckStore", func() store.DeckStore {
+		return memory.NewDeckStore()
+	}); err != nil {
+		return err
+	}
+	return container.RegisterSingleton("tagStore", func() store.TagStore {
+		return memory.NewTagStore()
+	})
+}
+
+// registerDatabaseStorage registers card, deck and tag stores sharing one PostgreSQL connection pool
+func registerDatabaseStorage(container di.Container, cfg *config.Config) error {
+	if err := container.RegisterSingleton("postgresStore", func() (*database.PostgresStore, error) {
+		pg, err := database.NewPostgresStore(cfg.Database.GetConnectionString())
+		if err != nil {
+			return nil, err
+		}
+		if err := pg.InitSchema(); err != nil {
+			pg.Close()
+			return nil, err
+		}
+		return pg, nil
+	}); err != nil {
+		return err
+	}
+
+	if err := container.RegisterSingleton("cardStore", func() (store.Store, error) {
+		return resolvePostgresStore(container)
+	}); err != nil {
+		return err
+	}
+	if err := container.RegisterSingleton("deckStore", func() (store.DeckStore, error) {
+		return resolvePostgresStore(container)
+	}); err != nil {
+		return err
+	}
+	return container.RegisterSingleton("tagStore", func() (store.TagStore, error) {
+		return resolvePostgresStore(container)
+	})
+}
+
+// resolvePostgresStore resolves the shared PostgreSQL store from the container
+func resolvePostgresStore(container di.Container) (*database.PostgresStore, error) {
+	instance, err := container.Resolve("postgresStore")
+	if err != nil {
+		return nil, err
+	}
+	return instance.(*database.PostgresStore), nil
+}
+
 // createCSVParserFactory creates a CSV parser factory function
 func createCSVParserFactory() func(reader io.Reader) *parser.CSVParser {
 	return func(reader io.Reader) *parser.CSVParser {
@@ -97,6 +178,40 @@ func (app *Application) GetCardStore() (store.Store, error) {
 	return instance.(store.Store), nil
 }
 
+// GetDeckStore resolves the deck store from the container
+func (app *Application) GetDeckStore() (store.DeckStore, error) {
+	instance, err := app.Container.Resolve("deckStore")
+	if err != nil {
+		return nil, err
+	}
+	return instance.(store.DeckStore), nil
+}
+
+// GetTagStore resolves the card tag store from the container
+func (app *Application) GetTagStore() (store.TagStore, error) {
+	instance, err := app.Container.Resolve("tagStore")
+	if err != nil {
+		return nil, err
+	}
+	return instance.(store.TagStore), nil
+}
+
+// NewCardTagger creates a card tagger that reads and writes manual tags in the tag store.
+// With LLM_BASE_URL set, an OpenAI-compatible model suggests additional tags and effect summaries.
+func (app *Application) NewCardTagger() (*tagger.CardTagger, error) {
+	tagStore, err := app.GetTagStore()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tag store: %w", err)
+	}
+
+	cardTagger := tagger.NewCardTagger()
+	cardTagger.SetTagStore(tagStore)
+	if cfg := llm.ConfigFromEnv(); cfg != nil {
+		cardTagger.SetEffectAnalyzer(llm.NewClient(*cfg))
+	}
+	return cardTagger, nil
+}
+
 // GetCardGenerator resolves the card generator from the container
 func (app *Application) GetCardGenerator() (generator.CardGenerator, error) {
 	instance, err := app.Container.Resolve("cardGenerator")
@@ -106,6 +221,26 @@ func (app *Application) GetCardGenerator() (generator.CardGenerator, error) {
 	return instance.(generator.CardGenerator), nil
 }
 
+// OutputOptions returns the configured format, size, quality and DPI of card images
+func (app *Application) OutputOptions() (output.Options, error) {
+	return outputOptions(app.Config.Generator)
+}
+
+// outputOptions maps the generator configuration to image output options
+func outputOptions(cfg config.GeneratorConfig) (output.Options, error) {
+	format, err := output.ParseFormat(cfg.Format)
+	if err != nil {
+		return output.Options{}, err
+	}
+	return output.Options{
+		Format:  format,
+		Width:   cfg.ImageWidth,
+		Height:  cfg.ImageHeight,
+		Quality: cfg.Quality,
+		DPI:     cfg.DPI,
+	}, nil
+}
+
 // GetCSVParser creates a new CSV parser instance
 func (app *Application) GetCSVParser(reader io.Reader) (*parser.CSVParser, error) {
 	factoryInstance, err := app.Container.Resolve("csvParserFactory")
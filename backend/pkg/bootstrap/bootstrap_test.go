@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
 	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator"
 	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
 	"github.com/ControlYourPotatoes/card-generator/backend/pkg/config"
@@ -31,7 +32,7 @@ func TestNewApplication_Success(t *testing.T) {
 
 	// Verify container has expected services
 	services := app.Container.GetRegisteredServices()
-	expectedServices := []string{"config", "cardStore", "cardGenerator", "csvParserFactory"}
+	expectedServices := []string{"config", "cardStore", "deckStore", "tagStore", "cardGenerator", "csvParserFactory"}
 
 	if len(services) != len(expectedServices) {
 		t.Errorf("Expected %d services, got %d", len(expectedServices), len(services))
@@ -97,6 +98,68 @@ func TestGetCardStore_Success(t *testing.T) {
 	}
 }
 
+func TestGetDeckStore_Success(t *testing.T) {
+	app, err := NewApplication("test")
+	if err != nil {
+		t.Fatalf("Failed to create application: %v", err)
+	}
+
+	deckStore, err := app.GetDeckStore()
+	if err != nil {
+		t.Fatalf("Failed to get deck store: %v", err)
+	}
+
+	if deckStore == nil {
+		t.Fatal("Deck store is nil")
+	}
+}
+
+func TestGetTagStore_Success(t *testing.T) {
+	app, err := NewApplication("test")
+	if err != nil {
+		t.Fatalf("Failed to create application: %v", err)
+	}
+
+	tagStore, err := app.GetTagStore()
+	if err != nil {
+		t.Fatalf("Failed to get tag store: %v", err)
+	}
+
+	if tagStore == nil {
+		t.Fatal("Tag store is nil")
+	}
+}
+
+func TestNewCardTagger_UsesTagStore(t *testing.T) {
+	t.Setenv("LLM_BASE_URL", "")
+
+	app, err := NewApplication("test")
+	if err != nil {
+		t.Fatalf("Failed to create application: %v", err)
+	}
+
+	cardTagger, err := app.NewCardTagger()
+	if err != nil {
+		t.Fatalf("Failed to create card tagger: %v", err)
+	}
+
+	if err := cardTagger.AddManualTag("Mend", types.Tag{Name: "STAPLE", Category: types.TagStrategy, Weight: 1}); err != nil {
+		t.Fatalf("Failed to add manual tag: %v", err)
+	}
+
+	tagStore, err := app.GetTagStore()
+	if err != nil {
+		t.Fatalf("Failed to get tag store: %v", err)
+	}
+	tags, err := tagStore.ListTags("Mend")
+	if err != nil {
+		t.Fatalf("Failed to list tags: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Name != "STAPLE" {
+		t.Errorf("Expected the manual tag in the tag store, got %+v", tags)
+	}
+}
+
 func TestGetCardGenerator_Success(t *testing.T) {
 	app, err := NewApplication("test")
 	if err != nil {
@@ -60,12 +60,30 @@ type GeneratorConfig struct {
 	Format         string              `yaml:"format"`
 	TemplatesPath  string              `yaml:"templates_path"`
 	FontsPath      string              `yaml:"fonts_path"`
+	BacksPath      string              `yaml:"backs_path"` // set backs named after the set, plus default.png
 	DefaultFont    string              `yaml:"default_font"`
 	TextRendering  TextRenderingConfig `yaml:"text_rendering"`
 	ArtProcessing  ArtProcessingConfig `yaml:"art_processing"`
 	ParallelJobs   int                 `yaml:"parallel_jobs"`
 	EnableCaching  bool                `yaml:"enable_caching"`
 	CacheDirectory string              `yaml:"cache_directory"`
+	CacheMaxSize   int64               `yaml:"cache_max_size"` // bytes; least recently used renders are evicted beyond it
+	Print          PrintConfig         `yaml:"print"`
+}
+
+// PrintConfig holds the PDF sheet layout; lengths are in inches
+type PrintConfig struct {
+	PageSize    string  `yaml:"page_size"` // letter or a4
+	CardWidth   float64 `yaml:"card_width"`
+	CardHeight  float64 `yaml:"card_height"`
+	Columns     int     `yaml:"columns"`
+	Rows        int     `yaml:"rows"`
+	Bleed       float64 `yaml:"bleed"`
+	Gutter      float64 `yaml:"gutter"`
+	CropMarks   bool    `yaml:"crop_marks"`
+	Duplex      string  `yaml:"duplex"` // none, long-edge or short-edge
+	BackOffsetX float64 `yaml:"back_offset_x"`
+	BackOffsetY float64 `yaml:"back_offset_y"`
 }
 
 // TextRenderingConfig holds text rendering configuration
@@ -80,6 +98,7 @@ type TextRenderingConfig struct {
 
 // ArtProcessingConfig holds art processing configuration
 type ArtProcessingConfig struct {
+	ArtDirectory      string `yaml:"art_directory"`
 	EnablePlaceholder bool   `yaml:"enable_placeholder"`
 	PlaceholderColor  string `yaml:"placeholder_color"`
 	ResizeAlgorithm   string `yaml:"resize_algorithm"`
@@ -160,6 +179,7 @@ func getDefaultConfig() *Config {
 			Format:        "png",
 			TemplatesPath: "./templates",
 			FontsPath:     "./fonts",
+			BacksPath:     "./backs",
 			DefaultFont:   "arial.ttf",
 			TextRendering: TextRenderingConfig{
 				DefaultFontSize: 12.0,
@@ -170,6 +190,7 @@ func getDefaultConfig() *Config {
 				CustomFonts:     make(map[string]string),
 			},
 			ArtProcessing: ArtProcessingConfig{
+				ArtDirectory:      "./art",
 				EnablePlaceholder: true,
 				PlaceholderColor:  "#CCCCCC",
 				ResizeAlgorithm:   "lanczos",
@@ -180,6 +201,17 @@ func getDefaultConfig() *Config {
 			ParallelJobs:   4,
 			EnableCaching:  true,
 			CacheDirectory: "./cache",
+			CacheMaxSize:   512 * 1024 * 1024, // 512MB
+			Print: PrintConfig{
+				PageSize:   "letter",
+				CardWidth:  2.5,
+				CardHeight: 3.5,
+				Columns:    3,
+				Rows:       3,
+				Bleed:      0.125,
+				CropMarks:  true,
+				Duplex:     "none",
+			},
 		},
 		Logging: LoggingConfig{
 			Level:      "info",
@@ -283,6 +315,9 @@ func loadFromEnvironment(config *Config) {
 	if fontsPath := getEnv("FONTS_PATH", ""); fontsPath != "" {
 		config.Generator.FontsPath = fontsPath
 	}
+	if backsPath := getEnv("BACKS_PATH", ""); backsPath != "" {
+		config.Generator.BacksPath = backsPath
+	}
 
 	// Logging configuration
 	if level := getEnv("LOG_LEVEL", ""); level != "" {
@@ -325,6 +360,16 @@ func validateConfig(config *Config) error {
 	if config.Generator.ImageHeight <= 0 {
 		return fmt.Errorf("invalid image height: %d", config.Generator.ImageHeight)
 	}
+	validFormats := []string{"png", "jpeg", "jpg", "webp"}
+	if !contains(validFormats, strings.ToLower(config.Generator.Format)) {
+		return fmt.Errorf("invalid image format: %s", config.Generator.Format)
+	}
+	if config.Generator.Quality < 1 || config.Generator.Quality > 100 {
+		return fmt.Errorf("invalid image quality: %d", config.Generator.Quality)
+	}
+	if config.Generator.DPI < 0 {
+		return fmt.Errorf("invalid DPI: %d", config.Generator.DPI)
+	}
 	if config.Generator.ParallelJobs <= 0 {
 		return fmt.Errorf("parallel jobs must be greater than 0")
 	}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/complexity"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/lint"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/synergy"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// cardRequest mirrors CardDataSchema in api/contracts.ts
+type cardRequest struct {
+	Name     string            `json:"name"`
+	Cost     int               `json:"cost"`
+	CardType string            `json:"card_type"`
+	Effect   string            `json:"effect"`
+	Keywords []string          `json:"keywords"`
+	Metadata map[string]string `json:"metadata"`
+}
+
+// analyzeResponse is the body returned by POST /cards/analyze
+type analyzeResponse struct {
+	Lint         lint.Result           `json:"lint"`
+	Tags         []string              `json:"tags"`
+	SynergyScore float64               `json:"synergyScore"`
+	TribalTags   []string              `json:"tribalTags"`
+	Summary      string                `json:"summary,omitempty"`
+	Complexity   complexity.Complexity `json:"complexity"`
+	Explanations []tagger.Explanation  `json:"explanations,omitempty"`
+	Metadata     map[string]string     `json:"metadata"`
+}
+
+// toDTO converts the API card payload into the core card DTO
+func (req cardRequest) toDTO() (*card.CardDTO, error) {
+	cardType, err := parseCardType(req.CardType)
+	if err != nil {
+		return nil, err
+	}
+
+	return &card.CardDTO{
+		Type:     cardType,
+		Name:     req.Name,
+		Cost:     req.Cost,
+		Effect:   req.Effect,
+		Keywords: req.Keywords,
+		Metadata: req.Metadata,
+	}, nil
+}
+
+// parseCardType maps the lowercase API card type onto card.CardType
+func parseCardType(value string) (card.CardType, error) {
+	switch strings.ToLower(value) {
+	case "creature":
+		return card.TypeCreature, nil
+	case "spell":
+		return card.TypeSpell, nil
+	case "artifact":
+		return card.TypeArtifact, nil
+	case "incantation":
+		return card.TypeIncantation, nil
+	case "anthem":
+		return card.TypeAnthem, nil
+	default:
+		return "", fmt.Errorf("unsupported card_type: %s", value)
+	}
+}
+
+// analyzeHandler lints the submitted card's effect text against the style guide, tags it and
+// scores its synergy with the stored cards.
+// With ?explain=true the response also says which rule and text span produced each tag.
+func analyzeHandler(linter *lint.Linter, cardTagger *tagger.CardTagger, cardStore store.Store) http.HandlerFunc {
+	analyzer := synergy.NewAnalyzer(cardTagger)
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req cardRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid JSON body: "+err.Error())
+			return
+		}
+		if req.Name == "" || req.Effect == "" {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", "name and effect are required")
+			return
+		}
+
+		dto, err := req.toDTO()
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+
+		explanations, err := cardTagger.ExplainTagsContext(r.Context(), dto)
+		if err != nil {
+			writeError(w, r, http.StatusUnprocessableEntity, "TAGGING_FAILED", err.Error())
+			return
+		}
+
+		score, err := analyzer.CardScore(dto, cardStore)
+		if err != nil {
+			log.Error().Err(err).Str("request_id", middleware.GetReqID(r.Context())).Msg("Synergy scoring failed")
+			writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to score card synergy")
+			return
+		}
+
+		resp := analyzeResponse{
+			Lint:         linter.Check(dto),
+			Tags:         make([]string, 0, len(explanations)),
+			SynergyScore: score,
+			TribalTags:   make([]string, 0),
+			Complexity:   complexity.Analyze(dto.Effect),
+			Metadata:     make(map[string]string, len(dto.Metadata)),
+		}
+		for key, value := range dto.Metadata {
+			resp.Metadata[key] = value
+		}
+		for _, explanation := range explanations {
+			resp.Tags = append(resp.Tags, explanation.Tag.Name)
+			if explanation.Tag.Category == types.TagTribal {
+				resp.TribalTags = append(resp.TribalTags, explanation.Tag.Name)
+			}
+		}
+		if explain, _ := strconv.ParseBool(r.URL.Query().Get("explain")); explain {
+			resp.Explanations = explanations
+		}
+		if summary, err := cardTagger.SummarizeEffect(r.Context(), dto); err != nil {
+			log.Warn().Err(err).Str("request_id", middleware.GetReqID(r.Context())).Msg("Effect summary failed")
+		} else {
+			resp.Summary = summary
+		}
+
+		log.Info().
+			Str("request_id", middleware.GetReqID(r.Context())).
+			Str("card", dto.Name).
+			Int("lint_issues", len(resp.Lint.Issues)).
+			Int("tags", len(resp.Tags)).
+			Msg("Analyzed card")
+
+		writeJSON(w, r, http.StatusOK, resp)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/archetype"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+)
+
+// archetypeRequest is the body of POST /decks/archetype; a card listed twice counts as two copies
+type archetypeRequest struct {
+	Cards []cardRequest `json:"cards"`
+}
+
+// archetypeHandler classifies the submitted cards as aggro, control, midrange or combo
+func archetypeHandler(cardTagger *tagger.CardTagger) http.HandlerFunc {
+	classifier := archetype.NewClassifier(cardTagger)
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req archetypeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid JSON body: "+err.Error())
+			return
+		}
+		if len(req.Cards) == 0 {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", "at least one card is required")
+			return
+		}
+
+		cards := make([]*card.CardDTO, 0, len(req.Cards))
+		for _, c := range req.Cards {
+			dto, err := c.toDTO()
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+				return
+			}
+			cards = append(cards, dto)
+		}
+
+		result, err := classifier.Classify(cards)
+		if err != nil {
+			writeError(w, r, http.StatusUnprocessableEntity, "CLASSIFICATION_FAILED", err.Error())
+			return
+		}
+
+		log.Info().
+			Str("request_id", middleware.GetReqID(r.Context())).
+			Int("cards", len(cards)).
+			Str("archetype", string(result.Archetype)).
+			Msg("Classified deck archetype")
+
+		writeJSON(w, r, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/similarity"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// Bounds of the duplicates query: low thresholds pair up most of the pool, so the
+// threshold has a floor and the number of returned pairs is capped
+const (
+	minDuplicateThreshold = 0.5
+	defaultDuplicatePairs = 100
+	maxDuplicatePairs     = 1000
+)
+
+// duplicatesHandler reports clusters of near-duplicate cards in the store.
+// The optional threshold query parameter overrides the default score cutoff and
+// limit caps the returned pairs, highest scores first.
+func duplicatesHandler(cardStore store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		options := similarity.DefaultOptions()
+		options.MaxPairs = defaultDuplicatePairs
+		if value := r.URL.Query().Get("threshold"); value != "" {
+			threshold, err := strconv.ParseFloat(value, 64)
+			if err != nil || threshold < minDuplicateThreshold || threshold > 1 {
+				writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("threshold must be a number between %.1f and 1", minDuplicateThreshold))
+				return
+			}
+			options.Threshold = threshold
+		}
+		if value := r.URL.Query().Get("limit"); value != "" {
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 1 || limit > maxDuplicatePairs {
+				writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("limit must be an integer between 1 and %d", maxDuplicatePairs))
+				return
+			}
+			options.MaxPairs = limit
+		}
+
+		result, err := similarity.NewEngine(nil, options).FindInStore(cardStore)
+		if err != nil {
+			log.Error().Err(err).Str("request_id", middleware.GetReqID(r.Context())).Msg("Duplicate detection failed")
+			writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to compare cards")
+			return
+		}
+
+		writeJSON(w, r, http.StatusOK, result)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/similarity"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/memory"
+)
+
+func TestDuplicatesHandler(t *testing.T) {
+	cardStore := memory.New()
+	for _, dto := range []*card.CardDTO{
+		{Type: card.TypeSpell, Name: "Smite", Cost: 2, Effect: "Destroy target creature."},
+		{Type: card.TypeSpell, Name: "Execute", Cost: 2, Effect: "Destroy target creature."},
+		{Type: card.TypeSpell, Name: "Research", Cost: 2, Effect: "Draw 2 cards."},
+		{Type: card.TypeSpell, Name: "Study", Cost: 2, Effect: "Draw 2 cards."},
+	} {
+		if _, err := cardStore.Save(card.NewSpellFromDTO(dto)); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+	}
+	handler := duplicatesHandler(cardStore)
+
+	tests := []struct {
+		name          string
+		query         string
+		wantStatus    int
+		wantPairs     int
+		wantTruncated bool
+	}{
+		{"Defaults", "", http.StatusOK, 2, false},
+		{"Limited", "?limit=1", http.StatusOK, 1, true},
+		{"Threshold below the floor", "?threshold=0", http.StatusBadRequest, 0, false},
+		{"Threshold above 1", "?threshold=1.5", http.StatusBadRequest, 0, false},
+		{"Limit of zero", "?limit=0", http.StatusBadRequest, 0, false},
+		{"Limit above the maximum", "?limit=100000", http.StatusBadRequest, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler(rec, httptest.NewRequest(http.MethodGet, "/cards/duplicates"+tt.query, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var result similarity.Result
+			if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+				t.Fatalf("invalid response: %v", err)
+			}
+			if len(result.Pairs) != tt.wantPairs || result.Truncated != tt.wantTruncated {
+				t.Errorf("got %d pairs (truncated %v), want %d (truncated %v)", len(result.Pairs), result.Truncated, tt.wantPairs, tt.wantTruncated)
+			}
+		})
+	}
+}
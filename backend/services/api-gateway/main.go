@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"os"
@@ -11,6 +12,14 @@ import (
 	"github.com/go-chi/cors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/lint"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/llm"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/rules"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+	"github.com/ControlYourPotatoes/card-generator/backend/pkg/bootstrap"
 )
 
 func main() {
@@ -18,6 +27,51 @@ func main() {
 	zerolog.TimeFieldFormat = zerolog.TimeFormatUnix
 	log.Logger = zerolog.New(zerolog.ConsoleWriter{Out: os.Stderr}).With().Timestamp().Logger()
 
+	linter, err := newLinter()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load effect text style guide")
+	}
+
+	taxonomy, err := newTaxonomy()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to load tag taxonomy")
+	}
+
+	app, err := bootstrap.NewApplication(getEnv("APP_ENV", "development"))
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to initialize application")
+	}
+	defer func() {
+		if err := app.Shutdown(); err != nil {
+			log.Error().Err(err).Msg("Error during shutdown")
+		}
+	}()
+
+	cardStore, err := app.GetCardStore()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get card store")
+	}
+
+	tagStore, err := app.GetTagStore()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get tag store")
+	}
+
+	cardGenerator, err := app.GetCardGenerator()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to get card generator")
+	}
+
+	outputOpts, err := app.OutputOptions()
+	if err != nil {
+		log.Fatal().Err(err).Msg("Invalid output configuration")
+	}
+
+	cardTagger, err := newTagger(context.Background(), app, cardStore, taxonomy)
+	if err != nil {
+		log.Fatal().Err(err).Msg("Failed to set up card tagger")
+	}
+
 	r := chi.NewRouter()
 
 	// Middleware
@@ -41,12 +95,20 @@ func main() {
 
 		// Stub endpoints (501 Not Implemented until services ready)
 		r.Post("/cards/generate", stubHandler("card-generator"))
+		r.Get("/cards/duplicates", duplicatesHandler(cardStore))
 		r.Get("/cards/{id}", stubHandler("card-generator"))
-		r.Get("/cards/{id}/render", stubImageHandler("image-renderer"))
-		r.Post("/cards/analyze", stubHandler("card-generator"))
+		r.Get("/cards/{id}/tags", cardTagsHandler(cardStore, tagStore))
+		r.Post("/cards/{id}/tags", addTagHandler(cardTagger, cardStore))
+		r.Get("/tags/cards", findByTagsHandler(tagStore, taxonomy))
+		r.Get("/tags/taxonomy", taxonomyHandler(taxonomy))
+		r.Get("/cards/{id}/render", renderHandler(cardStore, cardGenerator, outputOpts))
+		r.Post("/cards/render/batch", batchRenderHandler(cardGenerator, app.Config.Generator.ParallelJobs, outputOpts))
+		r.Post("/cards/analyze", analyzeHandler(linter, cardTagger, cardStore))
+		r.Post("/decks/archetype", archetypeHandler(cardTagger))
 		r.Post("/import/csv", stubHandler("importer"))
 		r.Get("/import/{jobId}/status", stubHandler("importer"))
 		r.Delete("/admin/cards", stubHandler("card-generator"))
+		r.Post("/admin/retag", retagHandler(cardTagger, cardStore))
 	})
 
 	log.Info().Msg("API Gateway starting on :8080")
@@ -65,6 +127,71 @@ func main() {
 	}
 }
 
+// newLinter builds the effect text linter, using LINT_STYLE_GUIDE when set
+func newLinter() (*lint.Linter, error) {
+	guide := lint.DefaultStyleGuide()
+	if path := os.Getenv("LINT_STYLE_GUIDE"); path != "" {
+		var err error
+		guide, err = lint.LoadStyleGuide(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lint.NewLinter(guide)
+}
+
+// newTaxonomy loads the tag taxonomy from TAG_TAXONOMY_PATH, or uses the default taxonomy when unset
+func newTaxonomy() (*types.Taxonomy, error) {
+	if path := os.Getenv("TAG_TAXONOMY_PATH"); path != "" {
+		return rules.LoadTaxonomy(path)
+	}
+	return types.DefaultTaxonomy(), nil
+}
+
+// newTagger builds the application's card tagger, validating tags against the taxonomy and merging and
+// watching the rule files in TAG_RULES_PATH when set. Cards are re-tagged into the tag store whenever the rules change.
+func newTagger(ctx context.Context, app *bootstrap.Application, cardStore store.Store, taxonomy *types.Taxonomy) (*tagger.CardTagger, error) {
+	cardTagger, err := app.NewCardTagger()
+	if err != nil {
+		return nil, err
+	}
+	if err := cardTagger.SetTaxonomy(taxonomy); err != nil {
+		return nil, err
+	}
+
+	retag := func() {
+		result, err := cardTagger.Retag(ctx, cardStore, false)
+		if err != nil {
+			log.Error().Err(err).Msg("Re-tagging cards failed")
+			return
+		}
+		log.Info().Str("rules_version", result.RulesVersion).Int("retagged", result.Retagged).Msg("Re-tagged cards")
+	}
+	cardTagger.OnRulesReloaded(retag)
+
+	if cfg := llm.ConfigFromEnv(); cfg != nil {
+		log.Info().Str("model", cfg.Model).Msg("Using model-assisted effect analysis")
+	}
+
+	if path := os.Getenv("TAG_RULES_PATH"); path != "" {
+		if err := cardTagger.WatchRules(ctx, path, 30*time.Second); err != nil {
+			return nil, err
+		}
+		log.Info().Str("path", path).Msg("Watching tag rule files")
+	}
+
+	go retag()
+	return cardTagger, nil
+}
+
+// getEnv returns the environment variable or a default when unset
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
+
 func healthHandler(w http.ResponseWriter, r *http.Request) {
 	resp := map[string]interface{}{
 		"status": "ok",
@@ -89,14 +216,3 @@ func stubHandler(service string) http.HandlerFunc {
 		http.Error(w, `{"error":"Service `+service+` not implemented (Phase 2)","code":"NOT_IMPLEMENTED"}`, http.StatusNotImplemented)
 	}
 }
-
-func stubImageHandler(service string) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		reqID := middleware.GetReqID(r.Context())
-		log.Info().Str("request_id", reqID).Str("service", service).Msg("Image stub")
-
-		w.Header().Set("X-Request-ID", reqID)
-		w.Header().Set("Content-Type", "image/png")
-		http.Error(w, "Image renderer not implemented", http.StatusNotImplemented)
-	}
-}
\ No newline at end of file
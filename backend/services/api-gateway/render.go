@@ -0,0 +1,248 @@
+package main
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/art"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/templates/types"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// maxBatchRender caps the cards rendered by one request so it finishes within the server's write timeout
+const maxBatchRender = 50
+
+// batchRenderRequest is the body of POST /cards/render/batch
+type batchRenderRequest struct {
+	Cards []cardRequest `json:"cards"`
+}
+
+// batchRenderEntry describes one card of the batch in the archive's manifest
+type batchRenderEntry struct {
+	Name  string `json:"name"`
+	File  string `json:"file,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// batchRenderHandler renders the submitted cards concurrently in memory and responds with a
+// zip of their images and a manifest.json listing each card's file or error
+func batchRenderHandler(cardGenerator generator.CardGenerator, workers int, opts output.Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetReqID(r.Context())
+
+		var req batchRenderRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid JSON body: "+err.Error())
+			return
+		}
+		if len(req.Cards) == 0 || len(req.Cards) > maxBatchRender {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", fmt.Sprintf("between 1 and %d cards are required", maxBatchRender))
+			return
+		}
+
+		names := make([]string, len(req.Cards))
+		images := make([]bytes.Buffer, len(req.Cards))
+		jobs := make([]generator.BatchJob, 0, len(req.Cards))
+		for i, c := range req.Cards {
+			dto, err := c.toDTO()
+			if err == nil {
+				err = checkFileMetadata(dto)
+			}
+			if err != nil {
+				writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+				return
+			}
+			names[i] = fmt.Sprintf("%03d-%s%s", i+1, art.Slug(dto.Name), opts.Format.Extension())
+			jobs = append(jobs, generator.BatchJob{Card: dto, Writer: &images[i], Options: opts})
+		}
+
+		renderer := generator.NewBatchRenderer(cardGenerator, workers).OnProgress(func(p generator.Progress) {
+			log.Debug().Str("request_id", reqID).Int("done", p.Done).Int("total", p.Total).Msg("Rendered card")
+		})
+		results, err := renderer.Render(r.Context(), jobs)
+		if err != nil {
+			log.Warn().Err(err).Str("request_id", reqID).Msg("Batch render cancelled")
+			return
+		}
+
+		failed := generator.Failed(results)
+		log.Info().
+			Str("request_id", reqID).
+			Int("cards", len(results)).
+			Int("failed", len(failed)).
+			Msg("Rendered card batch")
+
+		if len(failed) == len(results) {
+			writeJSON(w, r, http.StatusUnprocessableEntity, map[string]interface{}{
+				"error":   "no cards rendered",
+				"code":    "RENDER_FAILED",
+				"results": manifest(results, names),
+			})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/zip")
+		w.Header().Set("Content-Disposition", `attachment; filename="cards.zip"`)
+		w.Header().Set("X-Request-ID", reqID)
+		if err := writeArchive(w, results, names, images); err != nil {
+			log.Error().Err(err).Str("request_id", reqID).Msg("Failed to write render archive")
+		}
+	}
+}
+
+// checkFileMetadata rejects art and back metadata naming files outside the art and backs directories
+func checkFileMetadata(dto *card.CardDTO) error {
+	for _, key := range []string{art.MetadataArtKey, types.MetadataBackKey} {
+		value := strings.TrimSpace(dto.Metadata[key])
+		if value != "" && (filepath.IsAbs(value) || !filepath.IsLocal(filepath.Clean(value))) {
+			return fmt.Errorf("metadata %s must be a path inside its directory: %s", key, value)
+		}
+	}
+	return nil
+}
+
+// renderHandler streams the image of a stored card. The query may override the configured
+// format, width, height, quality and dpi. Renders are served from the generator's cache when
+// the card, its art, the templates and the options are unchanged.
+func renderHandler(cardStore store.Store, cardGenerator generator.CardGenerator, defaults output.Options) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		reqID := middleware.GetReqID(r.Context())
+
+		if r.URL.Query().Get("format") == "svg" {
+			writeError(w, r, http.StatusNotImplemented, "NOT_IMPLEMENTED", "svg rendering is not implemented")
+			return
+		}
+		opts, err := renderOptions(r, defaults)
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		c, err := cardStore.Load(id)
+		if err != nil {
+			writeError(w, r, http.StatusNotFound, "NOT_FOUND", "card not found: "+id)
+			return
+		}
+
+		// Encode fully before responding so a failed render can still be reported as an error
+		var buf bytes.Buffer
+		if err := cardGenerator.RenderTo(&buf, c.ToDTO(), opts); err != nil {
+			log.Error().Err(err).Str("request_id", reqID).Str("card_id", id).Msg("Render failed")
+			writeError(w, r, http.StatusUnprocessableEntity, "RENDER_FAILED", err.Error())
+			return
+		}
+
+		w.Header().Set("Content-Type", opts.Format.ContentType())
+		w.Header().Set("X-Request-ID", reqID)
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(buf.Bytes()))
+	}
+}
+
+// renderOptions applies the format, width, height, quality and dpi query parameters to the defaults
+func renderOptions(r *http.Request, defaults output.Options) (output.Options, error) {
+	query := r.URL.Query()
+	opts := defaults
+	if name := query.Get("format"); name != "" {
+		format, err := output.ParseFormat(name)
+		if err != nil {
+			return opts, err
+		}
+		opts.Format = format
+	}
+	if opts.Format == "" {
+		opts.Format = output.FormatPNG
+	}
+
+	for _, param := range []struct {
+		name  string
+		value *int
+	}{
+		{"width", &opts.Width},
+		{"height", &opts.Height},
+		{"quality", &opts.Quality},
+		{"dpi", &opts.DPI},
+	} {
+		raw := query.Get(param.name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return opts, fmt.Errorf("invalid %s: %s", param.name, raw)
+		}
+		*param.value = v
+	}
+	// A single dimension scales the other to keep the card's proportions
+	if query.Get("width") != "" && query.Get("height") == "" {
+		opts.Height = 0
+	} else if query.Get("height") != "" && query.Get("width") == "" {
+		opts.Width = 0
+	}
+	return opts, opts.Validate()
+}
+
+// manifest lists each result's archive file, or its error when the card failed
+func manifest(results []generator.BatchResult, names []string) []batchRenderEntry {
+	entries := make([]batchRenderEntry, 0, len(results))
+	for _, result := range results {
+		entry := batchRenderEntry{Name: result.Name}
+		if result.Err != nil {
+			entry.Error = result.Err.Error()
+		} else {
+			entry.File = names[result.Index]
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// writeArchive streams a zip of the rendered images followed by manifest.json
+func writeArchive(w io.Writer, results []generator.BatchResult, names []string, images []bytes.Buffer) error {
+	zw := zip.NewWriter(w)
+	now := time.Now()
+	for _, result := range results {
+		if result.Err != nil {
+			continue
+		}
+		if err := addFile(zw, names[result.Index], images[result.Index].Bytes(), now); err != nil {
+			return err
+		}
+	}
+
+	mw, err := zw.CreateHeader(&zip.FileHeader{Name: "manifest.json", Method: zip.Deflate, Modified: now})
+	if err != nil {
+		return fmt.Errorf("failed to add manifest: %w", err)
+	}
+	if err := json.NewEncoder(mw).Encode(manifest(results, names)); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	return zw.Close()
+}
+
+// addFile adds an image to the archive. Images are already compressed, so it is stored as is.
+func addFile(zw *zip.Writer, name string, data []byte, modified time.Time) error {
+	fw, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Store, Modified: modified})
+	if err != nil {
+		return fmt.Errorf("failed to add %s: %w", name, err)
+	}
+	if _, err := fw.Write(data); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/core/card"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/generator/output"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/storage/memory"
+)
+
+func TestCheckFileMetadata(t *testing.T) {
+	tests := []struct {
+		name     string
+		metadata map[string]string
+		wantErr  bool
+	}{
+		{"No file metadata", map[string]string{"set": "Core Set"}, false},
+		{"Art inside the art directory", map[string]string{"art": "goblins/scout.png"}, false},
+		{"Art leaving the art directory", map[string]string{"art": "../secret.png"}, true},
+		{"Absolute art", map[string]string{"art": "/etc/passwd"}, true},
+		{"Back leaving the backs directory", map[string]string{"back": "sets/../../../etc/hosts"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkFileMetadata(&card.CardDTO{Name: "Test", Metadata: tt.metadata})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkFileMetadata() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRenderHandlerRejectsOversizedOutput(t *testing.T) {
+	router := chi.NewRouter()
+	router.Get("/cards/{id}/render", renderHandler(memory.New(), nil, output.Options{Format: output.FormatPNG}))
+
+	tests := []struct {
+		name   string
+		query  string
+		status int
+	}{
+		{"Huge width and height", "width=100000&height=100000", http.StatusBadRequest},
+		{"Side over the limit", "width=20000", http.StatusBadRequest},
+		{"Pixel budget exceeded", "width=10000&height=10000", http.StatusBadRequest},
+		{"DPI over the limit", "dpi=100000", http.StatusBadRequest},
+		{"Size within the limits", "width=750&height=1050", http.StatusNotFound}, // validated, then the card is missing
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cards/missing/render?"+tt.query, nil))
+			if rec.Code != tt.status {
+				t.Errorf("status = %d, want %d: %s", rec.Code, tt.status, rec.Body.String())
+			}
+		})
+	}
+}
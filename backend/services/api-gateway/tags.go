@@ -0,0 +1,144 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/rs/zerolog/log"
+
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/tagger"
+	"github.com/ControlYourPotatoes/card-generator/backend/internal/analysis/types"
+	store "github.com/ControlYourPotatoes/card-generator/backend/internal/storage"
+)
+
+// tagRequest is the body of POST /cards/{id}/tags
+type tagRequest struct {
+	Name     string `json:"name"`
+	Category string `json:"category"`
+	Weight   int    `json:"weight"`
+}
+
+// cardTagsHandler lists the stored auto and manual tags of a card
+func cardTagsHandler(cardStore store.Store, tagStore store.TagStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := chi.URLParam(r, "id")
+		c, err := cardStore.Load(id)
+		if err != nil {
+			writeError(w, r, http.StatusNotFound, "NOT_FOUND", "card not found: "+id)
+			return
+		}
+
+		tags, err := tagStore.ListTags(tagger.CardKey(c.ToDTO()))
+		if err != nil {
+			log.Error().Err(err).Str("request_id", middleware.GetReqID(r.Context())).Msg("Listing card tags failed")
+			writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to list card tags")
+			return
+		}
+		writeJSON(w, r, http.StatusOK, map[string]interface{}{"tags": tags})
+	}
+}
+
+// addTagHandler adds a manual tag to a card, rejecting tags the tagger's taxonomy does not accept
+func addTagHandler(cardTagger *tagger.CardTagger, cardStore store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req tagRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", "invalid JSON body: "+err.Error())
+			return
+		}
+
+		tag := types.Tag{
+			Name:     strings.TrimSpace(req.Name),
+			Category: types.TagCategory(strings.ToUpper(req.Category)),
+			Weight:   req.Weight,
+		}
+		if err := cardTagger.ValidateTag(tag); err != nil {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+
+		id := chi.URLParam(r, "id")
+		c, err := cardStore.Load(id)
+		if err != nil {
+			writeError(w, r, http.StatusNotFound, "NOT_FOUND", "card not found: "+id)
+			return
+		}
+
+		if err := cardTagger.AddCardTag(c, tag); err != nil {
+			log.Error().Err(err).Str("request_id", middleware.GetReqID(r.Context())).Msg("Adding manual tag failed")
+			writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to add tag")
+			return
+		}
+		writeJSON(w, r, http.StatusCreated, tag)
+	}
+}
+
+// findByTagsHandler returns the IDs of cards matching comma-separated all, any and none tag lists.
+// The optional source parameter restricts matching to auto or manual tags.
+// Synonyms resolve through the taxonomy, and a parent tag also matches its descendants.
+func findByTagsHandler(tagStore store.TagStore, taxonomy *types.Taxonomy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		source, err := types.ParseTagSource(params.Get("source"))
+		if err != nil {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", err.Error())
+			return
+		}
+
+		query := types.TagQuery{
+			All:    splitTags(params.Get("all")),
+			Any:    splitTags(params.Get("any")),
+			None:   splitTags(params.Get("none")),
+			Source: source,
+		}
+		if len(query.All) == 0 && len(query.Any) == 0 {
+			writeError(w, r, http.StatusBadRequest, "INVALID_REQUEST", "at least one of all or any is required")
+			return
+		}
+
+		ids, err := tagStore.FindCards(taxonomy.ExpandQuery(query))
+		if err != nil {
+			log.Error().Err(err).Str("request_id", middleware.GetReqID(r.Context())).Msg("Tag query failed")
+			writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to query tags")
+			return
+		}
+		writeJSON(w, r, http.StatusOK, map[string]interface{}{"cards": ids})
+	}
+}
+
+// taxonomyHandler returns the tag taxonomy as a tree of root tags and their descendants
+func taxonomyHandler(taxonomy *types.Taxonomy) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, r, http.StatusOK, map[string]interface{}{"tags": taxonomy.Tree()})
+	}
+}
+
+// retagHandler refreshes stored auto tags generated by an older rule version; ?force=true re-tags every card
+func retagHandler(cardTagger *tagger.CardTagger, cardStore store.Store) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		force, _ := strconv.ParseBool(r.URL.Query().Get("force"))
+
+		result, err := cardTagger.Retag(r.Context(), cardStore, force)
+		if err != nil {
+			log.Error().Err(err).Str("request_id", middleware.GetReqID(r.Context())).Msg("Re-tag failed")
+			writeError(w, r, http.StatusInternalServerError, "INTERNAL_ERROR", "failed to re-tag cards")
+			return
+		}
+		writeJSON(w, r, http.StatusOK, result)
+	}
+}
+
+// splitTags parses a comma-separated list of tag names
+func splitTags(value string) []string {
+	var tags []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tags = append(tags, name)
+		}
+	}
+	return tags
+}